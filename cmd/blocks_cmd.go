@@ -21,10 +21,28 @@ var BlocksCommand = &cli.Command{
 	Flags: []cli.Flag{
 		&cli.StringFlag{
 			Name:        "bn-endpoint",
-			Usage:       "Beacon node endpoint (to request the Beacon States and Blocks)",
+			Usage:       "Beacon node endpoint (to request the Beacon States and Blocks). Accepts a comma separated list to fail over between nodes",
 			EnvVars:     []string{"ANALYZER_BN_ENDPOINT"},
 			DefaultText: "http://localhost:5052",
 		},
+		&cli.BoolFlag{
+			Name:        "bn-consistency-checks",
+			Usage:       "Compare downloaded block and state roots across all the beacon nodes and report mismatches",
+			EnvVars:     []string{"ANALYZER_BN_CONSISTENCY_CHECKS"},
+			DefaultText: "false",
+		},
+		&cli.BoolFlag{
+			Name:        "bn-ssz",
+			Usage:       "Download the Beacon States and Blocks SSZ encoded (application/octet-stream) instead of JSON",
+			EnvVars:     []string{"ANALYZER_BN_SSZ"},
+			DefaultText: "true",
+		},
+		&cli.BoolFlag{
+			Name:        "bn-non-archival",
+			Usage:       "Build the Beacon States from the per-resource endpoints (validators, committees, finality...) so that a non-archival beacon node can be used",
+			EnvVars:     []string{"ANALYZER_BN_NON_ARCHIVAL"},
+			DefaultText: "false",
+		},
 		&cli.StringFlag{
 			Name:        "bn-api-key",
 			Usage:       "Beacon node API key",
@@ -67,6 +85,24 @@ var BlocksCommand = &cli.Command{
 			EnvVars:     []string{"ANALYZER_REWARDS_AGGREGATION_EPOCHS"},
 			DefaultText: "1 (no aggregation)",
 		},
+		&cli.StringFlag{
+			Name:        "rewards-aggregation-levels",
+			Usage:       "Comma separated list of additional rewards aggregation levels as epochs:group, group being validator or entity. Example: 1575:validator,225:entity",
+			EnvVars:     []string{"ANALYZER_REWARDS_AGGREGATION_LEVELS"},
+			DefaultText: "",
+		},
+		&cli.IntFlag{
+			Name:        "backfill-shard-epochs",
+			Usage:       "Split the historical range in shards of this many epochs, leased through the database so that several instances can backfill in parallel",
+			EnvVars:     []string{"ANALYZER_BACKFILL_SHARD_EPOCHS"},
+			DefaultText: "0 (no sharding)",
+		},
+		&cli.StringFlag{
+			Name:        "backfill-worker-id",
+			Usage:       "Identifier of this instance in the backfill leases table",
+			EnvVars:     []string{"ANALYZER_BACKFILL_WORKER_ID"},
+			DefaultText: "hostname-pid",
+		},
 		&cli.StringFlag{
 			Name:        "log-level",
 			Usage:       "Log level: debug, warn, info, error",
@@ -99,7 +135,7 @@ var BlocksCommand = &cli.Command{
 		},
 		&cli.StringFlag{
 			Name:        "metrics",
-			Usage:       "Metrics to be persisted to the database: epoch,block,rewards,transactions,api_rewards,blob_sidecars",
+			Usage:       "Metrics to be persisted to the database: epoch,block,rewards,transactions,api_rewards,blob_sidecars,data_columns,logs,traces",
 			EnvVars:     []string{"ANALYZER_METRICS"},
 			DefaultText: "epoch,block",
 		},
@@ -121,6 +157,54 @@ var BlocksCommand = &cli.Command{
 			EnvVars:     []string{"ANALYZER_BEACON_CONTRACT_ADDRESS"},
 			DefaultText: "mainnet",
 		},
+		&cli.StringFlag{
+			Name:        "builders-file",
+			Usage:       "JSON file mapping extra data patterns, fee recipients and builder pubkeys to builder names, used to identify who built each block",
+			EnvVars:     []string{"ANALYZER_BUILDERS_FILE"},
+			DefaultText: "",
+		},
+		&cli.StringFlag{
+			Name:        "compliance-list",
+			Usage:       "File with one execution address per line. Blocks including transactions that touch any of them are tagged as non-compliant",
+			EnvVars:     []string{"ANALYZER_COMPLIANCE_LIST"},
+			DefaultText: "",
+		},
+		&cli.StringFlag{
+			Name:        "abi-dir",
+			Usage:       "Directory of JSON ABIs to decode the receipt logs with, on top of the ERC-20 and ERC-721 ones (needs the logs metric)",
+			EnvVars:     []string{"ANALYZER_ABI_DIR"},
+			DefaultText: "",
+		},
+		&cli.IntFlag{
+			Name:        "trace-concurrency",
+			Usage:       "Number of blocks traced at the same time through debug_traceBlockByHash (needs the traces metric)",
+			EnvVars:     []string{"ANALYZER_TRACE_CONCURRENCY"},
+			DefaultText: "2",
+		},
+		&cli.StringFlag{
+			Name:        "rollups-file",
+			Usage:       "JSON file mapping the sender and recipient addresses of blob transactions to rollup names, to aggregate their blob usage per epoch (needs the transactions metric)",
+			EnvVars:     []string{"ANALYZER_ROLLUPS_FILE"},
+			DefaultText: "",
+		},
+		&cli.BoolFlag{
+			Name:        "verify-kzg",
+			Usage:       "Verify the downloaded blobs against their KZG commitments, proofs (before Fulu) and the versioned hashes of the block transactions (needs the blob_sidecars metric)",
+			EnvVars:     []string{"ANALYZER_VERIFY_KZG"},
+			DefaultText: "false",
+		},
+		&cli.StringFlag{
+			Name:        "blob-store",
+			Usage:       "Directory or S3 compatible bucket (s3://bucket/prefix?region=...&endpoint=...) to write the blob contents to, addressed by versioned hash (needs the blob_sidecars metric)",
+			EnvVars:     []string{"ANALYZER_BLOB_STORE"},
+			DefaultText: "",
+		},
+		&cli.StringFlag{
+			Name:        "graffiti-rules",
+			Usage:       "JSON file with the rules parsing the consensus and execution clients from the block graffiti, replacing the built-in ones",
+			EnvVars:     []string{"ANALYZER_GRAFFITI_RULES"},
+			DefaultText: "",
+		},
 	},
 }
 
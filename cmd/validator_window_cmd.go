@@ -16,7 +16,7 @@ import (
 
 var ValidatorWindowCommand = &cli.Command{
 	Name:   "val-window",
-	Usage:  "Removes old rows from the validator rewards table according to given parameters",
+	Usage:  "Removes old rows from the validator rewards table and any other table with a configured retention",
 	Action: LaunchValidatorWindow,
 	Flags: []cli.Flag{
 		&cli.StringFlag{
@@ -37,6 +37,18 @@ var ValidatorWindowCommand = &cli.Command{
 			EnvVars:     []string{"NUM_EPOCHS"},
 			DefaultText: "100",
 		},
+		&cli.StringFlag{
+			Name:        "retention",
+			Usage:       "Comma separated list of table=retention, in epochs or in days with the d suffix. Example: t_transactions=30d,t_head_events=1000",
+			EnvVars:     []string{"ANALYZER_RETENTION"},
+			DefaultText: "",
+		},
+		&cli.BoolFlag{
+			Name:        "dry-run",
+			Usage:       "Print what would be removed from each table and the expected space freed, without removing anything",
+			EnvVars:     []string{"ANALYZER_RETENTION_DRY_RUN"},
+			DefaultText: "false",
+		},
 		&cli.StringFlag{
 			Name:        "bn-endpoint",
 			Usage:       "Beacon node endpoint (to request the Beacon States and Blocks)",
@@ -0,0 +1,233 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+)
+
+var (
+	// a lease not renewed for this long can be taken over by another instance
+	backfillLeaseTTL = 10 * time.Minute
+	// time given to concurrent claims to land before reading who won
+	backfillClaimSettle = 2 * time.Second
+)
+
+// backfillLeases is where the instances sharing a backfill claim their shards, the database.
+type backfillLeases interface {
+	PersistBackfillLease(lease db.BackfillLease) error
+	RetrieveBackfillLeases(from phase0.Epoch, to phase0.Epoch) (map[phase0.Epoch]db.BackfillLease, error)
+}
+
+// backfillShard is a range of epochs [start, end) backfilled by a single instance.
+type backfillShard struct {
+	start phase0.Epoch
+	end   phase0.Epoch
+}
+
+func defaultBackfillWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "goteth"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// backfillShards splits [first, end) in shards of size epochs aligned to multiples of size,
+// so the first and last ones may be shorter. Being a multiple of the rewards aggregation
+// epochs, shard boundaries are also aggregation window boundaries.
+func backfillShards(first phase0.Epoch, end phase0.Epoch, size int) []backfillShard {
+	shards := make([]backfillShard, 0)
+	if size <= 0 {
+		return shards
+	}
+	for start := first; start < end; {
+		next := (start/phase0.Epoch(size) + 1) * phase0.Epoch(size)
+		shards = append(shards, backfillShard{
+			start: start,
+			end:   min(next, end),
+		})
+		start = next
+	}
+	return shards
+}
+
+// runShardedHistorical backfills the requested range shard by shard, claiming each
+// shard in t_backfill_leases so that several instances can share the range.
+// A shard left unfinished (the instance crashed or was stopped) is taken over
+// once its lease expires.
+func (s *ChainAnalyzer) runShardedHistorical() {
+	defer s.wgMainRoutine.Done()
+
+	log.Infof("launching sharded backfill of epochs %d - %d in shards of %d epochs as %s",
+		s.backfillFirstEpoch, s.backfillEndEpoch-1, s.backfillShardEpochs, s.backfillWorkerID)
+
+	for {
+		if s.stop || s.ctx.Err() != nil {
+			log.Info("sudden shutdown detected, stopping sharded backfill")
+			return
+		}
+		shard, ok, err := s.claimBackfillShard()
+		if err != nil {
+			log.Errorf("could not claim a backfill shard: %s", err)
+			select {
+			case <-time.After(utils.RoutineFlushTimeout):
+			case <-s.ctx.Done():
+			}
+			continue
+		}
+		if !ok {
+			log.Infof("no backfill shards left to claim")
+			return
+		}
+
+		if !s.runBackfillShard(shard) {
+			// the lease is left to expire so that another instance retries the shard
+			return
+		}
+		err = s.leases.PersistBackfillLease(s.newBackfillLease(shard, db.BackfillLeaseDone))
+		if err != nil {
+			log.Errorf("could not mark backfill shard %d - %d as done: %s", shard.start, shard.end-1, err)
+		}
+	}
+}
+
+func (s *ChainAnalyzer) newBackfillLease(shard backfillShard, status string) db.BackfillLease {
+	now := time.Now()
+	return db.BackfillLease{
+		StartEpoch: shard.start,
+		EndEpoch:   shard.end,
+		Owner:      s.backfillWorkerID,
+		Status:     status,
+		ExpiresAt:  now.Add(backfillLeaseTTL),
+		Version:    uint64(now.UnixNano()),
+	}
+}
+
+// claimBackfillShard returns the first shard that is neither done nor leased by a live instance.
+// Several instances may write a claim for the same shard at the same time: the claim with
+// the highest version wins, and the others move on to the next shard.
+func (s *ChainAnalyzer) claimBackfillShard() (backfillShard, bool, error) {
+	leases, err := s.leases.RetrieveBackfillLeases(s.backfillFirstEpoch, s.backfillEndEpoch)
+	if err != nil {
+		return backfillShard{}, false, err
+	}
+
+	for _, shard := range backfillShards(s.backfillFirstEpoch, s.backfillEndEpoch, s.backfillShardEpochs) {
+		if lease, ok := leases[shard.start]; ok && !lease.Claimable(time.Now()) {
+			continue
+		}
+
+		err := s.leases.PersistBackfillLease(s.newBackfillLease(shard, db.BackfillLeaseRunning))
+		if err != nil {
+			return backfillShard{}, false, err
+		}
+
+		select {
+		case <-time.After(backfillClaimSettle):
+		case <-s.ctx.Done():
+			return backfillShard{}, false, nil
+		}
+
+		claimed, err := s.leases.RetrieveBackfillLeases(shard.start, shard.start+1)
+		if err != nil {
+			return backfillShard{}, false, err
+		}
+		if lease, ok := claimed[shard.start]; ok && lease.Owner == s.backfillWorkerID {
+			log.Infof("claimed backfill shard %d - %d", shard.start, shard.end-1)
+			return shard, true, nil
+		}
+		log.Debugf("backfill shard %d - %d claimed by another instance", shard.start, shard.end-1)
+	}
+	return backfillShard{}, false, nil
+}
+
+// runBackfillShard downloads the shard with the same 2 epochs warm-up as a regular
+// historical run, and waits until all its epochs were processed.
+// The warm-up blocks are persisted again, which the ReplacingMergeTree tables absorb.
+// Returns false if the analyzer is shutting down before the shard is complete.
+func (s *ChainAnalyzer) runBackfillShard(shard backfillShard) bool {
+	initSlot := phase0.Slot(0)
+	if shard.start >= 2 {
+		initSlot = phase0.Slot(shard.start-2) * spec.SlotsPerEpoch
+	}
+	finalSlot := phase0.Slot(shard.end) * spec.SlotsPerEpoch
+
+	// the previous shard is fully processed, start from a clean state
+	s.downloadCache.CleanUpTo(s.finalSlot + 1)
+	s.initSlot = initSlot
+	s.finalSlot = finalSlot
+
+	// shard boundaries are also aggregation window boundaries, except for the first shard
+	err := s.initRewardsAggregation(shard.start)
+	if err != nil {
+		log.Errorf("could not restore the rewards aggregation window: %s", err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.renewBackfillLease(shard, done)
+
+	s.wgMainRoutine.Add(1) // add because historical will defer it
+	s.runHistorical(initSlot, finalSlot)
+
+	return s.waitBackfillShard(shard)
+}
+
+// renewBackfillLease keeps the lease alive while the shard is being processed.
+func (s *ChainAnalyzer) renewBackfillLease(shard backfillShard, done <-chan struct{}) {
+	ticker := time.NewTicker(backfillLeaseTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			err := s.leases.PersistBackfillLease(s.newBackfillLease(shard, db.BackfillLeaseRunning))
+			if err != nil {
+				log.Errorf("could not renew backfill shard %d - %d: %s", shard.start, shard.end-1, err)
+			}
+		case <-done:
+			return
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// waitBackfillShard waits until the last block and state of the shard are in the cache
+// and nothing is being downloaded or processed anymore.
+func (s *ChainAnalyzer) waitBackfillShard(shard backfillShard) bool {
+	lastEpoch := shard.end - 1
+	lastSlot := phase0.Slot(shard.end)*spec.SlotsPerEpoch - 1
+
+	ticker := time.NewTicker(utils.RoutineFlushTimeout)
+	defer ticker.Stop()
+	idleTicks := 0
+	for {
+		select {
+		case <-ticker.C:
+			if s.stop {
+				return false
+			}
+			downloaded := s.downloadCache.BlockHistory.Available(SlotTo[uint64](lastSlot)) &&
+				(!s.metrics.Epoch || s.downloadCache.StateHistory.Available(EpochTo[uint64](lastEpoch)))
+			idle := len(s.downloadTaskChan) == 0 && s.cli.ActiveReqNum() == 0 && s.processerBook.ActivePages() == 0
+			if downloaded && idle {
+				idleTicks++
+			} else {
+				idleTicks = 0
+			}
+			// two idle ticks in a row, so routines spawned right before are accounted for
+			if idleTicks >= 2 {
+				log.Infof("backfill shard %d - %d completed", shard.start, lastEpoch)
+				return true
+			}
+		case <-s.ctx.Done():
+			return false
+		}
+	}
+}
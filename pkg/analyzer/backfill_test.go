@@ -0,0 +1,210 @@
+package analyzer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillShards(t *testing.T) {
+	shards := backfillShards(100, 325, 100)
+	assert.Equal(t, []backfillShard{
+		{start: 100, end: 200},
+		{start: 200, end: 300},
+		{start: 300, end: 325},
+	}, shards)
+
+	shards = backfillShards(130, 325, 100)
+	assert.Equal(t, []backfillShard{
+		{start: 130, end: 200},
+		{start: 200, end: 300},
+		{start: 300, end: 325},
+	}, shards)
+
+	assert.Equal(t, []backfillShard{{start: 10, end: 11}}, backfillShards(10, 11, 225))
+	assert.Empty(t, backfillShards(10, 10, 225))
+	assert.Empty(t, backfillShards(10, 20, 0))
+}
+
+// fakeBackfillLeases keeps the highest version of each lease, as t_backfill_leases does.
+type fakeBackfillLeases struct {
+	mu     sync.Mutex
+	leases map[phase0.Epoch]db.BackfillLease
+	writes int
+}
+
+func newFakeBackfillLeases() *fakeBackfillLeases {
+	return &fakeBackfillLeases{leases: make(map[phase0.Epoch]db.BackfillLease)}
+}
+
+func (f *fakeBackfillLeases) PersistBackfillLease(lease db.BackfillLease) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.writes++
+	if existing, ok := f.leases[lease.StartEpoch]; !ok || lease.Version > existing.Version {
+		f.leases[lease.StartEpoch] = lease
+	}
+	return nil
+}
+
+func (f *fakeBackfillLeases) RetrieveBackfillLeases(from phase0.Epoch, to phase0.Epoch) (map[phase0.Epoch]db.BackfillLease, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make(map[phase0.Epoch]db.BackfillLease)
+	for start, lease := range f.leases {
+		if start >= from && start < to {
+			result[start] = lease
+		}
+	}
+	return result, nil
+}
+
+// racingBackfillLeases holds the first read of a worker until every worker made it,
+// so that they all claim from the same view of the leases.
+type racingBackfillLeases struct {
+	*fakeBackfillLeases
+	barrier *sync.WaitGroup
+	once    sync.Once
+}
+
+func (r *racingBackfillLeases) RetrieveBackfillLeases(from phase0.Epoch, to phase0.Epoch) (map[phase0.Epoch]db.BackfillLease, error) {
+	leases, err := r.fakeBackfillLeases.RetrieveBackfillLeases(from, to)
+	r.once.Do(func() {
+		r.barrier.Done()
+		r.barrier.Wait()
+	})
+	return leases, err
+}
+
+func newBackfillWorker(id string, leases backfillLeases) *ChainAnalyzer {
+	return &ChainAnalyzer{
+		ctx:                 context.Background(),
+		leases:              leases,
+		backfillWorkerID:    id,
+		backfillFirstEpoch:  100,
+		backfillEndEpoch:    300,
+		backfillShardEpochs: 100,
+	}
+}
+
+func shortBackfillTimings(t *testing.T) {
+	settle, ttl := backfillClaimSettle, backfillLeaseTTL
+	backfillClaimSettle = 50 * time.Millisecond
+	backfillLeaseTTL = 30 * time.Millisecond
+	t.Cleanup(func() {
+		backfillClaimSettle, backfillLeaseTTL = settle, ttl
+	})
+}
+
+func TestClaimBackfillShard(t *testing.T) {
+	shortBackfillTimings(t)
+	leases := newFakeBackfillLeases()
+	worker := newBackfillWorker("a", leases)
+
+	shard, ok, err := worker.claimBackfillShard()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, backfillShard{start: 100, end: 200}, shard)
+	assert.Equal(t, "a", leases.leases[100].Owner)
+	assert.Equal(t, db.BackfillLeaseRunning, leases.leases[100].Status)
+
+	// a shard done is never claimed again, the next one is
+	require.NoError(t, leases.PersistBackfillLease(worker.newBackfillLease(shard, db.BackfillLeaseDone)))
+	shard, ok, err = worker.claimBackfillShard()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, backfillShard{start: 200, end: 300}, shard)
+
+	require.NoError(t, leases.PersistBackfillLease(worker.newBackfillLease(shard, db.BackfillLeaseDone)))
+	_, ok, err = worker.claimBackfillShard()
+	require.NoError(t, err)
+	assert.False(t, ok, "no shards left")
+}
+
+func TestRenewBackfillLease(t *testing.T) {
+	shortBackfillTimings(t)
+	leases := newFakeBackfillLeases()
+	worker := newBackfillWorker("a", leases)
+	shard := backfillShard{start: 100, end: 200}
+	require.NoError(t, leases.PersistBackfillLease(worker.newBackfillLease(shard, db.BackfillLeaseRunning)))
+	claimed := leases.leases[100]
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		worker.renewBackfillLease(shard, done)
+		close(stopped)
+	}()
+	time.Sleep(10 * backfillLeaseTTL)
+	close(done)
+	<-stopped
+
+	renewed, err := leases.RetrieveBackfillLeases(100, 101)
+	require.NoError(t, err)
+	assert.Equal(t, "a", renewed[100].Owner)
+	assert.Greater(t, renewed[100].Version, claimed.Version)
+	assert.True(t, renewed[100].ExpiresAt.After(claimed.ExpiresAt))
+	assert.False(t, renewed[100].Claimable(claimed.ExpiresAt.Add(backfillLeaseTTL/2)), "renewed before the first expiry")
+}
+
+func TestBackfillLeaseTakeover(t *testing.T) {
+	shortBackfillTimings(t)
+	leases := newFakeBackfillLeases()
+	crashed := newBackfillWorker("crashed", leases)
+	worker := newBackfillWorker("b", leases)
+
+	first := backfillShard{start: 100, end: 200}
+	require.NoError(t, leases.PersistBackfillLease(crashed.newBackfillLease(first, db.BackfillLeaseRunning)))
+
+	// the live lease of another instance is skipped
+	shard, ok, err := worker.claimBackfillShard()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, backfillShard{start: 200, end: 300}, shard)
+
+	// once expired, without renewals, it is taken over
+	time.Sleep(2 * backfillLeaseTTL)
+	shard, ok, err = worker.claimBackfillShard()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, first, shard)
+	assert.Equal(t, "b", leases.leases[100].Owner)
+}
+
+func TestCompetingBackfillClaims(t *testing.T) {
+	shortBackfillTimings(t)
+	leases := newFakeBackfillLeases()
+	barrier := &sync.WaitGroup{}
+	barrier.Add(2)
+	workers := []*ChainAnalyzer{
+		newBackfillWorker("a", &racingBackfillLeases{fakeBackfillLeases: leases, barrier: barrier}),
+		newBackfillWorker("b", &racingBackfillLeases{fakeBackfillLeases: leases, barrier: barrier}),
+	}
+
+	// both claim the first shard at the same time, the loser moves on to the next one
+	shards := make([]backfillShard, len(workers))
+	var wg sync.WaitGroup
+	for i, worker := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shard, ok, err := worker.claimBackfillShard()
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			shards[i] = shard
+		}()
+	}
+	wg.Wait()
+
+	assert.ElementsMatch(t, []backfillShard{{start: 100, end: 200}, {start: 200, end: 300}}, shards)
+	assert.Equal(t, 3, leases.writes, "both claimed the first shard")
+	for i, shard := range shards {
+		assert.Equal(t, workers[i].backfillWorkerID, leases.leases[shard.start].Owner)
+	}
+}
@@ -7,11 +7,18 @@ import (
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/migalabs/goteth/pkg/blobstore"
+	"github.com/migalabs/goteth/pkg/builders"
 	"github.com/migalabs/goteth/pkg/clientapi"
+	"github.com/migalabs/goteth/pkg/compliance"
 	"github.com/migalabs/goteth/pkg/config"
 	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/eventlogs"
+	"github.com/migalabs/goteth/pkg/graffiti"
+	"github.com/migalabs/goteth/pkg/kzg"
 	prom_metrics "github.com/migalabs/goteth/pkg/metrics"
 	"github.com/migalabs/goteth/pkg/relay"
+	"github.com/migalabs/goteth/pkg/rollups"
 	"github.com/migalabs/goteth/pkg/spec"
 	"github.com/migalabs/goteth/pkg/utils"
 
@@ -30,32 +37,44 @@ type ChainAnalyzer struct {
 	initSlot  phase0.Slot
 	finalSlot phase0.Slot
 
+	// Sharded backfill
+	backfillShardEpochs int          // epochs per leased shard, 0 if disabled
+	backfillWorkerID    string       // owner written in the leases table
+	backfillFirstEpoch  phase0.Epoch // first epoch requested by the user
+	backfillEndEpoch    phase0.Epoch // epoch after the last one requested by the user
+
 	// Channels
 	downloadTaskChan chan phase0.Slot // channel to send download tasks
 
 	// Connections
-	cli       *clientapi.APIClient // client to request data to the CL and EL clients
-	relayCli  *relay.RelaysMonitor // client to monitor all relays in list
-	eventsObj events.Events        // object to receive signals from beacon node
-	dbClient  *db.DBService        // client to communicate with clickhouse
+	cli        *clientapi.APIClient    // client to request data to the CL and EL clients
+	relayCli   *relay.RelaysMonitor    // client to monitor all relays in list
+	builders   *builders.Registry      // identifies who built each block
+	compliance *compliance.AddressList // nil when blocks are not checked
+	rollups    *rollups.Registry       // nil when blob transactions are not attributed
+	graffiti   *graffiti.RuleSet       // parses the clients advertised in the graffiti
+	kzg        *kzg.Verifier           // nil when blobs are not verified
+	blobStore  blobstore.Store         // nil when the blob contents are not stored
+	logDecoder *eventlogs.Decoder      // decodes receipt logs with the known ABIs
+	eventsObj  events.Events           // object to receive signals from beacon node
+	dbClient   *db.DBService           // client to communicate with clickhouse
+	leases     backfillLeases          // backfill shard claims, the dbClient
 
 	// Control Variables
-	wgMainRoutine            *sync.WaitGroup    // wait group for main routine (either historical or head)
-	wgDownload               *sync.WaitGroup    // wait group for download routine
-	stop                     bool               // flag to notify all routine to finish
-	routineClosed            chan struct{}      // signal that everything was closed succesfully
-	downloadMode             string             // whether to download historical blocks (defined by user) or follow chain head
-	rewardsAggregationEpochs int                // number of epochs to aggregate rewards
-	startEpochAggregation    phase0.Epoch       // epoch to start rewards aggregation
-	endEpochAggregation      phase0.Epoch       // epoch to end rewards aggregation
-	metrics                  db.DBMetrics       // what metrics to be downloaded / processed
-	processerBook            *utils.RoutineBook // defines slot to process new metrics into the database, good for monitoring
-
-	downloadCache                    ChainCache // store the blocks and states downloaded
-	validatorsRewardsAggregations   map[phase0.ValidatorIndex]*spec.ValidatorRewardsAggregation
-	validatorsRewardsAggregationsMu sync.Mutex
-	aggregatedEpochsInWindow        map[phase0.Epoch]bool // set of unique epochs aggregated in current window; prevents double-counting on reprocessing (#255)
-	epochBoundaryStateRoots       sync.Map   // slot -> phase0.Root, caches state roots from Head SSE events at epoch boundaries
+	wgMainRoutine *sync.WaitGroup    // wait group for main routine (either historical or head)
+	wgDownload    *sync.WaitGroup    // wait group for download routine
+	stop          bool               // flag to notify all routine to finish
+	routineClosed chan struct{}      // signal that everything was closed succesfully
+	downloadMode  string             // whether to download historical blocks (defined by user) or follow chain head
+	metrics       db.DBMetrics       // what metrics to be downloaded / processed
+	processerBook *utils.RoutineBook // defines slot to process new metrics into the database, good for monitoring
+
+	downloadCache           ChainCache                // store the blocks and states downloaded
+	rewardsLevels           []rewardsAggregationLevel // validator rewards aggregation levels
+	rewardsWindows          []*rewardsWindow          // per level, window being filled, nil until the first epoch
+	rewardsWindowsMu        sync.Mutex
+	epochBoundaryStateRoots sync.Map   // slot -> phase0.Root, caches state roots from Head SSE events at epoch boundaries
+	reorgMu                 sync.Mutex // held while a reorg replaces the cached blocks
 
 	initTime    time.Time
 	PromMetrics *prom_metrics.PrometheusMetrics // metrics to be stored to prometheus
@@ -71,8 +90,16 @@ func NewChainAnalyzer(
 	// generate the central exporting service
 	promethMetrics := prom_metrics.NewPrometheusMetrics(ctx, "0.0.0.0", iConfig.PrometheusPort)
 
-	startEpochAggregation := phase0.Epoch(0)
-	endEpochAggregation := phase0.Epoch(0)
+	backfillFirstEpoch := phase0.Epoch(0)
+	backfillEndEpoch := phase0.Epoch(0)
+
+	rewardsLevels, err := newRewardsAggregationLevels(iConfig.RewardsAggregationEpochs, iConfig.RewardsAggregationLevels)
+	if err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Wrap(err, "unable to read rewards aggregation levels.")
+	}
 
 	// calculate the list of slots that we will analyze
 	if iConfig.DownloadMode == "historical" {
@@ -83,14 +110,54 @@ func NewChainAnalyzer(
 				cancel: cancel,
 			}, errors.Errorf("Final Slot cannot be greater than Init Slot")
 		}
+		for _, level := range rewardsLevels {
+			// otherwise an aggregation window would be split between two shards
+			if iConfig.BackfillShardEpochs > 0 && iConfig.BackfillShardEpochs%level.epochs != 0 {
+				return &ChainAnalyzer{
+					ctx:    ctx,
+					cancel: cancel,
+				}, errors.Errorf("backfill shard epochs (%d) must be a multiple of rewards aggregation epochs (%d)",
+					iConfig.BackfillShardEpochs, level.epochs)
+			}
+		}
+		backfillFirstEpoch = spec.EpochAtSlot(iConfig.InitSlot)
+		backfillEndEpoch = spec.EpochAtSlot(iConfig.FinalSlot) + 1
 		// Start 2 epochs before and finish 1 epoch after
 		iConfig.InitSlot = iConfig.InitSlot/spec.SlotsPerEpoch*spec.SlotsPerEpoch - spec.SlotsPerEpoch*2
 		iConfig.FinalSlot = iConfig.FinalSlot/spec.SlotsPerEpoch*spec.SlotsPerEpoch + spec.SlotsPerEpoch
 		log.Infof("generating new Block Analyzer from slots %d:%d", iConfig.InitSlot, iConfig.FinalSlot)
-		// 2 epochs after the start since thats when we start processing rewards
-		startEpochAggregation = phase0.Epoch(spec.EpochAtSlot(iConfig.InitSlot) + 2)
-		endEpochAggregation = startEpochAggregation + phase0.Epoch(iConfig.RewardsAggregationEpochs-1)
+	}
+
+	buildersRegistry, err := builders.ReadBuildersFile(iConfig.BuildersFile)
+	if err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Wrap(err, "unable to read builders file.")
+	}
+
+	complianceList, err := compliance.ReadAddressList(iConfig.ComplianceList)
+	if err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Wrap(err, "unable to read compliance list.")
+	}
+
+	rollupsRegistry, err := rollups.ReadRegistryFile(iConfig.RollupsFile)
+	if err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Wrap(err, "unable to read rollups file.")
+	}
 
+	graffitiRules, err := graffiti.ReadRulesFile(iConfig.GraffitiRules)
+	if err != nil {
+		return &ChainAnalyzer{
+			ctx:    ctx,
+			cancel: cancel,
+		}, errors.Wrap(err, "unable to read graffiti rules.")
 	}
 
 	metricsObj, err := db.NewMetrics(iConfig.Metrics)
@@ -100,6 +167,46 @@ func NewChainAnalyzer(
 			cancel: cancel,
 		}, errors.Wrap(err, "unable to read metric.")
 	}
+	var logDecoder *eventlogs.Decoder
+	if metricsObj.Logs {
+		logDecoder, err = eventlogs.ReadABIDir(iConfig.ABIDir)
+		if err != nil {
+			return &ChainAnalyzer{
+				ctx:    ctx,
+				cancel: cancel,
+			}, errors.Wrap(err, "unable to read ABIs.")
+		}
+	}
+	if complianceList != nil && !metricsObj.Transactions {
+		log.Warnf("the compliance list needs the transactions metric, blocks will not be classified")
+		complianceList = nil
+	}
+	if rollupsRegistry != nil && !metricsObj.Transactions {
+		log.Warnf("the rollups file needs the transactions metric, blobs will not be attributed")
+		rollupsRegistry = nil
+	}
+	var kzgVerifier *kzg.Verifier
+	if iConfig.VerifyKZG {
+		if !metricsObj.BlobSidecars {
+			log.Warnf("the KZG verification needs the blob_sidecars metric, blobs will not be verified")
+		} else if kzgVerifier, err = kzg.NewVerifier(); err != nil {
+			return &ChainAnalyzer{
+				ctx:    ctx,
+				cancel: cancel,
+			}, errors.Wrap(err, "unable to load the KZG trusted setup.")
+		}
+	}
+	var blobStore blobstore.Store
+	if iConfig.BlobStore != "" {
+		if !metricsObj.BlobSidecars {
+			log.Warnf("the blob store needs the blob_sidecars metric, blobs will not be stored")
+		} else if blobStore, err = blobstore.New(iConfig.BlobStore); err != nil {
+			return &ChainAnalyzer{
+				ctx:    ctx,
+				cancel: cancel,
+			}, errors.Wrap(err, "unable to open the blob store.")
+		}
+	}
 
 	idbClient, err := db.New(ctx, iConfig.DBUrl)
 	if err != nil {
@@ -126,6 +233,10 @@ func NewChainAnalyzer(
 		iConfig.MaxRequestRetries,
 		clientapi.WithELEndpoint(iConfig.ElEndpoint),
 		clientapi.WithDBMetrics(metricsObj),
+		clientapi.WithConsistencyChecks(iConfig.BnConsistencyChecks),
+		clientapi.WithNonArchivalStates(iConfig.BnNonArchival),
+		clientapi.WithSSZ(iConfig.BnSSZ),
+		clientapi.WithTraceConcurrency(iConfig.TraceConcurrency),
 		clientapi.WithPromMetrics(promethMetrics))
 	if err != nil {
 		return &ChainAnalyzer{
@@ -160,30 +271,52 @@ func NewChainAnalyzer(
 
 	idbClient.InitGenesis(genesisTime)
 
+	backfillWorkerID := iConfig.BackfillWorkerID
+	if backfillWorkerID == "" {
+		backfillWorkerID = defaultBackfillWorkerID()
+	}
+
 	analyzer := &ChainAnalyzer{
-		ctx:                           ctx,
-		cancel:                        cancel,
-		beaconContractAddress:         beaconContractAddress,
-		initSlot:                      phase0.Slot(iConfig.InitSlot),
-		finalSlot:                     phase0.Slot(iConfig.FinalSlot),
-		downloadTaskChan:              make(chan phase0.Slot, rateLimit), // TODO: define size of buffer depending on performance
-		cli:                           cli,
-		relayCli:                      relayCli,
-		dbClient:                      idbClient,
-		routineClosed:                 make(chan struct{}, 1),
-		eventsObj:                     events.NewEventsObj(ctx, cli),
-		downloadMode:                  iConfig.DownloadMode,
-		rewardsAggregationEpochs:      iConfig.RewardsAggregationEpochs,
-		startEpochAggregation:         startEpochAggregation,
-		endEpochAggregation:           endEpochAggregation,
-		metrics:                       metricsObj,
-		PromMetrics:                   promethMetrics,
-		downloadCache:                 NewQueue(),
-		validatorsRewardsAggregations: make(map[phase0.ValidatorIndex]*spec.ValidatorRewardsAggregation),
-		aggregatedEpochsInWindow:      make(map[phase0.Epoch]bool),
-		processerBook:                 utils.NewRoutineBook(32, "processer"), // one whole epoch
-		wgMainRoutine:                 &sync.WaitGroup{},
-		wgDownload:                    &sync.WaitGroup{},
+		ctx:                   ctx,
+		cancel:                cancel,
+		beaconContractAddress: beaconContractAddress,
+		initSlot:              phase0.Slot(iConfig.InitSlot),
+		finalSlot:             phase0.Slot(iConfig.FinalSlot),
+		backfillShardEpochs:   iConfig.BackfillShardEpochs,
+		backfillWorkerID:      backfillWorkerID,
+		backfillFirstEpoch:    backfillFirstEpoch,
+		backfillEndEpoch:      backfillEndEpoch,
+		downloadTaskChan:      make(chan phase0.Slot, rateLimit), // TODO: define size of buffer depending on performance
+		cli:                   cli,
+		relayCli:              relayCli,
+		builders:              buildersRegistry,
+		compliance:            complianceList,
+		rollups:               rollupsRegistry,
+		graffiti:              graffitiRules,
+		kzg:                   kzgVerifier,
+		blobStore:             blobStore,
+		logDecoder:            logDecoder,
+		dbClient:              idbClient,
+		leases:                idbClient,
+		routineClosed:         make(chan struct{}, 1),
+		eventsObj:             events.NewEventsObj(ctx, cli),
+		downloadMode:          iConfig.DownloadMode,
+		metrics:               metricsObj,
+		PromMetrics:           promethMetrics,
+		downloadCache:         NewQueue(),
+		rewardsLevels:         rewardsLevels,
+		rewardsWindows:        make([]*rewardsWindow, len(rewardsLevels)),
+		processerBook:         utils.NewRoutineBook(32, "processer"), // one whole epoch
+		wgMainRoutine:         &sync.WaitGroup{},
+		wgDownload:            &sync.WaitGroup{},
+	}
+
+	if iConfig.DownloadMode == "historical" && iConfig.BackfillShardEpochs == 0 {
+		// 2 epochs after the start since thats when we start processing rewards
+		err = analyzer.initRewardsAggregation(spec.EpochAtSlot(iConfig.InitSlot) + 2)
+		if err != nil {
+			return analyzer, errors.Wrap(err, "unable to restore the rewards aggregation window.")
+		}
 	}
 
 	analyzerMet := analyzer.GetPrometheusMetrics()
@@ -210,7 +343,11 @@ func (s *ChainAnalyzer) Run() {
 		// Block requester + Task generator
 		s.wgMainRoutine.Add(1)
 
-		go s.runHistorical(s.initSlot, s.finalSlot)
+		if s.backfillShardEpochs > 0 {
+			go s.runShardedHistorical()
+		} else {
+			go s.runHistorical(s.initSlot, s.finalSlot)
+		}
 	}
 
 	if s.downloadMode == "finalized" {
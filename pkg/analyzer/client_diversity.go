@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+const unknownClient = "unknown"
+
+// aggregateClientDiversity estimates the share of each consensus and execution client
+// among the proposed blocks of an epoch, from the clients advertised in their graffiti.
+func aggregateClientDiversity(epoch phase0.Epoch, blocks []*spec.AgnosticBlock) []db.ClientDiversity {
+	counts := map[string]map[string]uint64{
+		"cl": make(map[string]uint64),
+		"el": make(map[string]uint64),
+	}
+	proposed := uint64(0)
+	for _, block := range blocks {
+		if !block.Proposed {
+			continue
+		}
+		proposed++
+		counts["cl"][clientOrUnknown(block.GraffitiClients.CLClient)]++
+		counts["el"][clientOrUnknown(block.GraffitiClients.ELClient)]++
+	}
+
+	result := make([]db.ClientDiversity, 0)
+	if proposed == 0 {
+		return result
+	}
+	for layer, clients := range counts {
+		for client, blocks := range clients {
+			result = append(result, db.ClientDiversity{
+				Epoch:  epoch,
+				Layer:  layer,
+				Client: client,
+				Blocks: blocks,
+				Share:  float64(blocks) / float64(proposed),
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Layer != result[j].Layer {
+			return result[i].Layer < result[j].Layer
+		}
+		return result[i].Client < result[j].Client
+	})
+	return result
+}
+
+func clientOrUnknown(client string) string {
+	if client == "" {
+		return unknownClient
+	}
+	return client
+}
@@ -0,0 +1,30 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateClientDiversity(t *testing.T) {
+	blocks := []*spec.AgnosticBlock{
+		{Proposed: true, GraffitiClients: spec.GraffitiClients{CLClient: "lighthouse", ELClient: "geth"}},
+		{Proposed: true, GraffitiClients: spec.GraffitiClients{CLClient: "lighthouse", ELClient: "nethermind"}},
+		{Proposed: true, GraffitiClients: spec.GraffitiClients{CLClient: "teku"}},
+		{Proposed: true},
+		{Proposed: false, GraffitiClients: spec.GraffitiClients{CLClient: "prysm"}}, // missed slot
+	}
+
+	assert.Equal(t, []db.ClientDiversity{
+		{Epoch: 10, Layer: "cl", Client: "lighthouse", Blocks: 2, Share: 0.5},
+		{Epoch: 10, Layer: "cl", Client: "teku", Blocks: 1, Share: 0.25},
+		{Epoch: 10, Layer: "cl", Client: "unknown", Blocks: 1, Share: 0.25},
+		{Epoch: 10, Layer: "el", Client: "geth", Blocks: 1, Share: 0.25},
+		{Epoch: 10, Layer: "el", Client: "nethermind", Blocks: 1, Share: 0.25},
+		{Epoch: 10, Layer: "el", Client: "unknown", Blocks: 2, Share: 0.5},
+	}, aggregateClientDiversity(10, blocks))
+
+	assert.Empty(t, aggregateClientDiversity(10, []*spec.AgnosticBlock{{Proposed: false}}))
+}
@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+const (
+	complianceRelayGroup   = "relay"
+	complianceBuilderGroup = "builder"
+)
+
+// classifyBlock checks the transactions of a block against the compliance list.
+// Blocks without a payload or whose transactions could not be parsed are not classified.
+func (s *ChainAnalyzer) classifyBlock(block spec.AgnosticBlock, reward db.BlockReward) (db.BlockCompliance, bool) {
+	if !block.Proposed || block.ExecutionPayload.BlockHash == (phase0.Hash32{}) {
+		return db.BlockCompliance{}, false
+	}
+	if len(block.ExecutionPayload.AgnosticTransactions) != len(block.ExecutionPayload.Transactions) {
+		log.Warnf("slot %d: not all transactions were parsed, skipping compliance", block.Slot)
+		return db.BlockCompliance{}, false
+	}
+
+	result := s.compliance.Classify(block.ExecutionPayload.AgnosticTransactions)
+	return db.BlockCompliance{
+		Slot:          block.Slot,
+		Epoch:         spec.EpochAtSlot(block.Slot),
+		BlockRoot:     block.Root,
+		ProposerIndex: block.ProposerIndex,
+		Compliant:     result.Compliant,
+		Transactions:  uint64(result.Transactions),
+		FlaggedTxs:    result.FlaggedTxs,
+		Relays:        reward.Relays,
+		Builder:       reward.Builder,
+		BuildType:     reward.BuildType,
+	}, true
+}
+
+func (s *ChainAnalyzer) persistCompliance(epoch phase0.Epoch, blocks []db.BlockCompliance) {
+	if len(blocks) == 0 {
+		return
+	}
+	s.dbClient.PersistBlockCompliance(blocks)
+	s.dbClient.PersistComplianceSummary(aggregateCompliance(epoch, blocks))
+}
+
+// aggregateCompliance summarizes the blocks of an epoch per relay that delivered them and
+// per builder. Blocks of unidentified builders are grouped by their build type.
+func aggregateCompliance(epoch phase0.Epoch, blocks []db.BlockCompliance) []db.ComplianceSummary {
+	summaries := make(map[[2]string]*db.ComplianceSummary)
+	add := func(group string, name string, block db.BlockCompliance) {
+		key := [2]string{group, name}
+		summary, ok := summaries[key]
+		if !ok {
+			summary = &db.ComplianceSummary{Epoch: epoch, Group: group, Name: name}
+			summaries[key] = summary
+		}
+		summary.Blocks++
+		if block.Compliant {
+			summary.CompliantBlocks++
+		}
+		summary.Transactions += block.Transactions
+		summary.FlaggedTxs += uint64(len(block.FlaggedTxs))
+	}
+
+	for _, block := range blocks {
+		for _, relay := range block.Relays {
+			add(complianceRelayGroup, relay, block)
+		}
+		builder := block.Builder
+		if builder == "" {
+			builder = block.BuildType
+		}
+		add(complianceBuilderGroup, builder, block)
+	}
+
+	result := make([]db.ComplianceSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Group != result[j].Group {
+			return result[i].Group < result[j].Group
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
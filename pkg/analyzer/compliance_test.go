@@ -0,0 +1,26 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateCompliance(t *testing.T) {
+	blocks := []db.BlockCompliance{
+		{Slot: 320, Compliant: true, Transactions: 10, Relays: []string{"relay-a", "relay-b"}, Builder: "titan", BuildType: "relay"},
+		{Slot: 321, Compliant: false, Transactions: 5, FlaggedTxs: []phase0.Hash32{{1}, {2}}, Relays: []string{"relay-a"}, BuildType: "relay"},
+		{Slot: 322, Compliant: false, Transactions: 3, FlaggedTxs: []phase0.Hash32{{3}}, BuildType: "local"},
+	}
+
+	summaries := aggregateCompliance(10, blocks)
+	assert.Equal(t, []db.ComplianceSummary{
+		{Epoch: 10, Group: "builder", Name: "local", Blocks: 1, CompliantBlocks: 0, Transactions: 3, FlaggedTxs: 1},
+		{Epoch: 10, Group: "builder", Name: "relay", Blocks: 1, CompliantBlocks: 0, Transactions: 5, FlaggedTxs: 2},
+		{Epoch: 10, Group: "builder", Name: "titan", Blocks: 1, CompliantBlocks: 1, Transactions: 10, FlaggedTxs: 0},
+		{Epoch: 10, Group: "relay", Name: "relay-a", Blocks: 2, CompliantBlocks: 1, Transactions: 15, FlaggedTxs: 2},
+		{Epoch: 10, Group: "relay", Name: "relay-b", Blocks: 1, CompliantBlocks: 1, Transactions: 10, FlaggedTxs: 0},
+	}, summaries)
+}
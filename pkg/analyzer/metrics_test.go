@@ -640,7 +640,7 @@ func TestBlockSizeNotSetWhenELNotProvided(t *testing.T) {
 	assert.Equal(t, block.ExecutionPayload.PayloadSize, uint32(0))
 }
 
-func TestBlockGasFees(t *testing.T) {
+func TestBlockFees(t *testing.T) {
 
 	analyzer, err := BuildChainAnalyzer()
 	if err != nil {
@@ -654,14 +654,14 @@ func TestBlockGasFees(t *testing.T) {
 		return
 	}
 
-	reward, burn, err := block.BlockGasFees()
+	fees, err := block.BlockFees()
 
 	if err != nil {
-		t.Errorf("could not calculate block gas fees: %s", err)
+		t.Errorf("could not calculate block fees: %s", err)
 		return
 	}
 
-	assert.Equal(t, reward, uint64(44861896127679906))
-	assert.Equal(t, burn, uint64(317246355753369564))
+	assert.Equal(t, fees.Tips.String(), "44861896127679906")
+	assert.Equal(t, fees.BurntFees.String(), "317246355753369564")
 
 }
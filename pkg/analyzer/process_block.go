@@ -6,6 +6,7 @@ import (
 	eth2_client_spec "github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/migalabs/goteth/pkg/blobstore"
 	"github.com/migalabs/goteth/pkg/spec"
 )
 
@@ -26,6 +27,7 @@ func (s *ChainAnalyzer) ProcessBlock(slot phase0.Slot) {
 		log.Errorf("context cancelled waiting for block at slot %d: %s", slot, err)
 		return
 	}
+	block.GraffitiClients = s.graffiti.Parse(block.Graffiti[:])
 	err = s.dbClient.PersistBlocks([]spec.AgnosticBlock{*block})
 	if err != nil {
 		log.Errorf("error persisting blocks: %s", err.Error())
@@ -60,11 +62,23 @@ func (s *ChainAnalyzer) ProcessETH1Data(block *spec.AgnosticBlock) {
 			log.Errorf("error processing eth1 deposits: %s", err.Error())
 			return
 		}
+
+		if s.metrics.Logs {
+			s.processLogs(block)
+		}
+
+		if s.metrics.Traces {
+			s.processTraces(block)
+		}
 	}
 
 	if block.HardForkVersion >= eth2_client_spec.DataVersionDeneb && s.metrics.BlobSidecars {
 		s.processBlobSidecars(block, block.ExecutionPayload.AgnosticTransactions)
 	}
+
+	if block.HardForkVersion >= eth2_client_spec.DataVersionFulu && s.metrics.DataColumns && block.Proposed && block.Blobs() > 0 {
+		s.processDataColumnSidecars(block)
+	}
 }
 
 func (s *ChainAnalyzer) processETH1Deposits(block *spec.AgnosticBlock) error {
@@ -99,6 +113,63 @@ func (s *ChainAnalyzer) processETH1Deposits(block *spec.AgnosticBlock) error {
 	return err
 }
 
+// processLogs stores the receipt logs of the block and the events decoded from them
+func (s *ChainAnalyzer) processLogs(block *spec.AgnosticBlock) {
+	logs := spec.ParseLogsFromTransactions(block.ExecutionPayload.AgnosticTransactions)
+	if len(logs) == 0 {
+		return
+	}
+	err := s.dbClient.PersistLogs(logs)
+	if err != nil {
+		log.Errorf("error persisting logs: %s", err.Error())
+	}
+
+	decoded := s.logDecoder.DecodeLogs(logs)
+	if len(decoded.Transfers) > 0 {
+		err = s.dbClient.PersistTokenTransfers(decoded.Transfers)
+		if err != nil {
+			log.Errorf("error persisting token transfers: %s", err.Error())
+		}
+	}
+	if len(decoded.Approvals) > 0 {
+		err = s.dbClient.PersistTokenApprovals(decoded.Approvals)
+		if err != nil {
+			log.Errorf("error persisting token approvals: %s", err.Error())
+		}
+	}
+	if len(decoded.Decoded) > 0 {
+		err = s.dbClient.PersistDecodedLogs(decoded.Decoded)
+		if err != nil {
+			log.Errorf("error persisting decoded logs: %s", err.Error())
+		}
+	}
+}
+
+// processTraces stores the internal calls of the block transactions moving ETH or failing
+func (s *ChainAnalyzer) processTraces(block *spec.AgnosticBlock) {
+	if len(block.ExecutionPayload.Transactions) == 0 {
+		return
+	}
+	traces, err := s.cli.TraceBlock(*block)
+	if err != nil {
+		log.Errorf("error tracing slot %d: %s", block.Slot, err.Error())
+		return
+	}
+	for _, trace := range traces {
+		if trace.Error != "" {
+			log.Warnf("could not trace tx %s at slot %d: %s", trace.TxHash, block.Slot, trace.Error)
+		}
+	}
+	calls := spec.ParseInternalCalls(*block, traces)
+	if len(calls) == 0 {
+		return
+	}
+	err = s.dbClient.PersistInternalCalls(calls)
+	if err != nil {
+		log.Errorf("error persisting internal calls: %s", err.Error())
+	}
+}
+
 // Process consensus layer deposits
 func (s *ChainAnalyzer) processDeposits(block *spec.AgnosticBlock) {
 	if len(block.Deposits) == 0 {
@@ -146,6 +217,14 @@ func (s *ChainAnalyzer) processBLSToExecutionChanges(block *spec.AgnosticBlock)
 }
 
 func (s *ChainAnalyzer) processWithdrawals(block *spec.AgnosticBlock) {
+	err := s.dbClient.PersistWithdrawals(blockWithdrawals(block))
+	if err != nil {
+		log.Errorf("error persisting withdrawals: %s", err.Error())
+	}
+
+}
+
+func blockWithdrawals(block *spec.AgnosticBlock) []spec.Withdrawal {
 	var withdrawals []spec.Withdrawal
 	for _, item := range block.ExecutionPayload.Withdrawals {
 		withdrawals = append(withdrawals, spec.Withdrawal{
@@ -154,14 +233,10 @@ func (s *ChainAnalyzer) processWithdrawals(block *spec.AgnosticBlock) {
 			ValidatorIndex: item.ValidatorIndex,
 			Address:        item.Address,
 			Amount:         item.Amount,
+			BlockRoot:      block.Root,
 		})
 	}
-
-	err := s.dbClient.PersistWithdrawals(withdrawals)
-	if err != nil {
-		log.Errorf("error persisting withdrawals: %s", err.Error())
-	}
-
+	return withdrawals
 }
 
 func (s *ChainAnalyzer) processTransactions(block *spec.AgnosticBlock, receipts []*types.Receipt) error {
@@ -178,6 +253,19 @@ func (s *ChainAnalyzer) processTransactions(block *spec.AgnosticBlock, receipts
 	err = s.dbClient.PersistTransactions(txs)
 	if err != nil {
 		log.Errorf("error persisting transactions: %s", err.Error())
+		return err
+	}
+
+	authorizations := make([]spec.AgnosticAuthorization, 0)
+	for _, tx := range txs {
+		authorizations = append(authorizations, tx.Authorizations...)
+	}
+	if len(authorizations) == 0 {
+		return nil
+	}
+	err = s.dbClient.PersistAuthorizations(authorizations)
+	if err != nil {
+		log.Errorf("error persisting transaction authorizations: %s", err.Error())
 	}
 	return err
 }
@@ -226,11 +314,161 @@ func (s *ChainAnalyzer) processBlobSidecars(block *spec.AgnosticBlock, txs []spe
 		log.Errorf("could not download blobs for slot %d: %s", block.Slot, err)
 	}
 	if len(blobs) > 0 {
-		if len(txs) > 0 {
-			for _, blob := range blobs {
-				blob.GetTxHash(txs)
+		matchBlobSidecars(block, blobs, txs)
+		s.verifyBlobSidecars(block, blobs, txs)
+		s.storeBlobs(blobs)
+		s.dbClient.PersistBlobSidecars(blobs)
+	}
+}
+
+// storeBlobs writes the blob contents to the blob store, the rows only keep their key.
+// A blob that could not be written is persisted without a key.
+func (s *ChainAnalyzer) storeBlobs(blobs []*spec.AgnosticBlobSidecar) {
+	if s.blobStore == nil {
+		return
+	}
+	for _, blob := range blobs {
+		key, err := blobstore.Key(blob.BlobHash)
+		if err != nil {
+			log.Errorf("could not store blob %d of slot %d: %s", blob.Index, blob.Slot, err)
+			continue
+		}
+		if err := s.blobStore.Put(s.ctx, key, blob.Blob[:]); err != nil {
+			log.Errorf("could not store blob %d of slot %d in %s: %s", blob.Index, blob.Slot, s.blobStore, err)
+			continue
+		}
+		blob.StorageKey = key
+	}
+}
+
+// processDataColumnSidecars records the columns of the block the beacon node holds, so that
+// data availability can be followed without a supernode.
+func (s *ChainAnalyzer) processDataColumnSidecars(block *spec.AgnosticBlock) {
+	columns, err := s.cli.RequestDataColumnSidecars(block.Slot, block.Root)
+	if err != nil {
+		log.Errorf("could not download data columns for slot %d: %s", block.Slot, err)
+		return
+	}
+	if len(columns) > 0 {
+		s.dbClient.PersistDataColumnSidecars(columns)
+	}
+}
+
+func matchBlobSidecars(block *spec.AgnosticBlock, blobs []*spec.AgnosticBlobSidecar, txs []spec.AgnosticTransaction) {
+	for _, blob := range blobs {
+		blob.BlockRoot = block.Root
+	}
+	if len(txs) > 0 {
+		for _, blob := range blobs {
+			blob.GetTxHash(txs)
+		}
+	}
+	block.BlobsFill = make([]spec.BlobFill, 0, len(blobs))
+	for _, blob := range blobs {
+		block.BlobsFill = append(block.BlobsFill, blob.Fill())
+	}
+}
+
+// verifyBlobSidecars checks the blobs against their KZG commitments and proofs, and their
+// versioned hashes against the ones of the block transactions when these were parsed.
+func (s *ChainAnalyzer) verifyBlobSidecars(block *spec.AgnosticBlock, blobs []*spec.AgnosticBlobSidecar, txs []spec.AgnosticTransaction) {
+	if s.kzg == nil {
+		return
+	}
+	withProof := block.HardForkVersion < eth2_client_spec.DataVersionFulu
+	for _, blob := range blobs {
+		blob.KZGVerification = s.kzg.Verify(blob, withProof)
+	}
+	checkBlobHashes(blobs, txs)
+	for _, blob := range blobs {
+		blobKZGVerifications.WithLabelValues(blob.KZGVerification).Inc()
+		if !spec.KZGVerified(blob.KZGVerification) {
+			log.Warnf("blob %d of slot %d (%s) failed the KZG verification: %s",
+				blob.Index, block.Slot, block.Root, blob.KZGVerification)
+		}
+	}
+}
+
+// checkBlobHashes flags the verified blobs whose versioned hash is not the one at the same
+// position in the blob hashes of the block transactions, in the order they were included.
+func checkBlobHashes(blobs []*spec.AgnosticBlobSidecar, txs []spec.AgnosticTransaction) {
+	if len(txs) == 0 {
+		return // transactions not parsed
+	}
+	blobHashes := make([]string, 0, len(blobs))
+	for _, tx := range txs {
+		for _, hash := range tx.BlobHashes {
+			blobHashes = append(blobHashes, hash.String())
+		}
+	}
+	for _, blob := range blobs {
+		if !spec.KZGVerified(blob.KZGVerification) {
+			continue
+		}
+		if int(blob.Index) >= len(blobHashes) || blobHashes[blob.Index] != blob.BlobHash {
+			blob.KZGVerification = spec.KZGHashMismatch
+		}
+	}
+}
+
+// ProcessOrphan persists a block that is no longer canonical, together with its
+// transactions, withdrawals, blob sidecars and attestations. The content is requested
+// by block hash and root, as the slot and block number now belong to the canonical block.
+func (s *ChainAnalyzer) ProcessOrphan(block spec.AgnosticBlock) {
+	err := s.dbClient.PersistOrphans([]spec.AgnosticBlock{block})
+	if err != nil {
+		log.Errorf("error persisting orphan at slot %d: %s", block.Slot, err)
+	}
+
+	err = s.dbClient.PersistOrphanedAttestations(spec.ParseAttestationsFromBlock(block))
+	if err != nil {
+		log.Errorf("error persisting orphaned attestations: %s", err.Error())
+	}
+	err = s.dbClient.PersistOrphanedWithdrawals(blockWithdrawals(&block))
+	if err != nil {
+		log.Errorf("error persisting orphaned withdrawals: %s", err.Error())
+	}
+
+	var txs []spec.AgnosticTransaction
+	if s.metrics.Transactions && len(block.ExecutionPayload.Transactions) > 0 {
+		receipts, err := s.cli.GetOrphanedBlockReceipts(block)
+		if err != nil {
+			log.Errorf("error getting orphaned slot %d receipts: %s", block.Slot, err.Error())
+		} else if txs, err = spec.ParseTransactionsFromBlock(block, receipts); err != nil {
+			log.Errorf("error getting orphaned slot %d transactions: %s", block.Slot, err.Error())
+		} else if len(txs) > 0 {
+			err = s.dbClient.PersistOrphanedTransactions(txs)
+			if err != nil {
+				log.Errorf("error persisting orphaned transactions: %s", err.Error())
+			}
+			authorizations := make([]spec.AgnosticAuthorization, 0)
+			for _, tx := range txs {
+				authorizations = append(authorizations, tx.Authorizations...)
+			}
+			if len(authorizations) > 0 {
+				err = s.dbClient.PersistOrphanedAuthorizations(authorizations)
+				if err != nil {
+					log.Errorf("error persisting orphaned transaction authorizations: %s", err.Error())
+				}
 			}
 		}
-		s.dbClient.PersistBlobSidecars(blobs)
+	}
+
+	if block.HardForkVersion >= eth2_client_spec.DataVersionDeneb && s.metrics.BlobSidecars {
+		var blobs []*spec.AgnosticBlobSidecar
+		if block.HardForkVersion >= eth2_client_spec.DataVersionFulu {
+			blobs, err = s.cli.RequestFuluBlobsByRoot(block.Slot, block.Root)
+		} else {
+			blobs, err = s.cli.RequestBlobSidecarsByRoot(block.Slot, block.Root)
+		}
+		if err != nil {
+			log.Errorf("could not download blobs for orphaned slot %d: %s", block.Slot, err)
+		}
+		if len(blobs) > 0 {
+			matchBlobSidecars(&block, blobs, txs)
+			s.verifyBlobSidecars(&block, blobs, txs)
+			s.storeBlobs(blobs)
+			s.dbClient.PersistOrphanedBlobSidecars(blobs)
+		}
 	}
 }
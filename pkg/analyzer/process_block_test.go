@@ -0,0 +1,46 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckBlobHashes(t *testing.T) {
+	newBlob := func(index uint64, commitment byte) *spec.AgnosticBlobSidecar {
+		kzgCommitment := deneb.KZGCommitment{commitment}
+		return &spec.AgnosticBlobSidecar{
+			Index:           deneb.BlobIndex(index),
+			KZGCommitment:   kzgCommitment,
+			BlobHash:        spec.KZGCommitmentToVersionedHash(kzgCommitment),
+			KZGVerification: spec.KZGValid,
+		}
+	}
+	blobs := []*spec.AgnosticBlobSidecar{newBlob(0, 1), newBlob(1, 2), newBlob(2, 3), newBlob(3, 4)}
+	blobs[2].KZGVerification = spec.KZGValidNoProof
+	blobs[3].KZGVerification = spec.KZGInvalidCommitment
+	hashOf := func(blob *spec.AgnosticBlobSidecar) common.Hash {
+		return common.HexToHash(blob.BlobHash)
+	}
+	txs := []spec.AgnosticTransaction{
+		{BlobHashes: []common.Hash{hashOf(blobs[0])}},
+		{}, // no blobs
+		// the hashes of the second and third blobs swapped
+		{BlobHashes: []common.Hash{hashOf(blobs[2]), hashOf(blobs[1])}},
+	}
+
+	checkBlobHashes(blobs, txs)
+	assert.Equal(t, spec.KZGValid, blobs[0].KZGVerification)
+	assert.Equal(t, spec.KZGHashMismatch, blobs[1].KZGVerification)
+	assert.Equal(t, spec.KZGHashMismatch, blobs[2].KZGVerification)
+	// already failed, and no transaction references it
+	assert.Equal(t, spec.KZGInvalidCommitment, blobs[3].KZGVerification)
+
+	// without parsed transactions the hashes are not checked
+	unchecked := []*spec.AgnosticBlobSidecar{newBlob(5, 6)}
+	checkBlobHashes(unchecked, nil)
+	assert.Equal(t, spec.KZGValid, unchecked[0].KZGVerification)
+}
@@ -2,8 +2,11 @@ package analyzer
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	v1 "github.com/attestantio/go-relay-client/api/v1"
+	"github.com/migalabs/goteth/pkg/builders"
 	"github.com/migalabs/goteth/pkg/db"
 	"github.com/migalabs/goteth/pkg/relay"
 	"github.com/migalabs/goteth/pkg/spec"
@@ -117,6 +120,10 @@ func (s *ChainAnalyzer) processSlashings(bundle metrics.StateMetrics) {
 // storeDepositsProcessed stores the deposits processed from electra + in the database
 func (s *ChainAnalyzer) storeDepositsProcessed(bundle metrics.StateMetrics) {
 	depositsProcessed := bundle.GetMetricsBase().NextState.DepositsProcessed
+	if bundle.GetMetricsBase().NextState.NonArchival {
+		// deposits are only known once the next pending deposits queue is available
+		depositsProcessed = bundle.GetMetricsBase().CurrentState.DepositsProcessed
+	}
 	if len(depositsProcessed) == 0 {
 		return
 	}
@@ -299,64 +306,32 @@ func (s *ChainAnalyzer) processEpochValRewards(bundle metrics.StateMetrics) {
 		}
 	}
 
-	if s.rewardsAggregationEpochs > 1 {
-		s.validatorsRewardsAggregationsMu.Lock()
-
-		epoch := bundle.GetMetricsBase().NextState.Epoch
-
-		// Only aggregate if:
-		//  1. The epoch belongs to the current window (rejects stale epochs
-		//     from already-flushed windows reprocessed by AdvanceFinalized).
-		//  2. The epoch hasn't been aggregated yet (rejects duplicate calls
-		//     for the same epoch, e.g. AdvanceFinalized reprocessing an epoch
-		//     that the normal flow already handled). Using a set of seen
-		//     epochs instead of a counter prevents the cumulative window
-		//     shift described in #255.
-		inWindow := epoch >= s.startEpochAggregation && epoch <= s.endEpochAggregation
-		_, alreadySeen := s.aggregatedEpochsInWindow[epoch]
-
-		if inWindow && !alreadySeen {
-			for _, maxRewards := range insertValsObj {
-				valIdx := maxRewards.ValidatorIndex
-				if _, ok := s.validatorsRewardsAggregations[valIdx]; !ok {
-					s.validatorsRewardsAggregations[valIdx] = spec.NewValidatorRewardsAggregation(valIdx, s.startEpochAggregation, s.endEpochAggregation)
-				}
-				s.validatorsRewardsAggregations[valIdx].Aggregate(maxRewards)
-			}
-
-			s.aggregatedEpochsInWindow[epoch] = true
-
-			if len(s.aggregatedEpochsInWindow) >= s.rewardsAggregationEpochs {
-				if len(s.validatorsRewardsAggregations) > 0 {
-					err := s.dbClient.PersistValidatorRewardsAggregation(s.validatorsRewardsAggregations)
-					if err != nil {
-						log.Fatalf("error persisting validator rewards aggregation: %s", err.Error())
-					}
-				}
-				s.validatorsRewardsAggregations = make(map[phase0.ValidatorIndex]*spec.ValidatorRewardsAggregation)
-				s.startEpochAggregation = s.endEpochAggregation + 1
-				s.endEpochAggregation = s.endEpochAggregation + phase0.Epoch(s.rewardsAggregationEpochs)
-				s.aggregatedEpochsInWindow = make(map[phase0.Epoch]bool)
-			}
-		}
-
-		s.validatorsRewardsAggregationsMu.Unlock()
+	if len(s.rewardsLevels) > 0 {
+		s.aggregateRewards(bundle.GetMetricsBase().NextState.Epoch, insertValsObj)
 	}
-
 }
 
 func (s *ChainAnalyzer) processBlockRewards(bundle metrics.StateMetrics) {
 
 	blockRewards := make([]db.BlockReward, 0)
+	blocksCompliance := make([]db.BlockCompliance, 0)
 
 	mevBids, err := s.relayCli.GetDeliveredBidsPerSlotRange(bundle.GetMetricsBase().CurrentState.Slot, spec.SlotsPerEpoch)
 	if err != nil {
 		log.Errorf("error getting mev bids: %s", err.Error())
 	}
+	// builders also bid for the slots built locally, which no relay delivered
+	proposedSlots := make([]phase0.Slot, 0, len(bundle.GetMetricsBase().CurrentState.Blocks))
+	for _, block := range bundle.GetMetricsBase().CurrentState.Blocks {
+		if block.Proposed {
+			proposedSlots = append(proposedSlots, block.Slot)
+		}
+	}
+	topBids := s.relayCli.GetTopReceivedBidValues(proposedSlots)
 
 	for _, block := range bundle.GetMetricsBase().CurrentState.Blocks {
 		// Wait for ProcessBlock to finish appending transactions before reading
-		// them in BlockGasFees(). Without this, AgnosticTransactions may be empty
+		// them in BlockFees(). Without this, AgnosticTransactions may be empty
 		// and f_reward_fees/f_burnt_fees are written as 0 (see #249).
 		slotKey := fmt.Sprintf("%s%d", slotProcesserTag, block.Slot)
 		s.processerBook.WaitUntilInactive(slotKey)
@@ -365,30 +340,50 @@ func (s *ChainAnalyzer) processBlockRewards(bundle metrics.StateMetrics) {
 		// By now the EL may have recovered from the transient issue (#251).
 		s.recoverBlockReceipts(block)
 
-		blockRewards = append(blockRewards, s.getSingleBlockRewards(*block, mevBids))
+		blockReward := s.getSingleBlockRewards(*block, mevBids, topBids[block.Slot])
+		blockRewards = append(blockRewards, blockReward)
+
+		if s.compliance != nil {
+			if blockCompliance, ok := s.classifyBlock(*block, blockReward); ok {
+				blocksCompliance = append(blocksCompliance, blockCompliance)
+			}
+		}
 	}
 
 	s.dbClient.PersistBlockRewards(blockRewards)
+	if s.compliance != nil {
+		s.persistCompliance(bundle.GetMetricsBase().CurrentState.Epoch, blocksCompliance)
+	}
+	if s.rollups != nil {
+		usages := aggregateRollupBlobs(bundle.GetMetricsBase().CurrentState.Epoch, bundle.GetMetricsBase().CurrentState.Blocks, s.rollups)
+		if len(usages) > 0 {
+			s.dbClient.PersistRollupBlobUsage(usages)
+		}
+	}
+	if s.metrics.Block {
+		diversity := aggregateClientDiversity(bundle.GetMetricsBase().CurrentState.Epoch, bundle.GetMetricsBase().CurrentState.Blocks)
+		if len(diversity) > 0 {
+			s.dbClient.PersistClientDiversity(diversity)
+		}
+	}
 
 }
 
 func (s *ChainAnalyzer) getSingleBlockRewards(
 	block spec.AgnosticBlock,
-	mevBids *relay.RelayBidsPerSlot) db.BlockReward {
+	mevBids *relay.RelayBidsPerSlot,
+	topBid *big.Int) db.BlockReward {
 	slot := block.Slot
 	bids := mevBids.GetBidsAtSlot(slot)
 	clManualReward := block.ManualReward
 	clApiReward := phase0.Gwei(block.Reward.Data.Total)
-	var err error
 
 	// obtain
-	burntFees := uint64(0)
-	rewardFees := uint64(0)
-	bidCommision := uint64(0)
+	bidCommision := new(big.Int)
 	relayAddresses := make([]string, 0)
 	builderPubkeys := make([]string, 0)
 
-	rewardFees, burntFees, err = block.BlockGasFees()
+	fees, err := block.BlockFees()
 	if err != nil {
 		log.Warnf("block at slot %d gas fees not calculated: %s", slot, err)
 	}
@@ -401,20 +396,80 @@ func (s *ChainAnalyzer) getSingleBlockRewards(
 			bidBlockHash := bid.BlockHash
 
 			if blockHash == bidBlockHash {
-				bidCommision = bid.Value.Uint64()
+				if bid.Value != nil {
+					bidCommision = new(big.Int).Set(bid.Value)
+				}
 				relayAddresses = append(relayAddresses, address)
 				builderPubkeys = append(builderPubkeys, bid.BuilderPubkey.String())
 			}
 		}
 	}
+	builder := s.builders.Identify(
+		string(block.ExecutionPayload.ExtraData),
+		block.ExecutionPayload.FeeRecipient.String(),
+		builderPubkeys,
+		relayAddresses)
+
+	values := compareBlockValue(builder.Type, fees.Tips, bidCommision, len(relayAddresses) > 0, topBid, bids)
+
+	clReward := clApiReward
+	if clReward == 0 {
+		clReward = clManualReward
+	}
+
 	return db.BlockReward{
 		Slot:           slot,
 		CLManualReward: clManualReward,
 		CLApiReward:    clApiReward,
-		RewardFees:     rewardFees,
-		BurntFees:      burntFees,
+		RewardFees:     fees.Tips,
+		BurntFees:      fees.BurntFees,
+		BlobBurntFees:  fees.BlobBurntFees,
 		Relays:         relayAddresses,
 		BidCommision:   bidCommision,
 		BuilderPubkeys: builderPubkeys,
+		Builder:        builder.Builder,
+		BuildType:      string(builder.Type),
+		ELReward:       values.ELReward,
+		CLReward:       clReward,
+		MaxBidValue:    values.MaxBidValue,
+		ValueLeft:      values.ValueLeft,
+	}
+}
+
+// blockValue compares what the proposer earned on the execution layer with the
+// best bid the relays received for the slot, all in Wei.
+type blockValue struct {
+	ELReward    *big.Int // bid value when delivered by a relay, priority fees otherwise
+	MaxBidValue *big.Int // highest bid received or delivered by any relay for the slot
+	ValueLeft   *big.Int // for local builds, what the best bid would have paid on top
+}
+
+func compareBlockValue(
+	buildType builders.BuildType,
+	priorityFees *big.Int,
+	bidValue *big.Int,
+	delivered bool,
+	topBid *big.Int,
+	bids map[string]v1.BidTrace) blockValue {
+
+	value := blockValue{
+		ELReward:    new(big.Int).Set(priorityFees),
+		MaxBidValue: new(big.Int),
+		ValueLeft:   new(big.Int),
+	}
+	if delivered {
+		value.ELReward.Set(bidValue)
+	}
+	if topBid != nil {
+		value.MaxBidValue.Set(topBid)
+	}
+	for _, bid := range bids {
+		if bid.Value != nil && bid.Value.Cmp(value.MaxBidValue) > 0 {
+			value.MaxBidValue.Set(bid.Value)
+		}
+	}
+	if buildType == builders.LocalBuild && value.MaxBidValue.Cmp(value.ELReward) > 0 {
+		value.ValueLeft.Sub(value.MaxBidValue, value.ELReward)
 	}
+	return value
 }
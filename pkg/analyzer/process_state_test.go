@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"math/big"
+	"testing"
+
+	v1 "github.com/attestantio/go-relay-client/api/v1"
+	"github.com/migalabs/goteth/pkg/builders"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertBlockValue(t *testing.T, elReward, maxBidValue, valueLeft int64, value blockValue) {
+	t.Helper()
+	assert.Equal(t, big.NewInt(elReward).String(), value.ELReward.String())
+	assert.Equal(t, big.NewInt(maxBidValue).String(), value.MaxBidValue.String())
+	assert.Equal(t, big.NewInt(valueLeft).String(), value.ValueLeft.String())
+}
+
+func TestCompareBlockValue(t *testing.T) {
+	bids := map[string]v1.BidTrace{
+		"relay-a": {Value: big.NewInt(3_000)},
+		"relay-b": {Value: big.NewInt(5_000)},
+		"relay-c": {},
+	}
+
+	// local build while the relays delivered a better payload
+	value := compareBlockValue(builders.LocalBuild, big.NewInt(1_000), new(big.Int), false, nil, bids)
+	assertBlockValue(t, 1_000, 5_000, 4_000, value)
+
+	// local build earning more than any delivered bid
+	value = compareBlockValue(builders.LocalBuild, big.NewInt(6_000), new(big.Int), false, nil, bids)
+	assertBlockValue(t, 6_000, 5_000, 0, value)
+
+	// relay build: the proposer earns the bid, not the priority fees
+	value = compareBlockValue(builders.RelayBuild, big.NewInt(7_000), big.NewInt(5_000), true, nil, bids)
+	assertBlockValue(t, 5_000, 5_000, 0, value)
+
+	// local build with no payload delivered, builders still bid for the slot
+	value = compareBlockValue(builders.LocalBuild, big.NewInt(1_000), new(big.Int), false, big.NewInt(8_000), nil)
+	assertBlockValue(t, 1_000, 8_000, 7_000, value)
+
+	// a delivered bid missing from the received ones still counts
+	value = compareBlockValue(builders.LocalBuild, big.NewInt(1_000), new(big.Int), false, big.NewInt(2_000), bids)
+	assertBlockValue(t, 1_000, 5_000, 4_000, value)
+
+	// no bids at all
+	value = compareBlockValue(builders.UnknownBuild, big.NewInt(2_000), new(big.Int), false, nil, nil)
+	assertBlockValue(t, 2_000, 0, 0, value)
+
+	// bids above 64 bits are compared exactly
+	huge, _ := new(big.Int).SetString("100000000000000000000000", 10)
+	value = compareBlockValue(builders.LocalBuild, big.NewInt(1), new(big.Int), false, nil,
+		map[string]v1.BidTrace{"relay-a": {Value: huge}})
+	assert.Equal(t, "100000000000000000000000", value.MaxBidValue.String())
+	assert.Equal(t, "99999999999999999999999", value.ValueLeft.String())
+}
@@ -25,6 +25,15 @@ var (
 		Name:      "block_queue_length",
 		Help:      "The number of blocks int the history queue",
 	})
+	blobKZGVerifications = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: strings.ToLower(utils.CliName),
+			Subsystem: modName,
+			Name:      "blob_kzg_verifications_total",
+			Help:      "Total number of downloaded blobs verified against their KZG commitments, by result",
+		},
+		[]string{"result"},
+	)
 )
 
 func (c *ChainAnalyzer) GetPrometheusMetrics() *metrics.MetricsModule {
@@ -36,6 +45,9 @@ func (c *ChainAnalyzer) GetPrometheusMetrics() *metrics.MetricsModule {
 
 	metricsMod.AddIndvMetric(c.getStateHistoryLength())
 	metricsMod.AddIndvMetric(c.getBlockHistoryLength())
+	if c.kzg != nil {
+		metricsMod.AddIndvMetric(c.getBlobKZGVerifications())
+	}
 
 	return metricsMod
 }
@@ -91,3 +103,28 @@ func (p *ChainAnalyzer) getBlockHistoryLength() *metrics.IndvMetrics {
 
 	return indvMetr
 }
+
+func (p *ChainAnalyzer) getBlobKZGVerifications() *metrics.IndvMetrics {
+
+	initFn := func() error {
+		prometheus.MustRegister(blobKZGVerifications)
+		return nil
+	}
+
+	// the counter is increased as blobs are verified
+	updateFn := func() (interface{}, error) {
+		return nil, nil
+	}
+
+	indvMetr, err := metrics.NewIndvMetrics(
+		"blob_kzg_verifications",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init blob_kzg_verifications"))
+		return nil
+	}
+
+	return indvMetr
+}
@@ -3,15 +3,20 @@ package analyzer
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
 	"github.com/migalabs/goteth/pkg/spec"
 )
 
 func (s *ChainAnalyzer) AdvanceFinalized(newFinalizedSlot phase0.Slot) {
 
 	finalizedEpoch := newFinalizedSlot / spec.SlotsPerEpoch
+	// epochs are only final once their block and state roots are checked,
+	// up to the first one that could not be
+	checkedEpoch := phase0.Epoch(finalizedEpoch)
 
 	stateKeys := s.downloadCache.StateHistory.GetKeyList()
 
@@ -22,6 +27,7 @@ func (s *ChainAnalyzer) AdvanceFinalized(newFinalizedSlot phase0.Slot) {
 
 	advance := false
 	epochsWithChangedBlocks := make(map[uint64]bool)
+	transitions := make([]db.FinalityTransition, 0)
 
 	for _, epoch := range stateKeys {
 		if epoch >= uint64(finalizedEpoch) {
@@ -34,6 +40,7 @@ func (s *ChainAnalyzer) AdvanceFinalized(newFinalizedSlot phase0.Slot) {
 		// because state processing reads block data (e.g. isFlagPossible
 		// uses prevState.Blocks to decide the head attester reward).
 		blocksChanged := false
+		rewrittenSlots := uint8(0)
 		for slot := (epoch * spec.SlotsPerEpoch); slot < ((epoch + 1) * spec.SlotsPerEpoch); slot++ {
 
 			cacheBlock, err := s.downloadCache.BlockHistory.Wait(s.ctx, slot)
@@ -48,10 +55,17 @@ func (s *ChainAnalyzer) AdvanceFinalized(newFinalizedSlot phase0.Slot) {
 				log.Warnf("cache block root: %s\nfinalized block root: %s", cacheBlockRoot, finalizedBlockRoot)
 				log.Warnf("block root for block (slot=%d) incorrect, redownload", cacheBlock.Slot)
 
-				s.dbClient.DeleteBlockMetrics(phase0.Slot(slot))
+				oldBlock := *cacheBlock
+				s.DownloadBlock(phase0.Slot(slot)) // replaces the block in the cache
+				if oldBlock.Proposed {
+					s.ProcessOrphan(oldBlock)
+				}
+
+				// rows are inserted again with a newer version, no need to delete the previous ones
 				log.Infof("rewriting metrics for slot %d", slot)
 				s.ProcessBlock(phase0.Slot(slot))
 				blocksChanged = true
+				rewrittenSlots++
 			}
 		}
 
@@ -73,6 +87,7 @@ func (s *ChainAnalyzer) AdvanceFinalized(newFinalizedSlot phase0.Slot) {
 		finalizedStateRoot, err := s.cli.RequestStateRoot(phase0.Slot(cacheState.Slot))
 		if err != nil {
 			log.Errorf("could not get state root at slot %d: %s", cacheState.Slot, err)
+			checkedEpoch = min(checkedEpoch, phase0.Epoch(epoch))
 			continue
 		}
 
@@ -113,12 +128,29 @@ func (s *ChainAnalyzer) AdvanceFinalized(newFinalizedSlot phase0.Slot) {
 			// to block forever. Re-download any that are missing. (#245)
 			s.ensureDependencyStates(epoch)
 
-			s.dbClient.DeleteStateMetrics(phase0.Epoch(epoch))
 			log.Infof("rewriting metrics for epoch %d (stateRootChanged=%t, blocksChanged=%t, dep=%t)",
 				epoch, stateRootChanged, blocksChanged,
 				(epoch >= 1 && epochsWithChangedBlocks[epoch-1]) || (epoch >= 2 && epochsWithChangedBlocks[epoch-2]))
 			s.ProcessStateTransitionMetrics(phase0.Epoch(epoch))
 		}
+
+		transitions = append(transitions, db.FinalityTransition{
+			Epoch:          phase0.Epoch(epoch),
+			FinalizedEpoch: phase0.Epoch(finalizedEpoch),
+			StateRoot:      finalizedStateRoot,
+			RewrittenSlots: rewrittenSlots,
+			StateRewritten: needsReprocess,
+			Timestamp:      time.Now(),
+		})
+	}
+
+	s.dbClient.SetFinalizedEpoch(checkedEpoch)
+
+	if len(transitions) > 0 {
+		err := s.dbClient.PersistFinalityTransitions(transitions)
+		if err != nil {
+			log.Errorf("could not persist finality transitions: %s", err)
+		}
 	}
 
 	s.downloadCache.CleanUpTo(newFinalizedSlot)
@@ -168,6 +200,13 @@ func (s *ChainAnalyzer) HandleReorg(newReorg v1.ChainReorgEvent) {
 
 	reorgedSlots := uint64(0)
 
+	s.reorgMu.Lock()
+	defer s.reorgMu.Unlock()
+
+	// the blocks before being replaced, to record what the old branch lost
+	cached := s.cachedBlocksByRoot()
+	var oldHead, newHead *spec.AgnosticBlock
+
 	cacheHeadBlock := s.downloadCache.GetHeadBlock()
 	i := cacheHeadBlock.Slot
 
@@ -193,11 +232,16 @@ func (s *ChainAnalyzer) HandleReorg(newReorg v1.ChainReorgEvent) {
 
 		if newBlock.Root != oldBlock.Root { // only rewrite if stateroots are different
 			if block.Proposed { // keep orphans -> if previous block was proposed and roots have changed
-				s.dbClient.PersistOrphans([]spec.AgnosticBlock{oldBlock})
+				s.ProcessOrphan(oldBlock)
+				if oldHead == nil {
+					oldHead = &oldBlock
+				}
+			}
+			if newBlock.Proposed && newHead == nil {
+				newHead = newBlock
 			}
-			s.dbClient.DeleteBlockMetrics(i)
 			log.Infof("rewriting metrics for slot %d", i)
-			// write slot metrics
+			// write slot metrics, superseding the previous version
 			s.ProcessBlock(i)
 		} else {
 			log.Infof("reorg slot %d: block roots are the same", i)
@@ -221,13 +265,22 @@ func (s *ChainAnalyzer) HandleReorg(newReorg v1.ChainReorgEvent) {
 			}
 
 			if newState.StateRoot != oldState.StateRoot {
-				s.dbClient.DeleteStateMetrics(epoch)
 				log.Infof("rewriting metrics for epoch %d", epoch)
-				// write epoch metrics
+				// write epoch metrics, superseding the previous version
 				s.ProcessStateTransitionMetrics(epoch)
 			}
 		}
 		i -= 1
 	}
 
+	if s.metrics.Block && oldHead != nil && newHead != nil {
+		// the new branch is cached by now, requested only if below the replaced slots
+		replaced := s.cachedBlocksByRoot()
+		s.analyzeFork(cached, oldHead, newHead, func(root phase0.Root) (*spec.AgnosticBlock, error) {
+			if block, ok := replaced[root]; ok {
+				return block, nil
+			}
+			return s.cli.RequestBeaconBlockByRoot(root)
+		})
+	}
 }
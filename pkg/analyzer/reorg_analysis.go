@@ -0,0 +1,245 @@
+package analyzer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	// slots walked back looking for the previous block or the common ancestor of a fork
+	maxReorgAnalysisDepth = phase0.Slot(2 * spec.SlotsPerEpoch)
+)
+
+// AnalyzeParentRoot checks that the block downloaded at the slot builds on the previous
+// block of the cache. If its parent root points elsewhere the chain forked while we were
+// downloading it, in historical or head mode alike: both branches are walked back to
+// their common ancestor to record what the old one lost.
+// Forks announced by a reorg event are recorded by HandleReorg instead, which holds the
+// reorg lock while replacing the blocks so the cache is not read half replaced.
+func (s *ChainAnalyzer) AnalyzeParentRoot(slot phase0.Slot) {
+	if !s.metrics.Block {
+		return
+	}
+	_, err := s.downloadCache.BlockHistory.Wait(s.ctx, SlotTo[uint64](slot))
+	if err != nil {
+		log.Errorf("context cancelled waiting for block at slot %d: %s", slot, err)
+		return
+	}
+
+	s.reorgMu.Lock()
+	defer s.reorgMu.Unlock()
+
+	// a reorg handled meanwhile may have replaced it
+	block, err := s.downloadCache.BlockHistory.Wait(s.ctx, SlotTo[uint64](slot))
+	if err != nil {
+		log.Errorf("context cancelled waiting for block at slot %d: %s", slot, err)
+		return
+	}
+	if !block.Proposed {
+		return
+	}
+
+	oldHead, err := s.previousProposedBlock(slot)
+	if err != nil {
+		log.Errorf("context cancelled waiting for the block before slot %d: %s", slot, err)
+		return
+	}
+	if oldHead == nil || oldHead.Root == block.ParentRoot {
+		return
+	}
+
+	s.analyzeFork(s.cachedBlocksByRoot(), oldHead, block, s.cli.RequestBeaconBlockByRoot)
+}
+
+// analyzeFork walks the new head back to the first of the cached blocks, requesting the
+// ones missing, and persists what the branch of the old head lost.
+func (s *ChainAnalyzer) analyzeFork(
+	cached map[phase0.Root]*spec.AgnosticBlock,
+	oldHead *spec.AgnosticBlock,
+	newHead *spec.AgnosticBlock,
+	request func(phase0.Root) (*spec.AgnosticBlock, error)) {
+
+	ancestor, newBranch, err := findCommonAncestor(cached, newHead, request)
+	if err != nil {
+		log.Warnf("fork found at slot %d, but could not find the common ancestor: %s", newHead.Slot, err)
+		return
+	}
+	oldBranch := branchFrom(cached, oldHead, ancestor.Root)
+
+	analysis := newReorgAnalysis(oldHead, newHead, ancestor, oldBranch, newBranch)
+	analysis.Mode = s.downloadMode
+	log.Warnf("fork found at slot %d: old head %d, common ancestor %d, %d orphaned blocks, %d dropped transactions",
+		newHead.Slot, oldHead.Slot, ancestor.Slot, analysis.DepthBlocks, len(analysis.DroppedTxHashes))
+
+	err = s.dbClient.PersistReorgAnalysis([]db.ReorgAnalysis{analysis})
+	if err != nil {
+		log.Errorf("could not persist the reorg analysis at slot %d: %s", newHead.Slot, err)
+	}
+}
+
+// previousProposedBlock returns the latest proposed block of the cache before the slot,
+// nil if there is none within reach.
+func (s *ChainAnalyzer) previousProposedBlock(slot phase0.Slot) (*spec.AgnosticBlock, error) {
+	lowest := uint64(slot)
+	for _, key := range s.downloadCache.BlockHistory.GetKeyList() {
+		lowest = min(lowest, key)
+	}
+	lowest = max(lowest, uint64(s.initSlot))
+
+	for i := slot; i > phase0.Slot(lowest) && slot-i < maxReorgAnalysisDepth; {
+		i--
+		// previous slots may still be downloading, but never below the lowest cached one
+		block, err := s.downloadCache.BlockHistory.Wait(s.ctx, SlotTo[uint64](i))
+		if err != nil {
+			return nil, err
+		}
+		if block.Proposed {
+			return block, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *ChainAnalyzer) cachedBlocksByRoot() map[phase0.Root]*spec.AgnosticBlock {
+	blocks := make(map[phase0.Root]*spec.AgnosticBlock)
+	for _, key := range s.downloadCache.BlockHistory.GetKeyList() {
+		block, ok := s.downloadCache.BlockHistory.Get(key)
+		if ok && block.Proposed {
+			blocks[block.Root] = block
+		}
+	}
+	return blocks
+}
+
+// findCommonAncestor follows the parents of the head, requesting the ones missing from the
+// cached blocks, until one of them is cached. Returns that block and the new branch,
+// from the head down to the block after the ancestor.
+func findCommonAncestor(
+	cached map[phase0.Root]*spec.AgnosticBlock,
+	head *spec.AgnosticBlock,
+	request func(phase0.Root) (*spec.AgnosticBlock, error)) (*spec.AgnosticBlock, []*spec.AgnosticBlock, error) {
+
+	branch := []*spec.AgnosticBlock{head}
+	root := head.ParentRoot
+	for {
+		if ancestor, ok := cached[root]; ok {
+			return ancestor, branch, nil
+		}
+		block, err := request(root)
+		if err != nil {
+			return nil, nil, err
+		}
+		if block.Slot >= branch[len(branch)-1].Slot || head.Slot-block.Slot > maxReorgAnalysisDepth {
+			return nil, nil, fmt.Errorf("no common ancestor within %d slots of slot %d", maxReorgAnalysisDepth, head.Slot)
+		}
+		branch = append(branch, block)
+		root = block.ParentRoot
+	}
+}
+
+// branchFrom follows the parents of the head through the cached blocks until the ancestor.
+// Returns the branch from the head down to the block after the ancestor.
+func branchFrom(cached map[phase0.Root]*spec.AgnosticBlock, head *spec.AgnosticBlock, ancestor phase0.Root) []*spec.AgnosticBlock {
+	branch := make([]*spec.AgnosticBlock, 0)
+	for block := head; block != nil && block.Root != ancestor; block = cached[block.ParentRoot] {
+		if len(branch) > 0 && block.Slot >= branch[len(branch)-1].Slot {
+			break
+		}
+		branch = append(branch, block)
+	}
+	return branch
+}
+
+func newReorgAnalysis(
+	oldHead *spec.AgnosticBlock,
+	newHead *spec.AgnosticBlock,
+	ancestor *spec.AgnosticBlock,
+	oldBranch []*spec.AgnosticBlock,
+	newBranch []*spec.AgnosticBlock) db.ReorgAnalysis {
+
+	analysis := db.ReorgAnalysis{
+		NewHeadSlot:       newHead.Slot,
+		NewHeadRoot:       newHead.Root,
+		OldHeadSlot:       oldHead.Slot,
+		OldHeadRoot:       oldHead.Root,
+		AncestorSlot:      ancestor.Slot,
+		AncestorRoot:      ancestor.Root,
+		DepthSlots:        uint64(oldHead.Slot - ancestor.Slot),
+		DepthBlocks:       uint64(len(oldBranch)),
+		NewBranchBlocks:   uint64(len(newBranch)),
+		OrphanedSlots:     make([]phase0.Slot, 0, len(oldBranch)),
+		OrphanedProposers: make([]phase0.ValidatorIndex, 0, len(oldBranch)),
+		Timestamp:         time.Now(),
+	}
+	for _, block := range oldBranch {
+		analysis.OrphanedSlots = append(analysis.OrphanedSlots, block.Slot)
+		analysis.OrphanedProposers = append(analysis.OrphanedProposers, block.ProposerIndex)
+	}
+	analysis.DroppedAttestations, analysis.DroppedTxHashes = droppedContent(oldBranch, newBranch)
+	return analysis
+}
+
+// droppedContent returns the number of attestations and the transactions of the old branch
+// that the new branch does not include. Attestations are compared by their data and bits,
+// so the same votes aggregated differently count as dropped.
+func droppedContent(oldBranch []*spec.AgnosticBlock, newBranch []*spec.AgnosticBlock) (uint64, []phase0.Hash32) {
+	included := make(map[string]bool)
+	includedTxs := make(map[phase0.Hash32]bool)
+	for _, block := range newBranch {
+		for _, key := range attestationKeys(block) {
+			included[key] = true
+		}
+		for _, hash := range transactionHashes(block) {
+			includedTxs[hash] = true
+		}
+	}
+
+	attestations := uint64(0)
+	txs := make([]phase0.Hash32, 0)
+	for _, block := range oldBranch {
+		for _, key := range attestationKeys(block) {
+			if !included[key] {
+				attestations++
+			}
+		}
+		for _, hash := range transactionHashes(block) {
+			if !includedTxs[hash] {
+				txs = append(txs, hash)
+			}
+		}
+	}
+	return attestations, txs
+}
+
+func attestationKeys(block *spec.AgnosticBlock) []string {
+	keys := make([]string, 0)
+	for _, attestation := range block.Attestations {
+		keys = append(keys, fmt.Sprintf("%d/%d/%s/%x",
+			attestation.Data.Slot, attestation.Data.Index, attestation.Data.BeaconBlockRoot,
+			attestation.AggregationBits.Bytes()))
+	}
+	for _, attestation := range block.ElectraAttestations {
+		keys = append(keys, fmt.Sprintf("%d/%x/%s/%x",
+			attestation.Data.Slot, attestation.CommitteeBits.Bytes(), attestation.Data.BeaconBlockRoot,
+			attestation.AggregationBits.Bytes()))
+	}
+	return keys
+}
+
+func transactionHashes(block *spec.AgnosticBlock) []phase0.Hash32 {
+	hashes := make([]phase0.Hash32, 0, len(block.ExecutionPayload.Transactions))
+	for _, tx := range block.ExecutionPayload.Transactions {
+		var parsedTx types.Transaction
+		if err := parsedTx.UnmarshalBinary(tx); err != nil {
+			log.Warnf("could not decode a transaction of slot %d: %s", block.Slot, err)
+			continue
+		}
+		hashes = append(hashes, phase0.Hash32(parsedTx.Hash()))
+	}
+	return hashes
+}
@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBlock(slot phase0.Slot, root byte, parent byte) *spec.AgnosticBlock {
+	return &spec.AgnosticBlock{
+		Slot:          slot,
+		Root:          phase0.Root{root},
+		ParentRoot:    phase0.Root{parent},
+		ProposerIndex: phase0.ValidatorIndex(100 + slot),
+		Proposed:      true,
+	}
+}
+
+func testAttestation(slot phase0.Slot, bits ...uint64) *phase0.Attestation {
+	aggregation := bitfield.NewBitlist(8)
+	for _, bit := range bits {
+		aggregation.SetBitAt(bit, true)
+	}
+	return &phase0.Attestation{
+		AggregationBits: aggregation,
+		Data:            &phase0.AttestationData{Slot: slot, BeaconBlockRoot: phase0.Root{1}},
+	}
+}
+
+func testTransaction(t *testing.T, nonce uint64) (bellatrix.Transaction, phase0.Hash32) {
+	tx := types.NewTx(&types.LegacyTx{Nonce: nonce, To: &common.Address{}, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(1)})
+	raw, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	return raw, phase0.Hash32(tx.Hash())
+}
+
+func TestReorgAnalysis(t *testing.T) {
+	// 10 <- 11 <- 12 is the cached chain, 10 <- 12' <- 13 the new one
+	ancestor := testBlock(10, 1, 0)
+	old11 := testBlock(11, 2, 1)
+	old12 := testBlock(12, 3, 2)
+	new12 := testBlock(12, 4, 1)
+	newHead := testBlock(13, 5, 4)
+
+	kept, dropped := testAttestation(10, 0), testAttestation(10, 1)
+	old11.Attestations = []*phase0.Attestation{kept, dropped}
+	new12.Attestations = []*phase0.Attestation{testAttestation(10, 0)}
+
+	reincluded, reincludedHash := testTransaction(t, 1)
+	lost, lostHash := testTransaction(t, 2)
+	old12.ExecutionPayload.Transactions = []bellatrix.Transaction{reincluded, lost}
+	newHead.ExecutionPayload.Transactions = []bellatrix.Transaction{reincluded}
+
+	cached := map[phase0.Root]*spec.AgnosticBlock{
+		ancestor.Root: ancestor,
+		old11.Root:    old11,
+		old12.Root:    old12,
+	}
+	requested := 0
+	request := func(root phase0.Root) (*spec.AgnosticBlock, error) {
+		requested++
+		if root == new12.Root {
+			return new12, nil
+		}
+		return nil, fmt.Errorf("block %s not found", root)
+	}
+
+	found, newBranch, err := findCommonAncestor(cached, newHead, request)
+	require.NoError(t, err)
+	assert.Equal(t, ancestor, found)
+	assert.Equal(t, []*spec.AgnosticBlock{newHead, new12}, newBranch)
+	assert.Equal(t, 1, requested)
+
+	oldBranch := branchFrom(cached, old12, found.Root)
+	assert.Equal(t, []*spec.AgnosticBlock{old12, old11}, oldBranch)
+
+	analysis := newReorgAnalysis(old12, newHead, found, oldBranch, newBranch)
+	assert.Equal(t, phase0.Slot(13), analysis.NewHeadSlot)
+	assert.Equal(t, phase0.Slot(12), analysis.OldHeadSlot)
+	assert.Equal(t, phase0.Slot(10), analysis.AncestorSlot)
+	assert.Equal(t, uint64(2), analysis.DepthSlots)
+	assert.Equal(t, uint64(2), analysis.DepthBlocks)
+	assert.Equal(t, uint64(2), analysis.NewBranchBlocks)
+	assert.Equal(t, []phase0.Slot{12, 11}, analysis.OrphanedSlots)
+	assert.Equal(t, []phase0.ValidatorIndex{112, 111}, analysis.OrphanedProposers)
+	assert.Equal(t, uint64(1), analysis.DroppedAttestations)
+	assert.Equal(t, []phase0.Hash32{lostHash}, analysis.DroppedTxHashes)
+	assert.NotContains(t, analysis.DroppedTxHashes, reincludedHash)
+}
+
+func TestFindCommonAncestorDepth(t *testing.T) {
+	head := testBlock(1000, 2, 1)
+	request := func(root phase0.Root) (*spec.AgnosticBlock, error) {
+		return testBlock(10, 1, 0), nil
+	}
+	_, _, err := findCommonAncestor(map[phase0.Root]*spec.AgnosticBlock{}, head, request)
+	assert.Error(t, err)
+
+	// a parent that does not go back in slots would loop forever
+	request = func(root phase0.Root) (*spec.AgnosticBlock, error) {
+		return testBlock(1000, 1, 1), nil
+	}
+	_, _, err = findCommonAncestor(map[phase0.Root]*spec.AgnosticBlock{}, head, request)
+	assert.Error(t, err)
+}
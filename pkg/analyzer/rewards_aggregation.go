@@ -0,0 +1,238 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// rewardsAggregationWindow returns the window [start, end] containing the epoch.
+// Windows are aligned to multiples of their size, so every run and every instance
+// produces the same ones regardless of the slot they started at.
+func rewardsAggregationWindow(epoch phase0.Epoch, size int) (phase0.Epoch, phase0.Epoch) {
+	if size <= 1 {
+		return epoch, epoch
+	}
+	start := epoch - epoch%phase0.Epoch(size)
+	return start, start + phase0.Epoch(size) - 1
+}
+
+const (
+	aggregateByValidator = "validator"
+	aggregateByEntity    = "entity"
+)
+
+// rewardsAggregationLevel aggregates the validator rewards over windows of epochs epochs,
+// either per validator (t_validator_rewards_aggregation) or per entity (t_entity_rewards_aggregation).
+type rewardsAggregationLevel struct {
+	epochs  int
+	groupBy string
+}
+
+// newRewardsAggregationLevels returns the validator level of --rewards-aggregation-epochs,
+// if above 1, followed by the comma separated epochs:group levels of --rewards-aggregation-levels,
+// e.g. "1575:validator,225:entity". The group defaults to validator.
+func newRewardsAggregationLevels(epochs int, input string) ([]rewardsAggregationLevel, error) {
+	levels := make([]rewardsAggregationLevel, 0)
+	if epochs > 1 {
+		levels = append(levels, rewardsAggregationLevel{epochs: epochs, groupBy: aggregateByValidator})
+	}
+	if input == "" {
+		return levels, nil
+	}
+
+	for _, item := range strings.Split(input, ",") {
+		epochsStr, groupBy, found := strings.Cut(strings.TrimSpace(item), ":")
+		if !found {
+			groupBy = aggregateByValidator
+		}
+		levelEpochs, err := strconv.Atoi(epochsStr)
+		if err != nil || levelEpochs < 2 {
+			return nil, fmt.Errorf("could not parse aggregation epochs: %s", item)
+		}
+		if groupBy != aggregateByValidator && groupBy != aggregateByEntity {
+			return nil, fmt.Errorf("could not parse aggregation group: %s", item)
+		}
+		level := rewardsAggregationLevel{epochs: levelEpochs, groupBy: groupBy}
+		for _, previous := range levels {
+			if previous == level {
+				return nil, fmt.Errorf("duplicated aggregation level: %s", item)
+			}
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// rewardsWindow aggregates the validator rewards of the epochs [start, end] of a level.
+type rewardsWindow struct {
+	level        rewardsAggregationLevel
+	start        phase0.Epoch
+	end          phase0.Epoch
+	aggregations map[phase0.ValidatorIndex]*spec.ValidatorRewardsAggregation
+	seen         map[phase0.Epoch]bool // epochs aggregated, so that reprocessed ones are not counted twice (#255)
+}
+
+// newRewardsWindow opens the window of the level containing the epoch.
+func newRewardsWindow(epoch phase0.Epoch, level rewardsAggregationLevel) *rewardsWindow {
+	start, end := rewardsAggregationWindow(epoch, level.epochs)
+	return &rewardsWindow{
+		level:        level,
+		start:        start,
+		end:          end,
+		aggregations: make(map[phase0.ValidatorIndex]*spec.ValidatorRewardsAggregation),
+		seen:         make(map[phase0.Epoch]bool),
+	}
+}
+
+func (w *rewardsWindow) aggregate(epoch phase0.Epoch, rewards []spec.ValidatorRewards) {
+	if epoch < w.start || epoch > w.end || w.seen[epoch] {
+		return
+	}
+	for _, item := range rewards {
+		if _, ok := w.aggregations[item.ValidatorIndex]; !ok {
+			w.aggregations[item.ValidatorIndex] = spec.NewValidatorRewardsAggregation(item.ValidatorIndex, w.start, w.end)
+		}
+		w.aggregations[item.ValidatorIndex].Aggregate(item)
+	}
+	w.seen[epoch] = true
+}
+
+// missing returns the epochs of the window that were not aggregated.
+func (w *rewardsWindow) missing() []phase0.Epoch {
+	missing := make([]phase0.Epoch, 0)
+	for epoch := w.start; epoch <= w.end; epoch++ {
+		if !w.seen[epoch] {
+			missing = append(missing, epoch)
+		}
+	}
+	return missing
+}
+
+// byEntity groups the aggregations of the window by the entity of each validator.
+// Validators without an entity are left out.
+func (w *rewardsWindow) byEntity(pools map[phase0.ValidatorIndex]string) map[string]*spec.ValidatorRewardsAggregation {
+	entities := make(map[string]*spec.ValidatorRewardsAggregation)
+	for valIdx, aggregation := range w.aggregations {
+		entity, ok := pools[valIdx]
+		if !ok {
+			continue
+		}
+		if _, ok := entities[entity]; !ok {
+			entities[entity] = spec.NewEntityRewardsAggregation(entity, w.start, w.end)
+		}
+		entities[entity].Merge(*aggregation)
+	}
+	return entities
+}
+
+// initRewardsAggregation opens the window of every level containing firstEpoch, the first epoch
+// whose rewards are going to be processed. The previous epochs of each window are rebuilt from
+// t_validator_rewards_summary, so that a restart in the middle of a window does not persist
+// a partial aggregation. Epochs that are not in the database leave a gap in the window,
+// which is then recorded as incomplete instead of persisted.
+func (s *ChainAnalyzer) initRewardsAggregation(firstEpoch phase0.Epoch) error {
+	s.rewardsWindowsMu.Lock()
+	defer s.rewardsWindowsMu.Unlock()
+
+	for i, level := range s.rewardsLevels {
+		window := newRewardsWindow(firstEpoch, level)
+		s.rewardsWindows[i] = window
+		if firstEpoch == window.start {
+			continue
+		}
+
+		aggregations, epochs, err := s.dbClient.RetrieveValidatorRewardsAggregation(window.start, window.end, firstEpoch)
+		if err != nil {
+			return err
+		}
+		window.aggregations = aggregations
+		for _, epoch := range epochs {
+			window.seen[epoch] = true
+		}
+		log.Infof("restored %s rewards aggregation window %d - %d from %d epochs in the database",
+			level.groupBy, window.start, window.end, len(epochs))
+		if len(epochs) < int(firstEpoch-window.start) {
+			log.Warnf("epochs of the %s aggregation window %d - %d are not in the database, it will be recorded as incomplete",
+				level.groupBy, window.start, window.end)
+		}
+	}
+	return nil
+}
+
+// nextRewardsWindows adds the rewards of an epoch to the open window of every level and returns
+// the windows the epoch closes. Epochs are processed in order, so a window closes with its last
+// epoch, or with a later one if its last epoch was never processed. Epochs of windows already
+// closed, e.g. reprocessed by AdvanceFinalized, are ignored.
+func (s *ChainAnalyzer) nextRewardsWindows(epoch phase0.Epoch, rewards []spec.ValidatorRewards) []*rewardsWindow {
+	closed := make([]*rewardsWindow, 0)
+	for i, level := range s.rewardsLevels {
+		window := s.rewardsWindows[i]
+		if window == nil {
+			window = newRewardsWindow(epoch, level)
+		}
+		if epoch < window.start {
+			continue
+		}
+		if epoch > window.end {
+			closed = append(closed, window)
+			window = newRewardsWindow(epoch, level)
+		}
+		window.aggregate(epoch, rewards)
+		if epoch == window.end {
+			closed = append(closed, window)
+			window = newRewardsWindow(epoch+1, level)
+		}
+		s.rewardsWindows[i] = window
+	}
+	return closed
+}
+
+// aggregateRewards aggregates the rewards of an epoch, persisting the windows it closes.
+// A window with missing epochs would understate the rewards, so it is recorded in
+// t_incomplete_rewards_aggregations instead.
+func (s *ChainAnalyzer) aggregateRewards(epoch phase0.Epoch, rewards []spec.ValidatorRewards) {
+	s.rewardsWindowsMu.Lock()
+	defer s.rewardsWindowsMu.Unlock()
+
+	for _, window := range s.nextRewardsWindows(epoch, rewards) {
+		if missing := window.missing(); len(missing) > 0 {
+			log.Warnf("epochs %v of the %s aggregation window %d - %d were not processed, skipping it",
+				missing, window.level.groupBy, window.start, window.end)
+			err := s.dbClient.PersistIncompleteRewardsAggregation(db.IncompleteRewardsAggregation{
+				Group:         window.level.groupBy,
+				StartEpoch:    window.start,
+				EndEpoch:      window.end,
+				MissingEpochs: missing,
+			})
+			if err != nil {
+				log.Errorf("error recording incomplete rewards aggregation: %s", err.Error())
+			}
+			continue
+		}
+		if len(window.aggregations) == 0 {
+			continue
+		}
+		switch window.level.groupBy {
+		case aggregateByValidator:
+			err := s.dbClient.PersistValidatorRewardsAggregation(window.aggregations)
+			if err != nil {
+				log.Fatalf("error persisting validator rewards aggregation: %s", err.Error())
+			}
+		case aggregateByEntity:
+			pools, err := s.dbClient.RetrieveValidatorPools()
+			if err != nil {
+				log.Errorf("error retrieving validator pools for epochs %d - %d: %s", window.start, window.end, err.Error())
+				continue
+			}
+			err = s.dbClient.PersistEntityRewardsAggregation(window.byEntity(pools))
+			if err != nil {
+				log.Fatalf("error persisting entity rewards aggregation: %s", err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewardsAggregationWindow(t *testing.T) {
+	tests := []struct {
+		epoch phase0.Epoch
+		size  int
+		start phase0.Epoch
+		end   phase0.Epoch
+	}{
+		{epoch: 0, size: 225, start: 0, end: 224},
+		{epoch: 224, size: 225, start: 0, end: 224},
+		{epoch: 225, size: 225, start: 225, end: 449},
+		{epoch: 1000, size: 225, start: 900, end: 1124},
+		{epoch: 1000, size: 1, start: 1000, end: 1000},
+		{epoch: 1000, size: 0, start: 1000, end: 1000},
+	}
+
+	for _, test := range tests {
+		start, end := rewardsAggregationWindow(test.epoch, test.size)
+		assert.Equal(t, test.start, start)
+		assert.Equal(t, test.end, end)
+	}
+}
+
+func epochRewards(indexes ...phase0.ValidatorIndex) []spec.ValidatorRewards {
+	rewards := make([]spec.ValidatorRewards, 0, len(indexes))
+	for _, index := range indexes {
+		rewards = append(rewards, spec.ValidatorRewards{ValidatorIndex: index, Reward: 10})
+	}
+	return rewards
+}
+
+func newRewardsAggregator(levels ...rewardsAggregationLevel) *ChainAnalyzer {
+	return &ChainAnalyzer{
+		rewardsLevels:  levels,
+		rewardsWindows: make([]*rewardsWindow, len(levels)),
+	}
+}
+
+func TestNewRewardsAggregationLevels(t *testing.T) {
+	levels, err := newRewardsAggregationLevels(225, "1575:validator, 225:entity,1575:entity")
+	require.NoError(t, err)
+	assert.Equal(t, []rewardsAggregationLevel{
+		{epochs: 225, groupBy: aggregateByValidator},
+		{epochs: 1575, groupBy: aggregateByValidator},
+		{epochs: 225, groupBy: aggregateByEntity},
+		{epochs: 1575, groupBy: aggregateByEntity},
+	}, levels)
+
+	levels, err = newRewardsAggregationLevels(1, "")
+	require.NoError(t, err)
+	assert.Empty(t, levels)
+
+	levels, err = newRewardsAggregationLevels(1, "225")
+	require.NoError(t, err)
+	assert.Equal(t, []rewardsAggregationLevel{{epochs: 225, groupBy: aggregateByValidator}}, levels)
+
+	for _, input := range []string{"1", "abc:validator", "225:pool", "225:entity,225:entity"} {
+		_, err := newRewardsAggregationLevels(1, input)
+		assert.Error(t, err, input)
+	}
+	// already the level of the aggregation epochs
+	_, err = newRewardsAggregationLevels(225, "225:validator")
+	assert.Error(t, err)
+}
+
+func TestNextRewardsWindows(t *testing.T) {
+	s := newRewardsAggregator(rewardsAggregationLevel{epochs: 4, groupBy: aggregateByValidator})
+
+	// window 0 - 3 is complete
+	for epoch := phase0.Epoch(0); epoch < 3; epoch++ {
+		assert.Empty(t, s.nextRewardsWindows(epoch, epochRewards(1, 2)))
+	}
+	closed := s.nextRewardsWindows(3, epochRewards(1, 2))
+	require.Len(t, closed, 1)
+	assert.Equal(t, phase0.Epoch(0), closed[0].start)
+	assert.Equal(t, phase0.Epoch(3), closed[0].end)
+	assert.Empty(t, closed[0].missing())
+	assert.Len(t, closed[0].aggregations, 2)
+
+	// reprocessed epochs of a closed window are ignored
+	assert.Empty(t, s.nextRewardsWindows(2, epochRewards(1, 2)))
+	assert.Empty(t, s.rewardsWindows[0].seen)
+}
+
+func TestNextRewardsWindowsGap(t *testing.T) {
+	s := newRewardsAggregator(rewardsAggregationLevel{epochs: 4, groupBy: aggregateByValidator})
+
+	// epochs 5 and 7 of the window 4 - 7 are never processed
+	assert.Empty(t, s.nextRewardsWindows(4, epochRewards(1)))
+	assert.Empty(t, s.nextRewardsWindows(6, epochRewards(1)))
+
+	closed := s.nextRewardsWindows(8, epochRewards(1))
+	require.Len(t, closed, 1)
+	assert.Equal(t, phase0.Epoch(4), closed[0].start)
+	assert.Equal(t, phase0.Epoch(7), closed[0].end)
+	assert.Equal(t, []phase0.Epoch{5, 7}, closed[0].missing())
+
+	// the epoch closing the window belongs to the next one
+	assert.Equal(t, phase0.Epoch(8), s.rewardsWindows[0].start)
+	assert.True(t, s.rewardsWindows[0].seen[8])
+
+	// a gap spanning a whole window closes the open one only
+	closed = s.nextRewardsWindows(17, epochRewards(1))
+	require.Len(t, closed, 1)
+	assert.Equal(t, []phase0.Epoch{9, 10, 11}, closed[0].missing())
+	assert.Equal(t, phase0.Epoch(16), s.rewardsWindows[0].start)
+	assert.Equal(t, []phase0.Epoch{16, 18, 19}, s.rewardsWindows[0].missing())
+}
+
+func TestNextRewardsWindowsLevels(t *testing.T) {
+	validators := rewardsAggregationLevel{epochs: 2, groupBy: aggregateByValidator}
+	entities := rewardsAggregationLevel{epochs: 4, groupBy: aggregateByEntity}
+	s := newRewardsAggregator(validators, entities)
+
+	assert.Empty(t, s.nextRewardsWindows(0, epochRewards(1, 2)))
+	closed := s.nextRewardsWindows(1, epochRewards(1, 2))
+	require.Len(t, closed, 1)
+	assert.Equal(t, validators, closed[0].level)
+
+	assert.Empty(t, s.nextRewardsWindows(2, epochRewards(1, 2)))
+	closed = s.nextRewardsWindows(3, epochRewards(1, 2))
+	require.Len(t, closed, 2)
+	assert.Equal(t, validators, closed[0].level)
+	assert.Equal(t, phase0.Epoch(2), closed[0].start)
+	assert.Equal(t, entities, closed[1].level)
+	assert.Equal(t, phase0.Epoch(0), closed[1].start)
+	assert.Empty(t, closed[1].missing())
+
+	// validator 2 has no entity, validator 3 shares the entity of validator 1
+	closed[1].aggregations[3] = spec.NewValidatorRewardsAggregation(3, 0, 3)
+	closed[1].aggregations[3].Aggregate(spec.ValidatorRewards{ValidatorIndex: 3, Reward: 5, AttestationIncluded: true})
+	byEntity := closed[1].byEntity(map[phase0.ValidatorIndex]string{1: "pool", 3: "pool"})
+	require.Len(t, byEntity, 1)
+	assert.Equal(t, "pool", byEntity["pool"].Entity)
+	assert.Equal(t, uint64(2), byEntity["pool"].Validators)
+	assert.Equal(t, int64(45), byEntity["pool"].Reward)
+	assert.Equal(t, uint64(1), byEntity["pool"].AttestationsIncluded)
+	assert.Equal(t, phase0.Epoch(3), byEntity["pool"].EndEpoch)
+}
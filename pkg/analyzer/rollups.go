@@ -0,0 +1,62 @@
+package analyzer
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/rollups"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// aggregateRollupBlobs summarizes the blob transactions of the blocks of an epoch per rollup.
+// The fill ratio only accounts for the blobs whose sidecars were downloaded.
+func aggregateRollupBlobs(epoch phase0.Epoch, blocks []*spec.AgnosticBlock, registry *rollups.Registry) []db.RollupBlobUsage {
+	usages := make(map[string]*db.RollupBlobUsage)
+	for _, block := range blocks {
+		if !block.Proposed {
+			continue
+		}
+		txRollups := make(map[common.Hash]*db.RollupBlobUsage)
+		for _, tx := range block.ExecutionPayload.AgnosticTransactions {
+			if len(tx.BlobHashes) == 0 {
+				continue
+			}
+			name := registry.Identify(tx.From, tx.To)
+			usage, ok := usages[name]
+			if !ok {
+				usage = &db.RollupBlobUsage{Epoch: epoch, Rollup: name, BlobFees: new(big.Int)}
+				usages[name] = usage
+			}
+			usage.Transactions++
+			usage.Blobs += uint64(len(tx.BlobHashes))
+			usage.BlobGasUsed += tx.BlobGasUsed
+			if tx.Fees.BlobBurntFee != nil {
+				usage.BlobFees.Add(usage.BlobFees, tx.Fees.BlobBurntFee)
+			}
+			txRollups[common.Hash(tx.Hash)] = usage
+		}
+		for _, fill := range block.BlobsFill {
+			usage, ok := txRollups[fill.TxHash]
+			if !ok {
+				continue // not matched with a transaction
+			}
+			usage.SidecarBlobs++
+			usage.UsedBytes += fill.UsedBytes
+		}
+	}
+
+	result := make([]db.RollupBlobUsage, 0, len(usages))
+	for _, usage := range usages {
+		if usage.SidecarBlobs > 0 {
+			usage.FillRatio = float64(usage.UsedBytes) / float64(usage.SidecarBlobs*uint64(spec.BytesPerBlob))
+		}
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Rollup < result[j].Rollup
+	})
+	return result
+}
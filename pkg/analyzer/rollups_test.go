@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/migalabs/goteth/pkg/rollups"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateRollupBlobs(t *testing.T) {
+	batcher := common.HexToAddress("0x5050F69a9786F081509234F1a7F4684b5E5b76C9")
+	registry, err := rollups.NewRegistry([]rollups.Rollup{{Name: "base", From: []string{batcher.String()}}})
+	require.NoError(t, err)
+
+	blobTx := func(hash byte, from common.Address, blobs int, fee int64) spec.AgnosticTransaction {
+		return spec.AgnosticTransaction{
+			Hash:        phase0.Hash32{hash},
+			From:        from,
+			BlobHashes:  make([]common.Hash, blobs),
+			BlobGasUsed: uint64(blobs) * spec.GasPerBlob,
+			Fees:        spec.TransactionFees{BlobBurntFee: big.NewInt(fee)},
+		}
+	}
+	first := &spec.AgnosticBlock{Proposed: true}
+	first.ExecutionPayload.AgnosticTransactions = []spec.AgnosticTransaction{
+		blobTx(1, batcher, 2, 100),
+		{Hash: phase0.Hash32{2}, From: batcher}, // no blobs
+		blobTx(3, common.HexToAddress("0x01"), 1, 7),
+	}
+	first.BlobsFill = []spec.BlobFill{
+		{TxHash: common.Hash{1}, UsedBytes: uint64(spec.BytesPerBlob)},
+		{TxHash: common.Hash{1}, UsedBytes: uint64(spec.BytesPerBlob) / 2},
+		{TxHash: common.Hash{9}, UsedBytes: 10}, // not matched with a transaction
+	}
+	// blobs without downloaded sidecars do not count for the fill ratio
+	second := &spec.AgnosticBlock{Proposed: true}
+	second.ExecutionPayload.AgnosticTransactions = []spec.AgnosticTransaction{blobTx(4, batcher, 3, 50)}
+	missed := &spec.AgnosticBlock{}
+
+	usages := aggregateRollupBlobs(10, []*spec.AgnosticBlock{first, second, missed}, registry)
+	require.Len(t, usages, 2)
+
+	base := usages[0]
+	assert.Equal(t, phase0.Epoch(10), base.Epoch)
+	assert.Equal(t, "base", base.Rollup)
+	assert.Equal(t, uint64(2), base.Transactions)
+	assert.Equal(t, uint64(5), base.Blobs)
+	assert.Equal(t, uint64(5*spec.GasPerBlob), base.BlobGasUsed)
+	assert.Equal(t, "150", base.BlobFees.String())
+	assert.Equal(t, uint64(2), base.SidecarBlobs)
+	assert.Equal(t, 0.75, base.FillRatio)
+
+	unknown := usages[1]
+	assert.Equal(t, rollups.Unknown, unknown.Rollup)
+	assert.Equal(t, uint64(1), unknown.Transactions)
+	assert.Equal(t, "7", unknown.BlobFees.String())
+	assert.Equal(t, uint64(0), unknown.SidecarBlobs)
+	assert.Equal(t, 0.0, unknown.FillRatio)
+}
@@ -29,6 +29,7 @@ downloadRoutine:
 
 			go s.DownloadBlockCotrolled(phase0.Slot(downloadSlot))
 			go s.ProcessBlock(downloadSlot)
+			go s.AnalyzeParentRoot(downloadSlot)
 
 			// if epoch boundary, download state
 			if (downloadSlot % spec.SlotsPerEpoch) == (spec.SlotsPerEpoch - 1) { // last slot of epoch
@@ -49,6 +50,10 @@ func (s *ChainAnalyzer) runHead() {
 	defer s.wgMainRoutine.Done()
 	log.Info("launching head routine")
 	nextSlotDownload := s.fillToHead()
+	if s.stop || s.ctx.Err() != nil {
+		log.Info("shutdown detected while filling to head")
+		return
+	}
 
 	// Wait for blocks that may still be in-flight. During historical
 	// processing, CleanUpTo evicts blocks older than 5 epochs, so only
@@ -79,7 +84,12 @@ func (s *ChainAnalyzer) runHead() {
 	s.eventsObj.SubscribeToFinalizedCheckpointEvents()
 	s.eventsObj.SubscribeToReorgsEvents()
 	s.eventsObj.SubscribeToBlobSidecarsEvents()
+	if s.metrics.DataColumns {
+		s.eventsObj.SubscribeToDataColumnSidecarsEvents()
+	}
 	ticker := time.NewTicker(utils.RoutineFlushTimeout)
+	// a block gossips up to one event per column, they are persisted together
+	dataColumnEvents := make([]spec.DataColumnSidecarEventWrapper, 0, spec.NumberOfColumns)
 	// loop over the list of slots that we need to analyze
 
 	for {
@@ -120,11 +130,22 @@ func (s *ChainAnalyzer) runHead() {
 		case newBlobSidecarEvent := <-s.eventsObj.BlobSidecarChan:
 			s.dbClient.PersistBlobSidecarsEvents([]spec.BlobSideCarEventWraper{newBlobSidecarEvent})
 
+		case newDataColumnEvent := <-s.eventsObj.DataColumnSidecarChan:
+			dataColumnEvents = append(dataColumnEvents, newDataColumnEvent)
+			if len(dataColumnEvents) >= spec.NumberOfColumns {
+				s.dbClient.PersistDataColumnSidecarEvents(dataColumnEvents)
+				dataColumnEvents = dataColumnEvents[:0]
+			}
+
 		case <-s.ctx.Done():
 			log.Info("context has died, closing block requester routine")
 			return
 
 		case <-ticker.C:
+			if len(dataColumnEvents) > 0 {
+				s.dbClient.PersistDataColumnSidecarEvents(dataColumnEvents)
+				dataColumnEvents = dataColumnEvents[:0]
+			}
 			if s.stop {
 				log.Info("sudden shutdown detected, block downloader routine")
 				return
@@ -138,9 +159,9 @@ func (s *ChainAnalyzer) fillToHead() phase0.Slot {
 	// ------ fill from last epoch in database to current head -------
 
 	// obtain current finalized
-	finalizedBlock, err := s.cli.RequestFinalizedBeaconBlock()
-	if err != nil {
-		log.Panicf("could not request the finalized block: %s", err)
+	finalizedBlock, ok := s.requestFinalizedBlock()
+	if !ok {
+		return 0
 	}
 
 	// obtain current head
@@ -170,8 +191,10 @@ func (s *ChainAnalyzer) fillToHead() phase0.Slot {
 	}
 	nextSlotDownload = nextSlotDownload / spec.SlotsPerEpoch * spec.SlotsPerEpoch
 	s.initSlot = nextSlotDownload / spec.SlotsPerEpoch * spec.SlotsPerEpoch
-	s.startEpochAggregation = phase0.Epoch(spec.EpochAtSlot(s.initSlot) + 2)
-	s.endEpochAggregation = s.startEpochAggregation + phase0.Epoch(s.rewardsAggregationEpochs-1)
+	err := s.initRewardsAggregation(spec.EpochAtSlot(s.initSlot) + 2)
+	if err != nil {
+		log.Errorf("could not restore the rewards aggregation window: %s", err)
+	}
 
 	log.Infof("filling to head...")
 	s.wgMainRoutine.Add(1) // add because historical will defer it
@@ -197,11 +220,12 @@ func (s *ChainAnalyzer) runHistorical(init phase0.Slot, end phase0.Slot) {
 			continue
 		}
 		if i%spec.SlotsPerEpoch == 0 { // every time a new epoch is crossed
-			finalizedSlot, err := s.cli.RequestFinalizedBeaconBlock()
-
-			if err != nil {
-				log.Fatalf("could not request finalized slot: %s", err)
+			finalizedSlot, ok := s.requestFinalizedBlock()
+			if !ok {
+				log.Info("shutdown detected while requesting the finalized block")
+				return
 			}
+			s.dbClient.SetFinalizedEpoch(spec.EpochAtSlot(finalizedSlot.Slot))
 
 			if i >= finalizedSlot.Slot {
 				// keep 2 epochs before finalized, needed to calculate epoch metrics
@@ -226,3 +250,26 @@ func (s *ChainAnalyzer) runHistorical(init phase0.Slot, end phase0.Slot) {
 	log.Infof("historical mode: all download tasks sent")
 
 }
+
+// requestFinalizedBlock keeps asking for the finalized block until one of the
+// beacon nodes answers. Returns false only if the analyzer is shutting down.
+func (s *ChainAnalyzer) requestFinalizedBlock() (*spec.AgnosticBlock, bool) {
+	attempts := 0
+	for {
+		finalizedBlock, err := s.cli.RequestFinalizedBeaconBlock()
+		if err == nil {
+			return finalizedBlock, true
+		}
+		attempts += 1
+		log.Errorf("could not request the finalized block (attempt %d): %s", attempts, err)
+
+		select {
+		case <-time.After(utils.RoutineFlushTimeout * time.Duration(min(attempts, 10))):
+		case <-s.ctx.Done():
+			return nil, false
+		}
+		if s.stop {
+			return nil, false
+		}
+	}
+}
@@ -154,6 +154,15 @@ func (m *AgnosticMap[T]) Available(key uint64) bool {
 	return ok
 }
 
+// Get returns the value of the key without waiting for it.
+func (m *AgnosticMap[T]) Get(key uint64) (*T, bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	value, ok := m.m[key]
+	return value, ok
+}
+
 func (m *AgnosticMap[T]) GetKeyList() []uint64 {
 	m.Lock()
 	// Unlock cannot be deferred so we can unblock Set() while waiting
@@ -0,0 +1,86 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	moduleName = "blobstore"
+	log        = logrus.WithField(
+		"module", moduleName)
+
+	// ErrNotFound is returned when no blob is stored under the key
+	ErrNotFound = errors.New("blob not found")
+
+	versionedHashRegexp = regexp.MustCompile(`^0x[0-9a-f]{64}$`)
+)
+
+// Store keeps the blob contents out of the database, addressed by their versioned hash.
+type Store interface {
+	// Put writes the data under the key, a no-op when the key is already stored
+	Put(ctx context.Context, key string, data []byte) error
+	// Get reads the data stored under the key, ErrNotFound when missing
+	Get(ctx context.Context, key string) ([]byte, error)
+	// String describes where the blobs are stored
+	String() string
+}
+
+// New opens the store of the given location: a local directory (a path or file:///path),
+// or an S3 compatible bucket as s3://bucket/prefix?region=eu-west-1&endpoint=http://localhost:9000.
+// An empty location disables the storage.
+func New(location string) (Store, error) {
+	if location == "" {
+		return nil, nil
+	}
+	if !strings.Contains(location, "://") {
+		return NewLocalStore(location)
+	}
+	parsed, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blob store %s: %w", location, err)
+	}
+	switch parsed.Scheme {
+	case "file":
+		return NewLocalStore(parsed.Path)
+	case "s3":
+		return newS3StoreFromURL(parsed)
+	default:
+		return nil, fmt.Errorf("unsupported blob store scheme: %s", parsed.Scheme)
+	}
+}
+
+// Key returns the key a blob is stored under: its versioned hash, behind two levels of
+// directories taken from the hash so that no directory grows too large.
+func Key(versionedHash string) (string, error) {
+	hash := strings.ToLower(versionedHash)
+	if !versionedHashRegexp.MatchString(hash) {
+		return "", fmt.Errorf("invalid versioned hash: %s", versionedHash)
+	}
+	return fmt.Sprintf("%s/%s/%s", hash[4:6], hash[6:8], hash), nil
+}
+
+// GetBlob reads back the blob of the given versioned hash.
+func GetBlob(ctx context.Context, store Store, versionedHash string) (*deneb.Blob, error) {
+	key, err := Key(versionedHash)
+	if err != nil {
+		return nil, err
+	}
+	data, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	blob := &deneb.Blob{}
+	if len(data) != len(blob) {
+		return nil, fmt.Errorf("stored blob %s has %d bytes instead of %d", versionedHash, len(data), len(blob))
+	}
+	copy(blob[:], data)
+	return blob, nil
+}
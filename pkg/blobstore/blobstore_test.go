@@ -0,0 +1,85 @@
+package blobstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testHash = "0x01" + strings.Repeat("ab", 31)
+
+func TestKey(t *testing.T) {
+	key, err := Key(testHash)
+	require.NoError(t, err)
+	assert.Equal(t, "ab/ab/"+testHash, key)
+
+	// hashes are lowercased
+	key, err = Key("0x01" + strings.Repeat("AB", 31))
+	require.NoError(t, err)
+	assert.Equal(t, "ab/ab/"+testHash, key)
+
+	_, err = Key("0x01ab")
+	assert.Error(t, err)
+	_, err = Key(testHash[2:] + "00")
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	store, err := New("")
+	require.NoError(t, err)
+	assert.Nil(t, store)
+
+	dir := t.TempDir()
+	store, err = New("file://" + dir)
+	require.NoError(t, err)
+	assert.Equal(t, "file://"+dir, store.String())
+
+	_, err = New("ftp://host/dir")
+	assert.Error(t, err)
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	_, err = New("s3://bucket/prefix")
+	assert.Error(t, err) // no credentials
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	store, err = New("s3://bucket/goteth/blobs?endpoint=http://localhost:9000&region=eu-west-1")
+	require.NoError(t, err)
+	s3Store := store.(*S3Store)
+	assert.Equal(t, "eu-west-1", s3Store.region)
+	assert.Equal(t, "http://localhost:9000/bucket/goteth/blobs/ab/cd/x", s3Store.objectURL("ab/cd/x").String())
+}
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = GetBlob(ctx, store, testHash)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	blob := deneb.Blob{}
+	blob[0] = 1
+	blob[len(blob)-1] = 2
+	key, err := Key(testHash)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, key, blob[:]))
+	// already stored
+	require.NoError(t, store.Put(ctx, key, []byte{1}))
+
+	stored, err := GetBlob(ctx, store, testHash)
+	require.NoError(t, err)
+	assert.Equal(t, blob, *stored)
+
+	// not a blob
+	otherHash := "0x01" + strings.Repeat("cd", 31)
+	otherKey, err := Key(otherHash)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, otherKey, []byte{1}))
+	_, err = GetBlob(ctx, store, otherHash)
+	assert.Error(t, err)
+}
@@ -0,0 +1,252 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+	s3Timeout       = 1 * time.Minute
+
+	amzDateFormat = "20060102T150405Z"
+)
+
+// S3Store keeps the blobs in an S3 compatible bucket, such as AWS S3 or MinIO. Requests are
+// signed with AWS Signature Version 4.
+type S3Store struct {
+	endpoint  *url.URL // path style endpoint, nil for the virtual hosted AWS one
+	bucket    string
+	prefix    string
+	region    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+// newS3StoreFromURL reads s3://bucket/prefix?region=...&endpoint=..., the credentials being
+// taken from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+func newS3StoreFromURL(location *url.URL) (*S3Store, error) {
+	query := location.Query()
+	region := query.Get("region")
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = defaultS3Region
+	}
+	return NewS3Store(
+		query.Get("endpoint"),
+		location.Host,
+		location.Path,
+		region,
+		os.Getenv("AWS_ACCESS_KEY_ID"),
+		os.Getenv("AWS_SECRET_ACCESS_KEY"))
+}
+
+// NewS3Store opens a bucket. With an endpoint (e.g. http://localhost:9000 for MinIO) objects
+// are addressed path style, otherwise through the virtual hosted AWS endpoint of the region.
+func NewS3Store(endpoint string, bucket string, prefix string, region string, accessKey string, secretKey string) (*S3Store, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("no blob store bucket given")
+	}
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("no credentials given for the blob store bucket %s", bucket)
+	}
+	s := &S3Store{
+		bucket:    bucket,
+		prefix:    strings.Trim(prefix, "/"),
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: s3Timeout},
+	}
+	if endpoint != "" {
+		parsed, err := url.Parse(endpoint)
+		if err != nil || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid blob store endpoint: %s", endpoint)
+		}
+		s.endpoint = parsed
+	}
+	log.Infof("storing blobs in %s", s)
+	return s, nil
+}
+
+func (s *S3Store) objectURL(key string) *url.URL {
+	if s.prefix != "" {
+		key = s.prefix + "/" + key
+	}
+	if s.endpoint != nil {
+		return &url.URL{
+			Scheme: s.endpoint.Scheme,
+			Host:   s.endpoint.Host,
+			Path:   strings.TrimSuffix(s.endpoint.Path, "/") + "/" + s.bucket + "/" + key,
+		}
+	}
+	return &url.URL{
+		Scheme: "https",
+		Host:   fmt.Sprintf("%s.s3.%s.amazonaws.com", s.bucket, s.region),
+		Path:   "/" + key,
+	}
+}
+
+// Put uploads the blob. Blobs are content addressed, so an existing object is simply
+// overwritten with the same data.
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp, key)
+	}
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, s3Error(resp, key)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (s *S3Store) String() string {
+	location := "s3://" + s.bucket + "/" + s.prefix
+	if s.endpoint != nil {
+		location += " at " + s.endpoint.String()
+	}
+	return location
+}
+
+func (s *S3Store) do(ctx context.Context, method string, key string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, body, time.Now())
+	return s.client.Do(req)
+}
+
+func s3Error(resp *http.Response, key string) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("blob store request for %s failed with status %d: %s", key, resp.StatusCode, string(data))
+}
+
+// sign adds the AWS Signature Version 4 of the request, covering the host and every
+// header already set.
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (s *S3Store) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.UTC().Format(amzDateFormat)
+	date := amzDate[:8]
+	payloadHash := sha256Hex(body)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncodePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func uriEncodePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return uriEncode(path, false)
+}
+
+// uriEncode escapes everything but the unreserved characters, and the slashes of paths
+func uriEncode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for _, c := range []byte(value) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			encoded.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			encoded.WriteByte(c)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+	return encoded.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,135 @@
+package builders
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	moduleName = "builders"
+	log        = logrus.WithField(
+		"module", moduleName)
+
+	// extra data set by default by the execution clients when building a block themselves
+	DefaultLocalExtraData = []string{"geth", "nethermind", "besu", "erigon", "reth", "ethereumjs"}
+)
+
+type BuildType string
+
+const (
+	LocalBuild   BuildType = "local"   // built by the proposer's own execution client
+	RelayBuild   BuildType = "relay"   // built by a builder and delivered through a relay
+	UnknownBuild BuildType = "unknown" // nothing tells who built it
+)
+
+// Builder gathers what identifies the blocks of a builder.
+type Builder struct {
+	Name          string   `json:"name"`
+	ExtraData     []string `json:"extra-data"`     // case insensitive substrings of the extra data text
+	FeeRecipients []string `json:"fee-recipients"` // execution addresses the builder sets as fee recipient
+	Pubkeys       []string `json:"pubkeys"`        // BLS keys the builder submits bids with
+}
+
+// Registry identifies the builder of a block, as read from a builders file:
+//
+//	{
+//	  "builders": [{"name": "beaverbuild", "extra-data": ["beaverbuild"], "fee-recipients": ["0x..."], "pubkeys": ["0x..."]}],
+//	  "local-extra-data": ["geth", "nethermind"]
+//	}
+type Registry struct {
+	Builders       []Builder `json:"builders"`
+	LocalExtraData []string  `json:"local-extra-data"`
+
+	byPubkey       map[string]string
+	byFeeRecipient map[string]string
+}
+
+// Identification is the builder of a block and how it was built.
+type Identification struct {
+	Builder string
+	Type    BuildType
+}
+
+// NewRegistry indexes the given builders. Without local extra data patterns the
+// execution clients defaults are used.
+func NewRegistry(builders []Builder, localExtraData []string) *Registry {
+	if len(localExtraData) == 0 {
+		localExtraData = DefaultLocalExtraData
+	}
+	r := &Registry{
+		Builders:       builders,
+		LocalExtraData: localExtraData,
+		byPubkey:       make(map[string]string),
+		byFeeRecipient: make(map[string]string),
+	}
+	for _, builder := range builders {
+		for _, pubkey := range builder.Pubkeys {
+			r.byPubkey[strings.ToLower(pubkey)] = builder.Name
+		}
+		for _, address := range builder.FeeRecipients {
+			r.byFeeRecipient[strings.ToLower(address)] = builder.Name
+		}
+	}
+	return r
+}
+
+// ReadBuildersFile reads a registry from a JSON file, an empty path returns a registry
+// that only tells local builds from the execution clients extra data.
+func ReadBuildersFile(path string) (*Registry, error) {
+	if path == "" {
+		return NewRegistry(nil, nil), nil
+	}
+	log.Infof("reading builders from: %s", path)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file Registry
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("could not parse builders file %s: %w", path, err)
+	}
+	for i, builder := range file.Builders {
+		if builder.Name == "" {
+			return nil, fmt.Errorf("builder %d of %s has no name", i, path)
+		}
+	}
+	log.Infof("read %d builders", len(file.Builders))
+	return NewRegistry(file.Builders, file.LocalExtraData), nil
+}
+
+// Identify tells who built a block, from the builder pubkeys and relays of the bids
+// delivered for it, its fee recipient and its extra data, in that order of confidence.
+// A block matching a known builder was delivered by a relay even if no monitored relay
+// reported it.
+func (r *Registry) Identify(extraData string, feeRecipient string, builderPubkeys []string, relays []string) Identification {
+	extraData = strings.ToLower(extraData)
+
+	for _, pubkey := range builderPubkeys {
+		if name, ok := r.byPubkey[strings.ToLower(pubkey)]; ok {
+			return Identification{Builder: name, Type: RelayBuild}
+		}
+	}
+	if name, ok := r.byFeeRecipient[strings.ToLower(feeRecipient)]; ok {
+		return Identification{Builder: name, Type: RelayBuild}
+	}
+	for _, builder := range r.Builders {
+		for _, pattern := range builder.ExtraData {
+			if pattern != "" && strings.Contains(extraData, strings.ToLower(pattern)) {
+				return Identification{Builder: builder.Name, Type: RelayBuild}
+			}
+		}
+	}
+	if len(relays) > 0 {
+		return Identification{Type: RelayBuild}
+	}
+	for _, pattern := range r.LocalExtraData {
+		if pattern != "" && strings.Contains(extraData, strings.ToLower(pattern)) {
+			return Identification{Type: LocalBuild}
+		}
+	}
+	return Identification{Type: UnknownBuild}
+}
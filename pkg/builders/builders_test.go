@@ -0,0 +1,67 @@
+package builders
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentify(t *testing.T) {
+	registry := NewRegistry([]Builder{
+		{
+			Name:          "beaverbuild",
+			ExtraData:     []string{"beaverbuild.org"},
+			FeeRecipients: []string{"0x95222290DD7278Aa3Ddd389Cc1E1d165CC4BAfe5"},
+		},
+		{
+			Name:      "titan",
+			ExtraData: []string{"Titan"},
+			Pubkeys:   []string{"0xabcd"},
+		},
+	}, nil)
+
+	tests := []struct {
+		name         string
+		extraData    string
+		feeRecipient string
+		pubkeys      []string
+		relays       []string
+		expected     Identification
+	}{
+		{"pubkey", "", "0x00", []string{"0xABCD"}, []string{"relay"}, Identification{"titan", RelayBuild}},
+		{"fee recipient", "", "0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5", nil, nil, Identification{"beaverbuild", RelayBuild}},
+		{"extra data", "Titan (titanbuilder.xyz)", "0x00", nil, nil, Identification{"titan", RelayBuild}},
+		{"unknown builder through a relay", "some builder", "0x00", []string{"0x1234"}, []string{"relay"}, Identification{"", RelayBuild}},
+		{"local", "\x8a\x83\x01\x0e\x0b\x84geth\x88go1.22.5\x85linux", "0x00", nil, nil, Identification{"", LocalBuild}},
+		{"unknown", "", "0x00", nil, nil, Identification{"", UnknownBuild}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, registry.Identify(test.extraData, test.feeRecipient, test.pubkeys, test.relays))
+		})
+	}
+}
+
+func TestReadBuildersFile(t *testing.T) {
+	registry, err := ReadBuildersFile("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLocalExtraData, registry.LocalExtraData)
+	assert.Equal(t, Identification{"", LocalBuild}, registry.Identify("Nethermind v1.29", "", nil, nil))
+
+	path := filepath.Join(t.TempDir(), "builders.json")
+	content := `{"builders": [{"name": "rsync", "extra-data": ["rsync-builder"]}], "local-extra-data": ["mybuilder"]}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	registry, err = ReadBuildersFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, Identification{"rsync", RelayBuild}, registry.Identify("rsync-builder.xyz", "", nil, nil))
+	assert.Equal(t, Identification{"", LocalBuild}, registry.Identify("mybuilder", "", nil, nil))
+	assert.Equal(t, Identification{"", UnknownBuild}, registry.Identify("geth", "", nil, nil))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"builders": [{"extra-data": ["x"]}]}`), 0o644))
+	_, err = ReadBuildersFile(path)
+	assert.Error(t, err)
+}
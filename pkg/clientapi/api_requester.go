@@ -3,6 +3,7 @@ package clientapi
 import (
 	"context"
 	"fmt"
+	nethttp "net/http"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/http"
@@ -25,16 +26,24 @@ var (
 type APIClientOption func(*APIClient) error
 
 type APIClient struct {
-	ctx            context.Context
-	Api            *http.Service     // Beacon Node
-	ELApi          *ethclient.Client // Execution Node
-	bnEndpoint     string
-	Metrics        db.DBMetrics
-	maxRetries     int
-	statesBook     *utils.RoutineBook // Book to track what is being downloaded through the CL API: states
-	blocksBook     *utils.RoutineBook // Book to track what is being downloaded through the CL API: blocks
-	txBook         *utils.RoutineBook // Book to track what is being downloaded through the EL API: transactions
-	receiptMetrics *receiptMetrics
+	ctx               context.Context
+	Api               *http.Service     // Beacon Node (primary, first endpoint)
+	ELApi             *ethclient.Client // Execution Node
+	bnEndpoint        string
+	nodes             []*beaconNode // every configured beacon node, used for failover
+	consistencyChecks bool          // whether to compare block/state roots across beacon nodes
+	nonArchivalStates bool          // whether to build states from per-resource endpoints instead of downloading them
+	ssz               bool          // whether to download states and blocks as SSZ
+	extraHeaders      map[string]string
+	httpClient        *nethttp.Client // used for the SSZ requests
+	Metrics           db.DBMetrics
+	maxRetries        int
+	statesBook        *utils.RoutineBook // Book to track what is being downloaded through the CL API: states
+	blocksBook        *utils.RoutineBook // Book to track what is being downloaded through the CL API: blocks
+	txBook            *utils.RoutineBook // Book to track what is being downloaded through the EL API: transactions
+	traceBook         *utils.RoutineBook // Book to track the blocks being traced through the EL API
+	receiptMetrics    *receiptMetrics
+	blobSchedule      *blobScheduleCache
 }
 
 func NewAPIClient(ctx context.Context, bnEndpoint string, bnApiKey string, cfAccessClientID string, cfAccessClientSecret string, maxRequestRetries int, options ...APIClientOption) (*APIClient, error) {
@@ -45,7 +54,10 @@ func NewAPIClient(ctx context.Context, bnEndpoint string, bnApiKey string, cfAcc
 		statesBook:     utils.NewRoutineBook(1, "api-cli-states"),
 		blocksBook:     utils.NewRoutineBook(1, "api-cli-blocks"),
 		txBook:         utils.NewRoutineBook(maxParallelConns, "api-cli-tx"),
+		traceBook:      utils.NewRoutineBook(maxParallelTraces, "api-cli-traces"),
 		receiptMetrics: newReceiptMetrics(),
+		httpClient:     &nethttp.Client{},
+		blobSchedule:   &blobScheduleCache{},
 	}
 
 	clientBuildingOpts := []http.Parameter{
@@ -68,28 +80,50 @@ func NewAPIClient(ctx context.Context, bnEndpoint string, bnApiKey string, cfAcc
 	}
 
 	log.Infof("extra headers: %v", extraHeadersMap)
+	apiService.extraHeaders = extraHeadersMap
 
 	if len(extraHeadersMap) > 0 {
 		clientBuildingOpts = append(clientBuildingOpts, http.WithExtraHeaders(extraHeadersMap))
 	}
 
-	bnCli, err := http.New(
-		ctx,
-		http.WithAddress(bnEndpoint),
-		http.WithLogLevel(zerolog.WarnLevel),
-		http.WithTimeout(QueryTimeout),
-		http.WithExtraHeaders(extraHeadersMap),
-	)
-	if err != nil {
-		return &APIClient{}, err
+	bnEndpoints := parseBeaconEndpoints(bnEndpoint)
+	if len(bnEndpoints) == 0 {
+		return &APIClient{}, fmt.Errorf("no beacon node endpoint provided")
 	}
 
-	hc, ok := bnCli.(*http.Service)
-	if !ok {
-		log.Error("generating the http api client")
+	for _, endpoint := range bnEndpoints {
+		bnCli, err := http.New(
+			ctx,
+			http.WithAddress(endpoint),
+			http.WithLogLevel(zerolog.WarnLevel),
+			http.WithTimeout(QueryTimeout),
+			http.WithExtraHeaders(extraHeadersMap),
+		)
+		if err != nil {
+			if len(bnEndpoints) > 1 {
+				// other nodes may still be reachable
+				log.Warnf("could not connect to beacon node %s: %s", endpoint, err)
+				continue
+			}
+			return &APIClient{}, err
+		}
+
+		hc, ok := bnCli.(*http.Service)
+		if !ok {
+			log.Error("generating the http api client")
+			continue
+		}
+		apiService.nodes = append(apiService.nodes, &beaconNode{
+			address: endpoint,
+			api:     hc,
+		})
+	}
+	if len(apiService.nodes) == 0 {
+		return &APIClient{}, fmt.Errorf("could not connect to any beacon node in %s", bnEndpoint)
 	}
+	log.Infof("using %d beacon node(s)", len(apiService.nodes))
 
-	apiService.Api = hc
+	apiService.Api = apiService.nodes[0].api
 	apiService.bnEndpoint = bnEndpoint
 	apiService.maxRetries = maxRequestRetries
 	for _, o := range options {
@@ -129,15 +163,31 @@ func WithPromMetrics(metrics *prom_metrics.PrometheusMetrics) APIClientOption {
 		metrics.AddMeticsModule(s.statesBook.GetPrometheusMetrics())
 		metrics.AddMeticsModule(s.blocksBook.GetPrometheusMetrics())
 		metrics.AddMeticsModule(s.txBook.GetPrometheusMetrics())
+		metrics.AddMeticsModule(s.traceBook.GetPrometheusMetrics())
 		if receiptModule := s.receiptMetrics.getPrometheusMetrics(); receiptModule != nil {
 			metrics.AddMeticsModule(receiptModule)
 		}
+		if nodesModule := s.getBeaconNodePrometheusMetrics(); nodesModule != nil {
+			metrics.AddMeticsModule(nodesModule)
+		}
+		if downloadsModule := getDownloadPrometheusMetrics(); downloadsModule != nil {
+			metrics.AddMeticsModule(downloadsModule)
+		}
 
 		return nil
 	}
 }
 
+// WithConsistencyChecks compares downloaded block and state roots against
+// the rest of the beacon nodes and reports any disagreement.
+func WithConsistencyChecks(enabled bool) APIClientOption {
+	return func(s *APIClient) error {
+		s.consistencyChecks = enabled
+		return nil
+	}
+}
+
 func (s APIClient) ActiveReqNum() int {
 
-	return s.blocksBook.ActivePages() + s.statesBook.ActivePages() + s.txBook.ActivePages()
+	return s.blocksBook.ActivePages() + s.statesBook.ActivePages() + s.txBook.ActivePages() + s.traceBook.ActivePages()
 }
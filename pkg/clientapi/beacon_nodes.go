@@ -0,0 +1,264 @@
+package clientapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+const (
+	// after this many consecutive failures a beacon node is skipped
+	// for a cooldown period, unless every other node is also failing
+	nodeFailureThreshold = 3
+	nodeCooldown         = 1 * time.Minute
+
+	// weight of the latest sample in the moving latency average
+	latencySmoothing = 0.2
+)
+
+// beaconNode wraps one beacon node endpoint together with its health record.
+type beaconNode struct {
+	address string
+	api     *http.Service
+
+	mu              sync.Mutex
+	successes       uint64
+	failures        uint64
+	consecutiveFail int
+	openUntil       time.Time
+	avgLatency      time.Duration
+}
+
+// parseBeaconEndpoints splits a comma separated list of beacon node endpoints.
+func parseBeaconEndpoints(endpoints string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(endpoints, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// available returns false while the node is in its failure cooldown.
+func (n *beaconNode) available() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.consecutiveFail < nodeFailureThreshold {
+		return true
+	}
+	if time.Now().After(n.openUntil) {
+		// cooldown expired, allow one probe request
+		n.consecutiveFail = nodeFailureThreshold - 1
+		return true
+	}
+	return false
+}
+
+func (n *beaconNode) recordResult(failed bool, latency time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if failed {
+		n.failures++
+		n.consecutiveFail++
+		if n.consecutiveFail >= nodeFailureThreshold {
+			n.openUntil = time.Now().Add(nodeCooldown)
+			log.Warnf("beacon node %s failed %d times in a row, skipping it for %s", n.address, n.consecutiveFail, nodeCooldown)
+		}
+	} else {
+		n.successes++
+		n.consecutiveFail = 0
+		if n.avgLatency == 0 {
+			n.avgLatency = latency
+		} else {
+			n.avgLatency = time.Duration((1-latencySmoothing)*float64(n.avgLatency) + latencySmoothing*float64(latency))
+		}
+	}
+	beaconNodeScore.WithLabelValues(n.address).Set(n.scoreLocked())
+}
+
+// score ranks nodes: success ratio first, recent failures and latency as penalties.
+func (n *beaconNode) score() float64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.scoreLocked()
+}
+
+func (n *beaconNode) scoreLocked() float64 {
+	total := n.successes + n.failures
+	ratio := 1.0
+	if total > 0 {
+		ratio = float64(n.successes) / float64(total)
+	}
+	return ratio*100 - float64(n.consecutiveFail)*10 - n.avgLatency.Seconds()
+}
+
+// rankedNodes returns the beacon nodes ordered by health score.
+// Nodes in cooldown are moved to the end so they are only used as a last resort.
+func (s *APIClient) rankedNodes() []*beaconNode {
+	healthy := make([]*beaconNode, 0, len(s.nodes))
+	cooling := make([]*beaconNode, 0)
+	for _, node := range s.nodes {
+		if node.available() {
+			healthy = append(healthy, node)
+		} else {
+			cooling = append(cooling, node)
+		}
+	}
+	// stable so that, on equal scores, the order given by the user is kept
+	sort.SliceStable(healthy, func(i, j int) bool {
+		return healthy[i].score() > healthy[j].score()
+	})
+	return append(healthy, cooling...)
+}
+
+// NodesNum returns the number of configured beacon nodes.
+func (s *APIClient) NodesNum() int {
+	return len(s.nodes)
+}
+
+// failover runs the request against the beacon nodes in health order until one answers.
+// A 404 is only returned once every node answered it, as a lagging or pruned node may
+// not have data the others do.
+func failover[T any](s *APIClient, operation string, request func(*http.Service) (T, error)) (T, error) {
+	return failoverNodes(s, operation, func(node *beaconNode) (T, error) {
+		return request(node.api)
+	})
+}
+
+// failoverNodes is failover for requests that need the node itself, not only its client.
+func failoverNodes[T any](s *APIClient, operation string, request func(*beaconNode) (T, error)) (T, error) {
+	var zero T
+	var lastErr, notFoundErr error
+	var notFound T
+
+	nodes := s.rankedNodes()
+	for i, node := range nodes {
+		startTime := time.Now()
+		result, err := request(node)
+		if err == nil {
+			node.recordResult(false, time.Since(startTime))
+			if i > 0 {
+				beaconNodeFailovers.WithLabelValues(operation).Inc()
+			}
+			return result, nil
+		}
+		if response404(err.Error()) {
+			// the node answered, it just does not have the data
+			node.recordResult(false, time.Since(startTime))
+			notFound, notFoundErr = result, err
+			if i < len(nodes)-1 {
+				log.Debugf("%s request not found at %s, trying next beacon node", operation, node.address)
+			}
+			continue
+		}
+		if errors.Is(err, context.Canceled) || s.ctx.Err() != nil {
+			return zero, err
+		}
+
+		node.recordResult(true, time.Since(startTime))
+		lastErr = fmt.Errorf("%s: %w", node.address, err)
+		if len(s.nodes) > 1 {
+			log.Warnf("%s request failed at %s, trying next beacon node: %s", operation, node.address, err)
+		}
+	}
+
+	if lastErr != nil {
+		// some node could not answer, the data may still exist
+		return zero, lastErr
+	}
+	return notFound, notFoundErr
+}
+
+// checkRootConsistency asks every other beacon node for the same root and
+// reports the nodes that disagree with the one that was used for the download.
+// It is a no-op unless consistency checks were enabled.
+func (s *APIClient) checkRootConsistency(kind string, id string, expected phase0.Root, request func(*http.Service) (phase0.Root, error)) {
+	if !s.consistencyChecks || len(s.nodes) < 2 {
+		return
+	}
+
+	for _, node := range s.nodes {
+		if !node.available() {
+			continue
+		}
+		root, err := request(node.api)
+		if err != nil {
+			if !response404(err.Error()) {
+				log.Debugf("could not check %s root at %s on %s: %s", kind, id, node.address, err)
+				continue
+			}
+			root = phase0.Root{} // node does not know about it
+		}
+		if root != expected {
+			beaconRootMismatches.WithLabelValues(kind).Inc()
+			log.Warnf("%s root mismatch at %s: %s reports %#x, expected %#x", kind, id, node.address, root, expected)
+		}
+	}
+}
+
+func (s *APIClient) checkBlockRootConsistency(slot phase0.Slot, expected phase0.Root) {
+	s.checkRootConsistency("block", fmt.Sprintf("slot %d", slot), expected, func(cli *http.Service) (phase0.Root, error) {
+		root, err := cli.BeaconBlockRoot(s.ctx, &api.BeaconBlockRootOpts{
+			Block: fmt.Sprintf("%d", slot),
+		})
+		if err != nil || root == nil {
+			return phase0.Root{}, err
+		}
+		return *root.Data, nil
+	})
+}
+
+func (s *APIClient) checkStateRootConsistency(slot phase0.Slot, expected phase0.Root) {
+	s.checkRootConsistency("state", fmt.Sprintf("slot %d", slot), expected, func(cli *http.Service) (phase0.Root, error) {
+		root, err := cli.BeaconStateRoot(s.ctx, &api.BeaconStateRootOpts{
+			State: fmt.Sprintf("%d", slot),
+		})
+		if err != nil || root == nil {
+			return phase0.Root{}, err
+		}
+		return *root.Data, nil
+	})
+}
+
+// SubscribeToEvents subscribes the handler to the given topics on the healthiest beacon node
+// that accepts the subscription. The address of the chosen node is returned so that
+// the caller can report it as failing if the stream stalls.
+func (s *APIClient) SubscribeToEvents(ctx context.Context, topics []string, handler api.EventHandlerFunc) (string, error) {
+	var lastErr error
+	for _, node := range s.rankedNodes() {
+		err := node.api.Events(ctx, &api.EventsOpts{
+			Topics:  topics,
+			Handler: handler,
+		})
+		if err == nil {
+			return node.address, nil
+		}
+		node.recordResult(true, 0)
+		lastErr = fmt.Errorf("%s: %w", node.address, err)
+		log.Warnf("could not subscribe to %v events at %s: %s", topics, node.address, err)
+	}
+	return "", lastErr
+}
+
+// ReportNodeFailure penalizes a beacon node from outside the request path,
+// e.g. when its event stream stopped delivering events.
+func (s *APIClient) ReportNodeFailure(address string) {
+	for _, node := range s.nodes {
+		if node.address == address {
+			node.mu.Lock()
+			node.consecutiveFail = nodeFailureThreshold - 1
+			node.mu.Unlock()
+			node.recordResult(true, 0) // opens the cooldown
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package clientapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBeaconEndpoints(t *testing.T) {
+	assert.Equal(t, []string{"http://a:5052"}, parseBeaconEndpoints("http://a:5052"))
+	assert.Equal(t, []string{"http://a:5052", "http://b:5052"}, parseBeaconEndpoints(" http://a:5052, ,http://b:5052,"))
+	assert.Empty(t, parseBeaconEndpoints(""))
+}
+
+func TestRankedNodes(t *testing.T) {
+	primary := &beaconNode{address: "primary"}
+	backup := &beaconNode{address: "backup"}
+	cli := &APIClient{nodes: []*beaconNode{primary, backup}}
+
+	// equal scores keep the configured order
+	assert.Equal(t, []*beaconNode{primary, backup}, cli.rankedNodes())
+
+	// a failing node loses its place
+	primary.recordResult(true, 0)
+	backup.recordResult(false, 10*time.Millisecond)
+	assert.Equal(t, []*beaconNode{backup, primary}, cli.rankedNodes())
+
+	// a node in cooldown is only used as a last resort
+	backup.recordResult(true, 0)
+	backup.recordResult(true, 0)
+	backup.recordResult(true, 0)
+	primary.recordResult(false, 0)
+	assert.False(t, backup.available())
+	assert.Equal(t, []*beaconNode{primary, backup}, cli.rankedNodes())
+}
+
+func TestFailoverNotFound(t *testing.T) {
+	lagging := &beaconNode{address: "lagging"}
+	synced := &beaconNode{address: "synced"}
+	cli := &APIClient{ctx: context.Background(), nodes: []*beaconNode{lagging, synced}}
+
+	// a 404 of the first node is not the answer while another node has the data
+	calls := make([]string, 0)
+	result, err := failoverNodes(cli, "test", func(node *beaconNode) (string, error) {
+		calls = append(calls, node.address)
+		if node == lagging {
+			return "", errors.New("GET failed with status 404: not found")
+		}
+		return "block", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "block", result)
+	assert.Equal(t, []string{"lagging", "synced"}, calls)
+
+	// the 404 is returned once every node answered it
+	_, err = failoverNodes(cli, "test", func(node *beaconNode) (string, error) {
+		return "", errors.New("GET failed with status 404: not found")
+	})
+	require.Error(t, err)
+	assert.True(t, response404(err.Error()))
+
+	// a node that could not answer does not make the data missing
+	_, err = failoverNodes(cli, "test", func(node *beaconNode) (string, error) {
+		if node == lagging {
+			return "", errors.New("GET failed with status 404: not found")
+		}
+		return "", errors.New("connection refused")
+	})
+	require.Error(t, err)
+	assert.False(t, response404(err.Error()))
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/attestantio/go-eth2-client/api"
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/spec/deneb"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	local_spec "github.com/migalabs/goteth/pkg/spec"
@@ -11,11 +13,22 @@ import (
 )
 
 func (s *APIClient) RequestBlobSidecars(slot phase0.Slot) ([]*local_spec.AgnosticBlobSidecar, error) {
+	return s.requestBlobSidecars(slot, fmt.Sprintf("%d", slot))
+}
+
+// RequestBlobSidecarsByRoot requests the blob sidecars of a block that may no longer be canonical.
+func (s *APIClient) RequestBlobSidecarsByRoot(slot phase0.Slot, root phase0.Root) ([]*local_spec.AgnosticBlobSidecar, error) {
+	return s.requestBlobSidecars(slot, root.String())
+}
+
+func (s *APIClient) requestBlobSidecars(slot phase0.Slot, blockID string) ([]*local_spec.AgnosticBlobSidecar, error) {
 
 	agnosticBlobs := make([]*local_spec.AgnosticBlobSidecar, 0)
 
-	blobsResp, err := s.Api.BlobSidecars(s.ctx, &api.BlobSidecarsOpts{
-		Block: fmt.Sprintf("%d", slot),
+	blobsResp, err := failover(s, "blob_sidecars", func(cli *http.Service) (*api.Response[[]*deneb.BlobSidecar], error) {
+		return cli.BlobSidecars(s.ctx, &api.BlobSidecarsOpts{
+			Block: blockID,
+		})
 	})
 
 	if err != nil {
@@ -41,10 +54,8 @@ func (s *APIClient) RequestBlobSidecars(slot phase0.Slot) ([]*local_spec.Agnosti
 
 // requestKZGCommitmentFromSignedBlock fetches a block from /eth/v2/beacon/blocks/
 // to match the KZG Commitments to the blobs given by the new blobs endpoint.
-func (s *APIClient) requestKZGCommitmentFromSignedBlock(slot phase0.Slot) ([]deneb.KZGCommitment, error) {
-	resp, err := s.Api.SignedBeaconBlock(s.ctx, &api.SignedBeaconBlockOpts{
-		Block: fmt.Sprintf("%d", slot),
-	})
+func (s *APIClient) requestKZGCommitmentFromSignedBlock(slot phase0.Slot, blockID string) ([]deneb.KZGCommitment, error) {
+	block, err := s.downloadSignedBeaconBlock(blockID)
 
 	if err != nil {
 		if response404(err.Error()) {
@@ -53,15 +64,26 @@ func (s *APIClient) requestKZGCommitmentFromSignedBlock(slot phase0.Slot) ([]den
 		return nil, fmt.Errorf("could not retrieve KZGCommitments for slot %d: %s", slot, err)
 	}
 
-	return resp.Data.BlobKZGCommitments()
+	return block.BlobKZGCommitments()
 }
 
 // RequestFuluBlobs uses the new endpoint /eth/v1/beacon/blobs/{block_id}
 func (s *APIClient) RequestFuluBlobs(slot phase0.Slot) ([]*local_spec.AgnosticBlobSidecar, error) {
+	return s.requestFuluBlobs(slot, fmt.Sprintf("%d", slot))
+}
+
+// RequestFuluBlobsByRoot requests the blobs of a block that may no longer be canonical.
+func (s *APIClient) RequestFuluBlobsByRoot(slot phase0.Slot, root phase0.Root) ([]*local_spec.AgnosticBlobSidecar, error) {
+	return s.requestFuluBlobs(slot, root.String())
+}
+
+func (s *APIClient) requestFuluBlobs(slot phase0.Slot, blockID string) ([]*local_spec.AgnosticBlobSidecar, error) {
 	blobs := make([]*local_spec.AgnosticBlobSidecar, 0)
 
-	resp, err := s.Api.Blobs(s.ctx, &api.BlobsOpts{
-		Block: fmt.Sprintf("%d", slot),
+	resp, err := failover(s, "blobs", func(cli *http.Service) (*api.Response[v1.Blobs], error) {
+		return cli.Blobs(s.ctx, &api.BlobsOpts{
+			Block: blockID,
+		})
 	})
 
 	if err != nil {
@@ -72,7 +94,7 @@ func (s *APIClient) RequestFuluBlobs(slot phase0.Slot) ([]*local_spec.AgnosticBl
 	}
 
 	var kzgCommitments []deneb.KZGCommitment
-	kzgCommitments, err = s.requestKZGCommitmentFromSignedBlock(slot)
+	kzgCommitments, err = s.requestKZGCommitmentFromSignedBlock(slot, blockID)
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,57 @@
+package clientapi
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+// blobScheduleCache keeps the blob schedule, read once from the beacon spec
+type blobScheduleCache struct {
+	mu       sync.Mutex
+	schedule local_spec.BlobSchedule
+}
+
+// BlobSchedule returns the blob limits of the network, read from the beacon spec the
+// first time it is requested. Requests are retried until the spec can be read.
+func (s *APIClient) BlobSchedule() (local_spec.BlobSchedule, error) {
+	if s.blobSchedule == nil {
+		return nil, fmt.Errorf("blob schedule not initialized")
+	}
+	s.blobSchedule.mu.Lock()
+	defer s.blobSchedule.mu.Unlock()
+	if s.blobSchedule.schedule != nil {
+		return s.blobSchedule.schedule, nil
+	}
+
+	specResp, err := failover(s, "spec", func(cli *http.Service) (*api.Response[map[string]any], error) {
+		return cli.Spec(s.ctx, &api.SpecOpts{})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.blobSchedule.schedule = local_spec.NewBlobSchedule(specResp.Data)
+	for _, params := range s.blobSchedule.schedule {
+		log.Infof("blob schedule: from epoch %d, target %d and max %d blobs, update fraction %d",
+			params.Epoch, params.Target, params.Max, params.BaseFeeUpdateFraction)
+	}
+	return s.blobSchedule.schedule, nil
+}
+
+// setBlobParameters sets the blob limits of the epoch of a Deneb+ block.
+func (s *APIClient) setBlobParameters(block *local_spec.AgnosticBlock) {
+	if block.HardForkVersion < spec.DataVersionDeneb {
+		return
+	}
+	schedule, err := s.BlobSchedule()
+	if err != nil {
+		log.Errorf("could not read the blob schedule for block at slot %d: %s", block.Slot, err)
+		return
+	}
+	block.BlobParameters = schedule.At(phase0.Epoch(block.Slot / local_spec.SlotsPerEpoch))
+}
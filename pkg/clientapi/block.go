@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/altair"
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
@@ -15,7 +17,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	local_spec "github.com/migalabs/goteth/pkg/spec"
-	"github.com/migalabs/goteth/pkg/utils"
 	bitfield "github.com/prysmaticlabs/go-bitfield"
 )
 
@@ -32,14 +33,12 @@ func (s *APIClient) RequestBeaconBlock(slot phase0.Slot) (*local_spec.AgnosticBl
 
 	startTime := time.Now()
 	err := errors.New("first attempt")
-	var newBlock *api.Response[*spec.VersionedSignedBeaconBlock]
+	var newBlock *spec.VersionedSignedBeaconBlock
 
 	attempts := 0
 	for err != nil && attempts < s.maxRetries {
 
-		newBlock, err = s.Api.SignedBeaconBlock(s.ctx, &api.SignedBeaconBlockOpts{
-			Block: fmt.Sprintf("%d", slot),
-		})
+		newBlock, err = s.downloadSignedBeaconBlock(fmt.Sprintf("%d", slot))
 		if err != nil {
 			if response404(err.Error()) {
 				if attempts < s.maxRetries-1 {
@@ -54,10 +53,13 @@ func (s *APIClient) RequestBeaconBlock(slot phase0.Slot) (*local_spec.AgnosticBl
 				return s.CreateMissingBlock(slot), nil
 			}
 
-			timeoutTime := utils.RoutineFlushTimeout * time.Duration(attempts+1)
-			ticker := time.NewTicker(timeoutTime)
-			log.Warnf("retrying request: %s. Attempt number: %d", routineKey, attempts)
-			<-ticker.C
+			if !isTransientError(err) || attempts+1 >= s.maxRetries {
+				break
+			}
+			log.Warnf("retrying request: %s. Attempt number: %d: %s", routineKey, attempts, err)
+			if !s.waitBackoff("block", attempts) {
+				break
+			}
 
 		}
 		attempts += 1
@@ -67,12 +69,14 @@ func (s *APIClient) RequestBeaconBlock(slot phase0.Slot) (*local_spec.AgnosticBl
 		// close the channel (to tell other routines to stop processing and end)
 		return &local_spec.AgnosticBlock{}, fmt.Errorf("unable to retrieve Beacon Block at slot %d: %s", slot, err.Error())
 	}
-	customBlock, err := local_spec.GetCustomBlock(*newBlock.Data)
+	customBlock, err := local_spec.GetCustomBlock(*newBlock)
 
 	if err != nil {
 		// close the channel (to tell other routines to stop processing and end)
 		return &local_spec.AgnosticBlock{}, fmt.Errorf("unable to parse Beacon Block at slot %d: %s", slot, err.Error())
 	}
+	s.checkBlockRootConsistency(slot, customBlock.Root)
+	s.setBlobParameters(&customBlock)
 
 	// fill in block size on custom block using RequestBlockByHash
 	// shows error inside function if ELApi is not defined
@@ -105,11 +109,52 @@ func (s *APIClient) RequestBeaconBlock(slot phase0.Slot) (*local_spec.AgnosticBl
 	return &customBlock, nil
 }
 
+// downloadSignedBeaconBlock requests the block, as SSZ unless disabled.
+func (s *APIClient) downloadSignedBeaconBlock(blockID string) (*spec.VersionedSignedBeaconBlock, error) {
+	if s.ssz {
+		return s.requestSignedBeaconBlockSSZ(blockID)
+	}
+	newBlock, err := failover(s, "block", func(cli *http.Service) (*api.Response[*spec.VersionedSignedBeaconBlock], error) {
+		return cli.SignedBeaconBlock(s.ctx, &api.SignedBeaconBlockOpts{
+			Block: blockID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newBlock.Data, nil
+}
+
+// RequestBeaconBlockByRoot downloads a block that may no longer be canonical, used to walk
+// a fork back to its common ancestor. Only the beacon block is parsed, the execution
+// payload size, state root and rewards are not requested.
+func (s *APIClient) RequestBeaconBlockByRoot(root phase0.Root) (*local_spec.AgnosticBlock, error) {
+	log.Debugf("downloading block %s", root)
+
+	var newBlock *spec.VersionedSignedBeaconBlock
+	var err error
+	for attempts := 0; attempts < s.maxRetries; attempts++ {
+		newBlock, err = s.downloadSignedBeaconBlock(root.String())
+		if err == nil || !isTransientError(err) || attempts+1 >= s.maxRetries || !s.waitBackoff("block", attempts) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve Beacon Block %s: %s", root, err.Error())
+	}
+	customBlock, err := local_spec.GetCustomBlock(*newBlock)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse Beacon Block %s: %s", root, err.Error())
+	}
+	return &customBlock, nil
+}
+
 func (s *APIClient) RequestFinalizedBeaconBlock() (*local_spec.AgnosticBlock, error) {
 
-	finalityCheckpoint, _ := s.Api.Finality(s.ctx, &api.FinalityOpts{
-		State: "head",
-	})
+	finalityCheckpoint, err := s.RequestFinality()
+	if err != nil {
+		return nil, err
+	}
 
 	finalizedSlot := finalityCheckpoint.Data.Finalized.Epoch * local_spec.SlotsPerEpoch
 
@@ -118,8 +163,10 @@ func (s *APIClient) RequestFinalizedBeaconBlock() (*local_spec.AgnosticBlock, er
 
 func (s *APIClient) RequestBlockRoot(slot phase0.Slot) phase0.Root {
 
-	root, err := s.Api.BeaconBlockRoot(s.ctx, &api.BeaconBlockRootOpts{
-		Block: fmt.Sprintf("%d", slot),
+	root, err := failover(s, "block_root", func(cli *http.Service) (*api.Response[*phase0.Root], error) {
+		return cli.BeaconBlockRoot(s.ctx, &api.BeaconBlockRootOpts{
+			Block: fmt.Sprintf("%d", slot),
+		})
 	})
 	if err != nil {
 		if strings.Contains(err.Error(), "404") {
@@ -137,9 +184,11 @@ func (s *APIClient) RequestBlockRoot(slot phase0.Slot) phase0.Root {
 }
 
 func (s *APIClient) CreateMissingBlock(slot phase0.Slot) *local_spec.AgnosticBlock {
-	duties, err := s.Api.ProposerDuties(s.ctx, &api.ProposerDutiesOpts{
-		Indices: []phase0.ValidatorIndex{},
-		Epoch:   phase0.Epoch(slot / 32),
+	duties, err := failover(s, "proposer_duties", func(cli *http.Service) (*api.Response[[]*apiv1.ProposerDuty], error) {
+		return cli.ProposerDuties(s.ctx, &api.ProposerDutiesOpts{
+			Indices: []phase0.ValidatorIndex{},
+			Epoch:   phase0.Epoch(slot / 32),
+		})
 	})
 	proposerValIdx := phase0.ValidatorIndex(0)
 	if err != nil {
@@ -215,8 +264,10 @@ func (s *APIClient) RequestExecutionBlockByHash(hash common.Hash) (*types.Block,
 
 func (s *APIClient) RequestCurrentHead() phase0.Slot {
 
-	head, err := s.Api.BeaconBlockHeader(s.ctx, &api.BeaconBlockHeaderOpts{
-		Block: "head",
+	head, err := failover(s, "head", func(cli *http.Service) (*api.Response[*apiv1.BeaconBlockHeader], error) {
+		return cli.BeaconBlockHeader(s.ctx, &api.BeaconBlockHeaderOpts{
+			Block: "head",
+		})
 	})
 	if err != nil {
 		log.Panicf("could not request current head: %s", err)
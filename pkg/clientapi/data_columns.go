@@ -0,0 +1,62 @@
+package clientapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+// RequestDataColumnSidecars requests the PeerDAS columns a beacon node holds for a block,
+// through the debug endpoint as the columns are not served by the standard API.
+// Unless the node is a supernode, only the columns it custodies are returned.
+func (s *APIClient) RequestDataColumnSidecars(slot phase0.Slot, root phase0.Root) ([]local_spec.AgnosticDataColumnSidecar, error) {
+	columns, err := failoverNodes(s, "data_column_sidecars", func(node *beaconNode) ([]local_spec.AgnosticDataColumnSidecar, error) {
+		return s.requestDataColumnSidecars(node, root)
+	})
+	if err != nil {
+		if response404(err.Error()) {
+			return make([]local_spec.AgnosticDataColumnSidecar, 0), nil
+		}
+		return nil, fmt.Errorf("could not retrieve data column sidecars for slot %d: %s", slot, err)
+	}
+	return columns, nil
+}
+
+func (s *APIClient) requestDataColumnSidecars(node *beaconNode, root phase0.Root) ([]local_spec.AgnosticDataColumnSidecar, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, QueryTimeout)
+	defer cancel()
+
+	endpoint := fmt.Sprintf("/eth/v1/debug/beacon/data_column_sidecars/%s", root)
+	url := strings.TrimSuffix(node.address, "/") + endpoint
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not build data column sidecars request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for key, value := range s.extraHeaders {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GET endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &api.Error{
+			Method:     nethttp.MethodGet,
+			Endpoint:   endpoint,
+			StatusCode: resp.StatusCode,
+			Data:       data,
+		}
+	}
+	return local_spec.ParseDataColumnSidecars(root, resp.Body)
+}
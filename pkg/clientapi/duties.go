@@ -4,14 +4,18 @@ import (
 	"fmt"
 
 	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/migalabs/goteth/pkg/spec"
 )
 
 func (s *APIClient) NewEpochData(slot phase0.Slot) spec.EpochDuties {
 
-	epochCommittees, err := s.Api.BeaconCommittees(s.ctx, &api.BeaconCommitteesOpts{
-		State: fmt.Sprintf("%d", slot),
+	epochCommittees, err := failover(s, "committees", func(cli *http.Service) (*api.Response[[]*apiv1.BeaconCommittee], error) {
+		return cli.BeaconCommittees(s.ctx, &api.BeaconCommitteesOpts{
+			State: fmt.Sprintf("%d", slot),
+		})
 	})
 
 	if err != nil {
@@ -37,8 +41,10 @@ func (s *APIClient) NewEpochData(slot phase0.Slot) spec.EpochDuties {
 		}
 	}
 
-	proposerDuties, err := s.Api.ProposerDuties(s.ctx, &api.ProposerDutiesOpts{
-		Epoch: phase0.Epoch(slot / spec.SlotsPerEpoch),
+	proposerDuties, err := failover(s, "proposer_duties", func(cli *http.Service) (*api.Response[[]*apiv1.ProposerDuty], error) {
+		return cli.ProposerDuties(s.ctx, &api.ProposerDutiesOpts{
+			Epoch: phase0.Epoch(slot / spec.SlotsPerEpoch),
+		})
 	})
 
 	if err != nil {
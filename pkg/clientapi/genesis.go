@@ -1,12 +1,48 @@
 package clientapi
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/http"
+)
 
 func (s APIClient) RequestGenesis() time.Time {
-	genesis, err := s.Api.GenesisTime(s.ctx)
+	genesis, err := failover(&s, "genesis", func(cli *http.Service) (time.Time, error) {
+		return cli.GenesisTime(s.ctx)
+	})
 	if err != nil {
 		log.Panicf("could not get genesis time: %s", err)
 	}
 
 	return genesis
 }
+
+// RequestSecondsPerSlot reads the slot duration of the network from the beacon spec.
+func (s *APIClient) RequestSecondsPerSlot() (uint64, error) {
+	return s.requestSpecValue("SECONDS_PER_SLOT")
+}
+
+// RequestSlotsPerEpoch reads the slots of an epoch of the network from the beacon spec.
+func (s *APIClient) RequestSlotsPerEpoch() (uint64, error) {
+	return s.requestSpecValue("SLOTS_PER_EPOCH")
+}
+
+// requestSpecValue reads a numeric value of the beacon spec, durations in seconds.
+func (s *APIClient) requestSpecValue(key string) (uint64, error) {
+	specResp, err := failover(s, "spec", func(cli *http.Service) (*api.Response[map[string]any], error) {
+		return cli.Spec(s.ctx, &api.SpecOpts{})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not get the beacon node spec: %w", err)
+	}
+
+	switch v := specResp.Data[key].(type) {
+	case time.Duration:
+		return uint64(v / time.Second), nil
+	case uint64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("%s not found in the beacon node spec", key)
+}
@@ -138,3 +138,166 @@ func (m *receiptMetrics) getPrometheusMetrics() *metrics.MetricsModule {
 
 	return mod
 }
+
+var (
+	registerBeaconNodeMetricsOnce sync.Once
+
+	beaconNodeScore = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: strings.ToLower(utils.CliName),
+			Subsystem: clientAPIMetricsName,
+			Name:      "beacon_node_health_score",
+			Help:      "Health score of each configured beacon node, used to order failover.",
+		},
+		[]string{"node"},
+	)
+
+	beaconNodeFailovers = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: strings.ToLower(utils.CliName),
+			Subsystem: clientAPIMetricsName,
+			Name:      "beacon_node_failovers_total",
+			Help:      "Total number of requests that were answered by a fallback beacon node.",
+		},
+		[]string{"operation"},
+	)
+
+	beaconRootMismatches = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: strings.ToLower(utils.CliName),
+			Subsystem: clientAPIMetricsName,
+			Name:      "beacon_root_mismatches_total",
+			Help:      "Total number of block/state roots that differ between beacon nodes.",
+		},
+		[]string{"kind"},
+	)
+)
+
+func (s *APIClient) getBeaconNodePrometheusMetrics() *metrics.MetricsModule {
+	mod := metrics.NewMetricsModule(
+		"beacon_nodes",
+		"health and failover metrics of the configured beacon nodes",
+	)
+
+	initFn := func() error {
+		registerBeaconNodeMetricsOnce.Do(func() {
+			prometheus.MustRegister(beaconNodeScore)
+			prometheus.MustRegister(beaconNodeFailovers)
+			prometheus.MustRegister(beaconRootMismatches)
+			for _, node := range s.nodes {
+				beaconNodeScore.WithLabelValues(node.address).Set(node.score())
+			}
+		})
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		scores := make(map[string]float64, len(s.nodes))
+		for _, node := range s.nodes {
+			scores[node.address] = node.score()
+		}
+		return scores, nil
+	}
+
+	indvMetrics, err := metrics.NewIndvMetrics(
+		"beacon_node_health",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init beacon_node_health metrics"))
+		return nil
+	}
+
+	if err := mod.AddIndvMetric(indvMetrics); err != nil {
+		log.Error(errors.Wrap(err, "unable to register beacon node metrics module"))
+		return nil
+	}
+
+	return mod
+}
+
+var (
+	registerDownloadMetricsOnce sync.Once
+
+	sszDownloadSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: strings.ToLower(utils.CliName),
+			Subsystem: clientAPIMetricsName,
+			Name:      "ssz_download_seconds",
+			Help:      "Time spent downloading SSZ encoded states and blocks from the beacon node.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 40, 80},
+		},
+		[]string{"object"},
+	)
+
+	sszDecodeSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: strings.ToLower(utils.CliName),
+			Subsystem: clientAPIMetricsName,
+			Name:      "ssz_decode_seconds",
+			Help:      "Time spent decoding SSZ encoded states and blocks.",
+			Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+		},
+		[]string{"object"},
+	)
+
+	sszDownloadBytes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: strings.ToLower(utils.CliName),
+			Subsystem: clientAPIMetricsName,
+			Name:      "ssz_download_bytes",
+			Help:      "Size of the SSZ encoded states and blocks downloaded from the beacon node.",
+			Buckets:   prometheus.ExponentialBuckets(1<<10, 4, 12),
+		},
+		[]string{"object"},
+	)
+
+	requestRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: strings.ToLower(utils.CliName),
+			Subsystem: clientAPIMetricsName,
+			Name:      "request_retries_total",
+			Help:      "Total number of beacon node requests retried after a transient error.",
+		},
+		[]string{"operation"},
+	)
+)
+
+func getDownloadPrometheusMetrics() *metrics.MetricsModule {
+	mod := metrics.NewMetricsModule(
+		"downloads",
+		"download and decode times of the beacon states and blocks",
+	)
+
+	initFn := func() error {
+		registerDownloadMetricsOnce.Do(func() {
+			prometheus.MustRegister(sszDownloadSeconds)
+			prometheus.MustRegister(sszDecodeSeconds)
+			prometheus.MustRegister(sszDownloadBytes)
+			prometheus.MustRegister(requestRetries)
+		})
+		return nil
+	}
+
+	updateFn := func() (interface{}, error) {
+		return nil, nil
+	}
+
+	indvMetrics, err := metrics.NewIndvMetrics(
+		"ssz_downloads",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		log.Error(errors.Wrap(err, "unable to init ssz_downloads metrics"))
+		return nil
+	}
+
+	if err := mod.AddIndvMetric(indvMetrics); err != nil {
+		log.Error(errors.Wrap(err, "unable to register download metrics module"))
+		return nil
+	}
+
+	return mod
+}
@@ -12,7 +12,19 @@ import (
 )
 
 func (s *APIClient) RequestBlockRewards(slot phase0.Slot) (spec.BlockRewards, error) {
-	parsedURL, _ := url.Parse(s.bnEndpoint)
+	var lastErr error
+	for _, node := range s.rankedNodes() {
+		rewards, err := s.requestBlockRewards(node.address, slot)
+		if err == nil {
+			return rewards, nil
+		}
+		lastErr = err
+	}
+	return spec.BlockRewards{}, lastErr
+}
+
+func (s *APIClient) requestBlockRewards(endpoint string, slot phase0.Slot) (spec.BlockRewards, error) {
+	parsedURL, _ := url.Parse(endpoint)
 	parsedURL.Path = fmt.Sprintf("/eth/v1/beacon/rewards/blocks/%d", slot)
 
 	req, _ := http.NewRequestWithContext(s.ctx, http.MethodGet, parsedURL.String(), nil)
@@ -0,0 +1,325 @@
+package clientapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/fulu"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+const (
+	contentTypeSSZ = "application/octet-stream"
+
+	// bounds of the SSZ responses, so a misbehaving node cannot make us buffer more.
+	// A state is mostly its validators: the record and balance of each, plus its
+	// participation flags and inactivity score since Altair. The rest (roots, randao
+	// mixes, sync committees, pending queues) fits in the fixed part.
+	maxStateValidators   = 4_000_000 // about twice the mainnet registry
+	stateFixedSize       = 64 << 20
+	phase0ValidatorBytes = 121 + 8
+	altairValidatorBytes = 121 + 8 + 1 + 1 + 8
+	maxBlockSize         = 10 << 20 // MAX_PAYLOAD_SIZE, larger blocks are not gossiped
+
+	// backoff between retries of transient errors
+	minRetryBackoff = 1 * time.Second
+	maxRetryBackoff = 30 * time.Second
+)
+
+// errSSZNotSupported is returned when the beacon node answers with another content type.
+var errSSZNotSupported = errors.New("beacon node does not serve ssz")
+
+// sszResponse is the raw body of an SSZ response together with its fork.
+type sszResponse struct {
+	version spec.DataVersion
+	body    []byte
+}
+
+// WithSSZ requests states and blocks as application/octet-stream and decodes them locally,
+// which is much faster and lighter than the JSON encoding. Responses are not decoded while
+// streaming: each one is buffered whole, up to a size cap, and decoded afterwards.
+func WithSSZ(enabled bool) APIClientOption {
+	return func(s *APIClient) error {
+		s.ssz = enabled
+		return nil
+	}
+}
+
+// maxSSZSize returns the largest SSZ response accepted for a state or a block of the fork.
+func maxSSZSize(object string, version spec.DataVersion) int64 {
+	if object != "state" {
+		return maxBlockSize
+	}
+	if version == spec.DataVersionPhase0 {
+		return stateFixedSize + maxStateValidators*phase0ValidatorBytes
+	}
+	return stateFixedSize + maxStateValidators*altairValidatorBytes
+}
+
+// requestSSZ downloads an SSZ object from the given beacon node as a size-capped buffered read:
+// the body is read whole into a single buffer, sized after Content-Length when given, and
+// refused beyond the maximum size of the object at its fork. The go-eth2-client types only
+// unmarshal from a byte slice, so memory is bounded by that cap, not by decoding incrementally.
+func (s *APIClient) requestSSZ(node *beaconNode, endpoint string, object string) (*sszResponse, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, QueryTimeout)
+	defer cancel()
+
+	url := strings.TrimSuffix(node.address, "/") + endpoint
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not build ssz request: %w", err)
+	}
+	req.Header.Set("Accept", contentTypeSSZ)
+	for key, value := range s.extraHeaders {
+		req.Header.Set(key, value)
+	}
+
+	startTime := time.Now()
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GET endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, &api.Error{
+			Method:     nethttp.MethodGet,
+			Endpoint:   endpoint,
+			StatusCode: resp.StatusCode,
+			Data:       data,
+		}
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), contentTypeSSZ) {
+		return nil, errSSZNotSupported
+	}
+
+	version, err := spec.DataVersionFromString(strings.ToLower(resp.Header.Get("Eth-Consensus-Version")))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse consensus version: %w", err)
+	}
+
+	maxSize := maxSSZSize(object, version)
+	if resp.ContentLength > maxSize {
+		return nil, fmt.Errorf("%s %s ssz response too large: %d bytes", version, object, resp.ContentLength)
+	}
+	var body []byte
+	if resp.ContentLength >= 0 {
+		body = make([]byte, resp.ContentLength)
+		if _, err := io.ReadFull(resp.Body, body); err != nil {
+			return nil, fmt.Errorf("failed to read ssz response: %w", err)
+		}
+	} else {
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ssz response: %w", err)
+		}
+		if int64(len(body)) > maxSize {
+			return nil, fmt.Errorf("%s %s ssz response too large: more than %d bytes", version, object, maxSize)
+		}
+	}
+	sszDownloadSeconds.WithLabelValues(object).Observe(time.Since(startTime).Seconds())
+	sszDownloadBytes.WithLabelValues(object).Observe(float64(len(body)))
+
+	return &sszResponse{
+		version: version,
+		body:    body,
+	}, nil
+}
+
+// requestBeaconStateSSZ downloads and decodes a beacon state, falling back to
+// the standard client on nodes that do not serve SSZ.
+func (s *APIClient) requestBeaconStateSSZ(stateID string) (*spec.VersionedBeaconState, error) {
+	return failoverNodes(s, "state", func(node *beaconNode) (*spec.VersionedBeaconState, error) {
+		res, err := s.requestSSZ(node, fmt.Sprintf("/eth/v2/debug/beacon/states/%s", stateID), "state")
+		if errors.Is(err, errSSZNotSupported) {
+			state, err := node.api.BeaconState(s.ctx, &api.BeaconStateOpts{
+				State: stateID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return state.Data, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		startTime := time.Now()
+		state, err := decodeBeaconState(res)
+		res.body = nil // release the raw buffer as soon as possible
+		if err != nil {
+			return nil, err
+		}
+		sszDecodeSeconds.WithLabelValues("state").Observe(time.Since(startTime).Seconds())
+		return state, nil
+	})
+}
+
+// requestSignedBeaconBlockSSZ downloads and decodes a signed beacon block, falling back to
+// the standard client on nodes that do not serve SSZ.
+func (s *APIClient) requestSignedBeaconBlockSSZ(blockID string) (*spec.VersionedSignedBeaconBlock, error) {
+	return failoverNodes(s, "block", func(node *beaconNode) (*spec.VersionedSignedBeaconBlock, error) {
+		res, err := s.requestSSZ(node, fmt.Sprintf("/eth/v2/beacon/blocks/%s", blockID), "block")
+		if errors.Is(err, errSSZNotSupported) {
+			block, err := node.api.SignedBeaconBlock(s.ctx, &api.SignedBeaconBlockOpts{
+				Block: blockID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return block.Data, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		startTime := time.Now()
+		block, err := decodeSignedBeaconBlock(res)
+		res.body = nil
+		if err != nil {
+			return nil, err
+		}
+		sszDecodeSeconds.WithLabelValues("block").Observe(time.Since(startTime).Seconds())
+		return block, nil
+	})
+}
+
+func decodeBeaconState(res *sszResponse) (*spec.VersionedBeaconState, error) {
+	state := &spec.VersionedBeaconState{
+		Version: res.version,
+	}
+
+	var err error
+	switch res.version {
+	case spec.DataVersionPhase0:
+		state.Phase0 = &phase0.BeaconState{}
+		err = state.Phase0.UnmarshalSSZ(res.body)
+	case spec.DataVersionAltair:
+		state.Altair = &altair.BeaconState{}
+		err = state.Altair.UnmarshalSSZ(res.body)
+	case spec.DataVersionBellatrix:
+		state.Bellatrix = &bellatrix.BeaconState{}
+		err = state.Bellatrix.UnmarshalSSZ(res.body)
+	case spec.DataVersionCapella:
+		state.Capella = &capella.BeaconState{}
+		err = state.Capella.UnmarshalSSZ(res.body)
+	case spec.DataVersionDeneb:
+		state.Deneb = &deneb.BeaconState{}
+		err = state.Deneb.UnmarshalSSZ(res.body)
+	case spec.DataVersionElectra:
+		state.Electra = &electra.BeaconState{}
+		err = state.Electra.UnmarshalSSZ(res.body)
+	case spec.DataVersionFulu:
+		state.Fulu = &fulu.BeaconState{}
+		err = state.Fulu.UnmarshalSSZ(res.body)
+	default:
+		return nil, fmt.Errorf("unhandled state version %s", res.version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s beacon state: %w", res.version, err)
+	}
+	return state, nil
+}
+
+func decodeSignedBeaconBlock(res *sszResponse) (*spec.VersionedSignedBeaconBlock, error) {
+	block := &spec.VersionedSignedBeaconBlock{
+		Version: res.version,
+	}
+
+	var err error
+	switch res.version {
+	case spec.DataVersionPhase0:
+		block.Phase0 = &phase0.SignedBeaconBlock{}
+		err = block.Phase0.UnmarshalSSZ(res.body)
+	case spec.DataVersionAltair:
+		block.Altair = &altair.SignedBeaconBlock{}
+		err = block.Altair.UnmarshalSSZ(res.body)
+	case spec.DataVersionBellatrix:
+		block.Bellatrix = &bellatrix.SignedBeaconBlock{}
+		err = block.Bellatrix.UnmarshalSSZ(res.body)
+	case spec.DataVersionCapella:
+		block.Capella = &capella.SignedBeaconBlock{}
+		err = block.Capella.UnmarshalSSZ(res.body)
+	case spec.DataVersionDeneb:
+		block.Deneb = &deneb.SignedBeaconBlock{}
+		err = block.Deneb.UnmarshalSSZ(res.body)
+	case spec.DataVersionElectra:
+		block.Electra = &electra.SignedBeaconBlock{}
+		err = block.Electra.UnmarshalSSZ(res.body)
+	case spec.DataVersionFulu:
+		block.Fulu = &electra.SignedBeaconBlock{}
+		err = block.Fulu.UnmarshalSSZ(res.body)
+	default:
+		return nil, fmt.Errorf("unhandled block version %s", res.version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s signed beacon block: %w", res.version, err)
+	}
+	return block, nil
+}
+
+// isTransientError tells whether a failed request is worth retrying:
+// timeouts, connection errors, truncated bodies and 408/429/5xx answers.
+func isTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case nethttp.StatusRequestTimeout,
+			nethttp.StatusTooManyRequests,
+			nethttp.StatusInternalServerError,
+			nethttp.StatusBadGateway,
+			nethttp.StatusServiceUnavailable,
+			nethttp.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryBackoff returns the time to wait before the given retry (starting at 0),
+// doubling from minRetryBackoff up to maxRetryBackoff.
+func retryBackoff(attempt int) time.Duration {
+	backoff := minRetryBackoff
+	for i := 0; i < attempt && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRetryBackoff)
+}
+
+// waitBackoff sleeps before the given retry, returning false if the client is closing.
+func (s *APIClient) waitBackoff(operation string, attempt int) bool {
+	requestRetries.WithLabelValues(operation).Inc()
+	select {
+	case <-s.ctx.Done():
+		return false
+	case <-time.After(retryBackoff(attempt)):
+		return true
+	}
+}
@@ -0,0 +1,134 @@
+package clientapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAltairBlock(slot phase0.Slot) *altair.SignedBeaconBlock {
+	return &altair.SignedBeaconBlock{
+		Message: &altair.BeaconBlock{
+			Slot:          slot,
+			ProposerIndex: 7,
+			Body: &altair.BeaconBlockBody{
+				ETH1Data:          &phase0.ETH1Data{BlockHash: make([]byte, 32)},
+				ProposerSlashings: []*phase0.ProposerSlashing{},
+				AttesterSlashings: []*phase0.AttesterSlashing{},
+				Attestations:      []*phase0.Attestation{},
+				Deposits:          []*phase0.Deposit{},
+				VoluntaryExits:    []*phase0.SignedVoluntaryExit{},
+				SyncAggregate: &altair.SyncAggregate{
+					SyncCommitteeBits: bitfield.NewBitvector512(),
+				},
+			},
+		},
+	}
+}
+
+func TestRequestSignedBeaconBlockSSZ(t *testing.T) {
+	encoded, err := testAltairBlock(100).MarshalSSZ()
+	require.NoError(t, err)
+
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		switch r.URL.Path {
+		case "/eth/v2/beacon/blocks/100":
+			assert.Equal(t, contentTypeSSZ, r.Header.Get("Accept"))
+			assert.Equal(t, "secret", r.Header.Get("X-goog-api-key"))
+			w.Header().Set("Content-Type", contentTypeSSZ)
+			w.Header().Set("Eth-Consensus-Version", "altair")
+			w.Header().Set("Content-Length", fmt.Sprintf("%d", len(encoded)))
+			_, _ = w.Write(encoded)
+		case "/eth/v2/beacon/blocks/103":
+			w.Header().Set("Content-Type", contentTypeSSZ)
+			w.Header().Set("Eth-Consensus-Version", "altair")
+			_, _ = w.Write(make([]byte, maxBlockSize+1)) // no Content-Length, over the block bound
+		case "/eth/v2/beacon/blocks/101":
+			w.Header().Set("Content-Type", contentTypeSSZ)
+			w.Header().Set("Eth-Consensus-Version", "altair")
+			_, _ = w.Write(encoded[:len(encoded)/2]) // truncated
+		default:
+			w.WriteHeader(nethttp.StatusNotFound)
+			_, _ = io.WriteString(w, `{"code":404,"message":"not found"}`)
+		}
+	}))
+	defer server.Close()
+
+	cli := &APIClient{
+		ctx:          context.Background(),
+		nodes:        []*beaconNode{{address: server.URL}},
+		extraHeaders: map[string]string{"X-goog-api-key": "secret"},
+		httpClient:   server.Client(),
+	}
+
+	block, err := cli.requestSignedBeaconBlockSSZ("100")
+	require.NoError(t, err)
+	assert.Equal(t, spec.DataVersionAltair, block.Version)
+	assert.Equal(t, phase0.Slot(100), block.Altair.Message.Slot)
+	assert.Equal(t, phase0.ValidatorIndex(7), block.Altair.Message.ProposerIndex)
+
+	_, err = cli.requestSignedBeaconBlockSSZ("101")
+	assert.Error(t, err)
+
+	_, err = cli.requestSignedBeaconBlockSSZ("102")
+	require.Error(t, err)
+	assert.True(t, response404(err.Error()))
+	assert.False(t, isTransientError(err))
+
+	_, err = cli.requestSignedBeaconBlockSSZ("103")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too large")
+}
+
+func TestMaxSSZSize(t *testing.T) {
+	assert.Equal(t, int64(maxBlockSize), maxSSZSize("block", spec.DataVersionFulu))
+	assert.Less(t, maxSSZSize("state", spec.DataVersionPhase0), maxSSZSize("state", spec.DataVersionAltair))
+	assert.Equal(t, maxSSZSize("state", spec.DataVersionAltair), maxSSZSize("state", spec.DataVersionElectra))
+	// well under a GiB, well over a mainnet state
+	assert.Less(t, maxSSZSize("state", spec.DataVersionFulu), int64(1<<30))
+	assert.Greater(t, maxSSZSize("state", spec.DataVersionFulu), int64(300<<20))
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{"nil", nil, false},
+		{"cancelled", context.Canceled, false},
+		{"deadline", fmt.Errorf("node: %w", context.DeadlineExceeded), true},
+		{"truncated body", fmt.Errorf("failed to read: %w", io.ErrUnexpectedEOF), true},
+		{"service unavailable", errors.Join(errors.New("wrapped"), &api.Error{StatusCode: 503}), true},
+		{"too many requests", &api.Error{StatusCode: 429}, true},
+		{"not found", &api.Error{StatusCode: 404}, false},
+		{"bad request", &api.Error{StatusCode: 400}, false},
+		{"other", errors.New("failed to decode"), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.transient, isTransientError(test.err))
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	assert.Equal(t, minRetryBackoff, retryBackoff(0))
+	assert.Equal(t, 2*minRetryBackoff, retryBackoff(1))
+	assert.Equal(t, 16*time.Second, retryBackoff(4))
+	assert.Equal(t, maxRetryBackoff, retryBackoff(10))
+}
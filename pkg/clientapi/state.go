@@ -1,16 +1,16 @@
 package clientapi
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	local_spec "github.com/migalabs/goteth/pkg/spec"
-	"github.com/migalabs/goteth/pkg/utils"
 )
 
 var (
@@ -36,54 +36,102 @@ func (s *APIClient) requestBeaconStateWithID(slot phase0.Slot, stateID string, k
 
 	startTime := time.Now()
 
-	err := errors.New("first attempt")
-	var newState *api.Response[*spec.VersionedBeaconState]
+	var resultState *local_spec.AgnosticState
+	var err error
+	if s.nonArchivalStates {
+		resultState, err = s.requestStateFromResourcesWithRetries(slot, stateID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to build Beacon State from the beacon node resources, closing requester routine. %s", err.Error())
+		}
+		log.Infof("state at slot %d built from resources in %f seconds (id=%s)", slot, time.Since(startTime).Seconds(), stateID)
+	} else {
+		resultState, err = s.requestFullBeaconState(slot, stateID, routineKey)
+		if err != nil {
+			return nil, err
+		}
+		log.Infof("state at slot %d downloaded in %f seconds (id=%s)", slot, time.Since(startTime).Seconds(), stateID)
+	}
 
-	attempts := 0
-	for err != nil && attempts < s.maxRetries {
+	var zeroRoot phase0.Root
+	if knownRoot != zeroRoot {
+		resultState.StateRoot = knownRoot
+	} else {
+		stateRoot, err := s.RequestStateRoot(slot)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get state root at slot %d: %w", slot, err)
+		}
+		resultState.StateRoot = stateRoot
+	}
+	s.checkStateRootConsistency(slot, resultState.StateRoot)
 
-		newState, err = s.Api.BeaconState(s.ctx, &api.BeaconStateOpts{
-			State: stateID,
-		})
+	return resultState, nil
+}
 
-		if errors.Is(err, context.DeadlineExceeded) {
-			ticker := time.NewTicker(utils.RoutineFlushTimeout)
-			log.Warnf("retrying request: %s", routineKey)
-			<-ticker.C
+func (s *APIClient) requestFullBeaconState(slot phase0.Slot, stateID string, routineKey string) (*local_spec.AgnosticState, error) {
+	var newState *spec.VersionedBeaconState
+	var err error
 
+	for attempts := 0; ; attempts++ {
+		newState, err = s.downloadBeaconState(stateID)
+		if err == nil || attempts+1 >= s.maxRetries || !isTransientError(err) {
+			break
+		}
+		log.Warnf("retrying request: %s. Attempt number: %d: %s", routineKey, attempts, err)
+		if !s.waitBackoff("state", attempts) {
+			break
 		}
-		attempts += 1
-
 	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve Beacon State from the beacon node, closing requester routine. %s", err.Error())
 
 	}
 
-	log.Infof("state at slot %d downloaded in %f seconds (id=%s)", slot, time.Since(startTime).Seconds(), stateID)
-	resultState, err := local_spec.GetCustomState(*newState.Data, s.NewEpochData(slot))
+	resultState, err := local_spec.GetCustomState(*newState, s.NewEpochData(slot))
 	if err != nil {
 		return nil, fmt.Errorf("unable to open beacon state, closing requester routine. %s", err.Error())
 	}
+	return &resultState, nil
+}
 
-	var zeroRoot phase0.Root
-	if knownRoot != zeroRoot {
-		resultState.StateRoot = knownRoot
-	} else {
-		stateRoot, err := s.RequestStateRoot(slot)
-		if err != nil {
-			return nil, fmt.Errorf("unable to get state root at slot %d: %w", slot, err)
-		}
-		resultState.StateRoot = stateRoot
+// downloadBeaconState requests the full state, as SSZ unless disabled.
+func (s *APIClient) downloadBeaconState(stateID string) (*spec.VersionedBeaconState, error) {
+	if s.ssz {
+		return s.requestBeaconStateSSZ(stateID)
+	}
+	newState, err := failover(s, "state", func(cli *http.Service) (*api.Response[*spec.VersionedBeaconState], error) {
+		return cli.BeaconState(s.ctx, &api.BeaconStateOpts{
+			State: stateID,
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
+	return newState.Data, nil
+}
 
-	return &resultState, nil
+func (s *APIClient) requestStateFromResourcesWithRetries(slot phase0.Slot, stateID string) (*local_spec.AgnosticState, error) {
+	err := errors.New("first attempt")
+	var resultState *local_spec.AgnosticState
+
+	for attempts := 0; attempts < s.maxRetries; attempts++ {
+		resultState, err = s.requestStateFromResources(slot, stateID)
+		if err == nil || !isTransientError(err) {
+			break
+		}
+		log.Warnf("retrying state resources at slot %d: %s", slot, err)
+		if !s.waitBackoff("state_resources", attempts) {
+			break
+		}
+	}
+	return resultState, err
 }
 
 func (s *APIClient) RequestStateRoot(slot phase0.Slot) (phase0.Root, error) {
 
-	root, err := s.Api.BeaconStateRoot(s.ctx, &api.BeaconStateRootOpts{
-		State: fmt.Sprintf("%d", slot),
+	root, err := failover(s, "state_root", func(cli *http.Service) (*api.Response[*phase0.Root], error) {
+		return cli.BeaconStateRoot(s.ctx, &api.BeaconStateRootOpts{
+			State: fmt.Sprintf("%d", slot),
+		})
 	})
 	if err != nil {
 		return phase0.Root{}, fmt.Errorf("could not download the state root at %d: %w", slot, err)
@@ -92,15 +140,25 @@ func (s *APIClient) RequestStateRoot(slot phase0.Slot) (phase0.Root, error) {
 	return *root.Data, nil
 }
 
+// RequestFinality returns the finality checkpoints at the current head
+func (s *APIClient) RequestFinality() (*api.Response[*apiv1.Finality], error) {
+	finality, err := failover(s, "finality", func(cli *http.Service) (*api.Response[*apiv1.Finality], error) {
+		return cli.Finality(s.ctx, &api.FinalityOpts{
+			State: "head",
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not request finality checkpoints: %w", err)
+	}
+	return finality, nil
+}
+
 // Finalized Checkpoints happen at the beginning of an epoch
 // This method returns the finalized slot at the end of an epoch
 // Usually, it is the slot before the finalized one
 func (s *APIClient) GetFinalizedEndSlotStateRoot() (phase0.Slot, phase0.Root, error) {
 
-	currentFinalized, err := s.Api.Finality(s.ctx, &api.FinalityOpts{
-		State: "head",
-	})
-
+	currentFinalized, err := s.RequestFinality()
 	if err != nil {
 		return 0, phase0.Root{}, fmt.Errorf("could not determine the current finalized checkpoint: %w", err)
 	}
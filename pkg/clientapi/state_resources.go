@@ -0,0 +1,268 @@
+package clientapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/api"
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	// fork epoch keys in /eth/v1/config/spec, from newest to oldest
+	forkEpochKeys = []struct {
+		key     string
+		version spec.DataVersion
+	}{
+		{"FULU_FORK_EPOCH", spec.DataVersionFulu},
+		{"ELECTRA_FORK_EPOCH", spec.DataVersionElectra},
+		{"DENEB_FORK_EPOCH", spec.DataVersionDeneb},
+		{"CAPELLA_FORK_EPOCH", spec.DataVersionCapella},
+		{"BELLATRIX_FORK_EPOCH", spec.DataVersionBellatrix},
+		{"ALTAIR_FORK_EPOCH", spec.DataVersionAltair},
+	}
+)
+
+// WithNonArchivalStates builds the beacon states out of the per-resource endpoints
+// (validators, committees, sync committees, finality, pending queues...) instead of
+// downloading the full state, so that a pruned (non-archival) beacon node can be used.
+func WithNonArchivalStates(enabled bool) APIClientOption {
+	return func(s *APIClient) error {
+		s.nonArchivalStates = enabled
+		return nil
+	}
+}
+
+// requestStateFromResources is the non-archival counterpart of the full state download.
+func (s *APIClient) requestStateFromResources(slot phase0.Slot, stateID string) (*local_spec.AgnosticState, error) {
+	epoch := phase0.Epoch(slot / local_spec.SlotsPerEpoch)
+	res := local_spec.StateResources{
+		Slot: slot,
+	}
+
+	version, err := s.versionAtEpoch(epoch)
+	if err != nil {
+		return nil, err
+	}
+	res.Version = version
+
+	genesis, err := failover(s, "genesis", func(cli *http.Service) (time.Time, error) {
+		return cli.GenesisTime(s.ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get genesis time: %w", err)
+	}
+	res.GenesisTimestamp = uint64(genesis.Unix())
+
+	validators, err := failover(s, "validators", func(cli *http.Service) (*api.Response[map[phase0.ValidatorIndex]*apiv1.Validator], error) {
+		return cli.Validators(s.ctx, &api.ValidatorsOpts{
+			State: stateID,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get validators at slot %d: %w", slot, err)
+	}
+	res.Validators = validators.Data
+
+	res.BlockRoots, res.LatestBlockHeader, err = s.requestEpochBlockRoots(slot)
+	if err != nil {
+		return nil, err
+	}
+
+	syncCommittee, err := failover(s, "sync_committee", func(cli *http.Service) (*api.Response[*apiv1.SyncCommittee], error) {
+		return cli.SyncCommittee(s.ctx, &api.SyncCommitteeOpts{
+			State: stateID,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get sync committee at slot %d: %w", slot, err)
+	}
+	res.SyncCommittee = syncCommittee.Data.Validators
+
+	finality, err := failover(s, "finality", func(cli *http.Service) (*api.Response[*apiv1.Finality], error) {
+		return cli.Finality(s.ctx, &api.FinalityOpts{
+			State: stateID,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not get finality checkpoints at slot %d: %w", slot, err)
+	}
+	res.Finality = finality.Data
+
+	if epoch > 0 {
+		// participation of the previous epoch, as found in previous_epoch_participation
+		rewards, err := failover(s, "attestation_rewards", func(cli *http.Service) (*api.Response[*apiv1.AttestationRewards], error) {
+			return cli.AttestationRewards(s.ctx, &api.AttestationRewardsOpts{
+				Epoch: epoch - 1,
+			})
+		})
+		if err != nil {
+			// metrics depending on participation get marked as unavailable
+			log.Warnf("could not get attestation rewards for epoch %d: %s", epoch-1, err)
+		} else {
+			res.AttestationRewards = rewards.Data.TotalRewards
+		}
+	}
+
+	if version >= spec.DataVersionElectra {
+		if err := s.requestPendingQueues(stateID, &res); err != nil {
+			return nil, err
+		}
+	}
+
+	state, err := local_spec.NewStateFromResources(res, s.NewEpochData(slot))
+	if err != nil {
+		return nil, fmt.Errorf("could not build state at slot %d from resources: %w", slot, err)
+	}
+	return &state, nil
+}
+
+// versionAtEpoch derives the fork of an epoch from the fork schedule of the beacon node.
+func (s *APIClient) versionAtEpoch(epoch phase0.Epoch) (spec.DataVersion, error) {
+	specResp, err := failover(s, "spec", func(cli *http.Service) (*api.Response[map[string]any], error) {
+		return cli.Spec(s.ctx, &api.SpecOpts{})
+	})
+	if err != nil {
+		return spec.DataVersionUnknown, fmt.Errorf("could not get the beacon node spec: %w", err)
+	}
+
+	for _, fork := range forkEpochKeys {
+		value, ok := specResp.Data[fork.key]
+		if !ok {
+			continue // fork not scheduled in this node
+		}
+		var forkEpoch uint64
+		switch v := value.(type) {
+		case uint64:
+			forkEpoch = v
+		case phase0.Epoch:
+			forkEpoch = uint64(v)
+		default:
+			continue
+		}
+		if uint64(epoch) >= forkEpoch {
+			return fork.version, nil
+		}
+	}
+	return spec.DataVersionPhase0, nil
+}
+
+// requestEpochBlockRoots fills the block roots needed to process the epoch of the given slot:
+// from the last slot before the previous epoch up to the slot itself. Missed slots repeat the
+// root of the previous block, as in the state block_roots vector.
+func (s *APIClient) requestEpochBlockRoots(slot phase0.Slot) (map[phase0.Slot]phase0.Root, *phase0.BeaconBlockHeader, error) {
+	epoch := phase0.Epoch(slot / local_spec.SlotsPerEpoch)
+	firstSlot := phase0.Slot(0)
+	if epoch > 0 {
+		firstSlot = phase0.Slot(epoch-1) * local_spec.SlotsPerEpoch
+	}
+	if firstSlot > 0 {
+		firstSlot -= 1
+	}
+
+	roots := make(map[phase0.Slot]phase0.Root)
+	lastRoot := phase0.Root{}
+	lastProposed := phase0.Slot(0)
+	found := false
+
+	// look back for the block root that precedes the window in case its first slots were missed
+	for lookback := firstSlot; ; lookback-- {
+		root, ok, err := s.requestBlockRootAt(lookback)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			lastRoot = root
+			lastProposed = lookback
+			found = true
+			break
+		}
+		if lookback == 0 || firstSlot-lookback >= local_spec.SlotsPerEpoch {
+			break
+		}
+	}
+
+	for i := firstSlot; i <= slot; i++ {
+		root, ok, err := s.requestBlockRootAt(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			lastRoot = root
+			lastProposed = i
+			found = true
+		}
+		roots[i] = lastRoot
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("no blocks found around slot %d", slot)
+	}
+
+	header, err := failover(s, "header", func(cli *http.Service) (*api.Response[*apiv1.BeaconBlockHeader], error) {
+		return cli.BeaconBlockHeader(s.ctx, &api.BeaconBlockHeaderOpts{
+			Block: fmt.Sprintf("%#x", lastRoot),
+		})
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get block header at slot %d: %w", lastProposed, err)
+	}
+
+	return roots, header.Data.Header.Message, nil
+}
+
+func (s *APIClient) requestBlockRootAt(slot phase0.Slot) (phase0.Root, bool, error) {
+	root, err := failover(s, "block_root", func(cli *http.Service) (*api.Response[*phase0.Root], error) {
+		return cli.BeaconBlockRoot(s.ctx, &api.BeaconBlockRootOpts{
+			Block: fmt.Sprintf("%d", slot),
+		})
+	})
+	if err != nil {
+		if response404(err.Error()) {
+			return phase0.Root{}, false, nil // missed slot
+		}
+		return phase0.Root{}, false, fmt.Errorf("could not get block root at slot %d: %w", slot, err)
+	}
+	if root == nil || root.Data == nil {
+		return phase0.Root{}, false, nil
+	}
+	return *root.Data, true, nil
+}
+
+func (s *APIClient) requestPendingQueues(stateID string, res *local_spec.StateResources) error {
+	deposits, err := failover(s, "pending_deposits", func(cli *http.Service) (*api.Response[[]*electra.PendingDeposit], error) {
+		return cli.PendingDeposits(s.ctx, &api.PendingDepositsOpts{
+			State: stateID,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("could not get pending deposits at %s: %w", stateID, err)
+	}
+	res.PendingDeposits = deposits.Data
+
+	withdrawals, err := failover(s, "pending_partial_withdrawals", func(cli *http.Service) (*api.Response[[]*electra.PendingPartialWithdrawal], error) {
+		return cli.PendingPartialWithdrawals(s.ctx, &api.PendingPartialWithdrawalsOpts{
+			State: stateID,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("could not get pending partial withdrawals at %s: %w", stateID, err)
+	}
+	res.PendingPartialWithdrawals = withdrawals.Data
+
+	consolidations, err := failover(s, "pending_consolidations", func(cli *http.Service) (*api.Response[[]*electra.PendingConsolidation], error) {
+		return cli.PendingConsolidations(s.ctx, &api.PendingConsolidationsOpts{
+			State: stateID,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("could not get pending consolidations at %s: %w", stateID, err)
+	}
+	res.PendingConsolidations = consolidations.Data
+
+	return nil
+}
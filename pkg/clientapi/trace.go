@@ -0,0 +1,70 @@
+package clientapi
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+)
+
+var maxParallelTraces = 2
+
+// WithTraceConcurrency bounds the number of blocks traced at the same time.
+func WithTraceConcurrency(traces int) APIClientOption {
+	return func(s *APIClient) error {
+		if traces <= 0 {
+			return fmt.Errorf("invalid trace concurrency %d, using %d", traces, maxParallelTraces)
+		}
+		s.traceBook = utils.NewRoutineBook(traces, "api-cli-traces")
+		return nil
+	}
+}
+
+// TraceBlock requests the call tree of every transaction of the block with the callTracer.
+// The execution node must serve the debug namespace.
+func (client *APIClient) TraceBlock(block spec.AgnosticBlock) ([]spec.TxTrace, error) {
+	if client.ELApi == nil {
+		return nil, errors.New("execution endpoint not configured")
+	}
+
+	maxAttempts := client.maxRetries
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	blockHash := common.BytesToHash(block.ExecutionPayload.BlockHash[:])
+	routineKey := fmt.Sprintf("trace=%s:%d", blockHash.Hex(), block.ExecutionPayload.BlockNumber)
+	client.traceBook.Acquire(routineKey)
+	defer client.traceBook.FreePage(routineKey)
+
+	var (
+		traces []spec.TxTrace
+		err    error
+	)
+	tracerConfig := map[string]interface{}{"tracer": "callTracer"}
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = client.ELApi.Client().CallContext(client.ctx, &traces, "debug_traceBlockByHash", blockHash, tracerConfig)
+		if err == nil {
+			return traces, nil
+		}
+
+		if attempt < maxAttempts {
+			waitTime := utils.RoutineFlushTimeout * time.Duration(attempt)
+			if waitTime <= 0 {
+				waitTime = utils.RoutineFlushTimeout
+			}
+			log.Warnf("retrying block trace request: block=%d attempt=%d err=%s", block.ExecutionPayload.BlockNumber, attempt, err)
+			select {
+			case <-time.After(waitTime):
+			case <-client.ctx.Done():
+				return nil, fmt.Errorf("context cancelled while waiting for block %d trace: %w", block.ExecutionPayload.BlockNumber, client.ctx.Err())
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unable to trace block %d after %d attempts: %w", block.ExecutionPayload.BlockNumber, maxAttempts, err)
+}
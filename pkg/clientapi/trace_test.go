@@ -0,0 +1,62 @@
+package clientapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/migalabs/goteth/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceBlockRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(body, &request))
+		assert.Equal(t, "debug_traceBlockByHash", request.Method)
+		assert.JSONEq(t, `{"tracer": "callTracer"}`, string(request.Params[1]))
+
+		w.Header().Set("Content-Type", "application/json")
+		if requests.Add(1) == 1 {
+			w.Write([]byte(`{"jsonrpc": "2.0", "id": ` + string(request.ID) + `, "error": {"code": -32000, "message": "busy"}}`))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": ` + string(request.ID) + `, "result": [{"txHash": "0x0100000000000000000000000000000000000000000000000000000000000000", "result": {"type": "CALL", "from": "0x00000000000000000000000000000000000000aa", "gas": "0x0", "gasUsed": "0x0"}}]}`))
+	}))
+	defer server.Close()
+
+	elClient, err := ethclient.Dial(server.URL)
+	require.NoError(t, err)
+	client := &APIClient{
+		ctx:        context.Background(),
+		ELApi:      elClient,
+		maxRetries: 2,
+		traceBook:  utils.NewRoutineBook(1, "test-traces"),
+	}
+
+	block := spec.AgnosticBlock{ExecutionPayload: spec.AgnosticExecutionPayload{BlockHash: phase0.Hash32{7}, BlockNumber: 10}}
+	traces, err := client.TraceBlock(block)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+	require.Len(t, traces, 1)
+	assert.Equal(t, "CALL", traces[0].Result.Type)
+
+	client.maxRetries = 1
+	requests.Store(0)
+	_, err = client.TraceBlock(block)
+	assert.Error(t, err)
+}
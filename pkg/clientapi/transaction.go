@@ -16,6 +16,16 @@ import (
 )
 
 func (client *APIClient) GetBlockReceipts(block spec.AgnosticBlock) ([]*types.Receipt, error) {
+	return client.getBlockReceipts(block, true)
+}
+
+// GetOrphanedBlockReceipts requests the receipts of a block no longer canonical, by hash only,
+// as the block number now points to the canonical block.
+func (client *APIClient) GetOrphanedBlockReceipts(block spec.AgnosticBlock) ([]*types.Receipt, error) {
+	return client.getBlockReceipts(block, false)
+}
+
+func (client *APIClient) getBlockReceipts(block spec.AgnosticBlock, byNumber bool) ([]*types.Receipt, error) {
 	if client.ELApi == nil {
 		return nil, errors.New("execution endpoint not configured")
 	}
@@ -36,7 +46,7 @@ func (client *APIClient) GetBlockReceipts(block spec.AgnosticBlock) ([]*types.Re
 	)
 
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		receipts, err = client.requestBlockReceipts(blockHash, blockNumber)
+		receipts, err = client.requestBlockReceipts(blockHash, blockNumber, byNumber)
 		if err == nil {
 			return receipts, nil
 		}
@@ -127,7 +137,7 @@ func classifyReceiptError(err error) string {
 	}
 }
 
-func (client *APIClient) requestBlockReceipts(blockHash common.Hash, blockNumber rpc.BlockNumber) ([]*types.Receipt, error) {
+func (client *APIClient) requestBlockReceipts(blockHash common.Hash, blockNumber rpc.BlockNumber, byNumber bool) ([]*types.Receipt, error) {
 	var selector rpc.BlockNumberOrHash
 	if blockHash != (common.Hash{}) {
 		selector = rpc.BlockNumberOrHashWithHash(blockHash, false)
@@ -136,7 +146,7 @@ func (client *APIClient) requestBlockReceipts(blockHash common.Hash, blockNumber
 	}
 
 	receipts, err := client.ELApi.BlockReceipts(client.ctx, selector)
-	if err != nil && blockHash != (common.Hash{}) {
+	if err != nil && blockHash != (common.Hash{}) && byNumber {
 		log.Debugf("receipt request by hash failed (%s), retrying via block number %d", blockHash.Hex(), blockNumber)
 		return client.ELApi.BlockReceipts(client.ctx, rpc.BlockNumberOrHashWithNumber(blockNumber))
 	}
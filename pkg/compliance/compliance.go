@@ -0,0 +1,135 @@
+package compliance
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	moduleName = "compliance"
+	log        = logrus.WithField(
+		"module", moduleName)
+)
+
+// AddressList is a set of execution addresses a block should not include
+// transactions for.
+type AddressList struct {
+	addresses map[common.Address]struct{}
+}
+
+// NewAddressList builds a list out of the given addresses.
+func NewAddressList(addresses []common.Address) *AddressList {
+	l := &AddressList{addresses: make(map[common.Address]struct{}, len(addresses))}
+	for _, address := range addresses {
+		l.addresses[address] = struct{}{}
+	}
+	return l
+}
+
+// ReadAddressList reads a file with one address per line, lines starting
+// with # are ignored. An empty path disables the classification.
+func ReadAddressList(path string) (*AddressList, error) {
+	if path == "" {
+		return nil, nil
+	}
+	log.Infof("reading compliance address list from: %s", path)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	addresses := make([]common.Address, 0)
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !common.IsHexAddress(line) {
+			return nil, fmt.Errorf("invalid address at %s:%d: %s", path, lineNumber, line)
+		}
+		addresses = append(addresses, common.HexToAddress(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	log.Infof("read %d addresses", len(addresses))
+	return NewAddressList(addresses), nil
+}
+
+func (l *AddressList) Len() int {
+	return len(l.addresses)
+}
+
+func (l *AddressList) contains(address common.Address) bool {
+	_, ok := l.addresses[address]
+	return ok
+}
+
+// Touches tells whether a transaction sends from, to or creates a listed address,
+// or emits a log from or about one (indexed address topics).
+func (l *AddressList) Touches(tx spec.AgnosticTransaction) bool {
+	if l.contains(tx.From) {
+		return true
+	}
+	if tx.To != nil && l.contains(*tx.To) {
+		return true
+	}
+	if tx.ContractAddress != (common.Address{}) && l.contains(tx.ContractAddress) {
+		return true
+	}
+	if tx.Receipt == nil {
+		return false
+	}
+	for _, txLog := range tx.Receipt.Logs {
+		if l.contains(txLog.Address) {
+			return true
+		}
+		for _, topic := range txLog.Topics {
+			if isAddressTopic(topic) && l.contains(common.BytesToAddress(topic[12:])) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// an indexed address is left padded with 12 zero bytes
+func isAddressTopic(topic common.Hash) bool {
+	for _, b := range topic[:12] {
+		if b != 0 {
+			return false
+		}
+	}
+	return topic != (common.Hash{})
+}
+
+// Result is the classification of a block: it is compliant when none of its
+// transactions touches a listed address.
+type Result struct {
+	Compliant    bool
+	FlaggedTxs   []phase0.Hash32
+	Transactions int
+}
+
+// Classify checks every transaction of a block against the list.
+func (l *AddressList) Classify(txs []spec.AgnosticTransaction) Result {
+	result := Result{Compliant: true, Transactions: len(txs)}
+	for _, tx := range txs {
+		if l.Touches(tx) {
+			result.Compliant = false
+			result.FlaggedTxs = append(result.FlaggedTxs, tx.Hash)
+		}
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	listed   = common.HexToAddress("0x8589427373D6D84E98730D7795D8f6f8731FDA16")
+	unlisted = common.HexToAddress("0x0000000000000000000000000000000000000042")
+)
+
+func TestClassify(t *testing.T) {
+	list := NewAddressList([]common.Address{listed})
+
+	transfer := types.Log{
+		Address: unlisted,
+		Topics: []common.Hash{
+			common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"),
+			common.BytesToHash(unlisted.Bytes()),
+			common.BytesToHash(listed.Bytes()),
+		},
+	}
+	txs := []spec.AgnosticTransaction{
+		{Hash: phase0.Hash32{1}, From: unlisted, To: &unlisted},
+		{Hash: phase0.Hash32{2}, From: listed},
+		{Hash: phase0.Hash32{3}, From: unlisted, To: &listed},
+		{Hash: phase0.Hash32{4}, From: unlisted, Receipt: &types.Receipt{Logs: []*types.Log{&transfer}}},
+		{Hash: phase0.Hash32{5}, From: unlisted, ContractAddress: listed},
+	}
+
+	result := list.Classify(txs)
+	assert.False(t, result.Compliant)
+	assert.Equal(t, 5, result.Transactions)
+	assert.Equal(t, []phase0.Hash32{{2}, {3}, {4}, {5}}, result.FlaggedTxs)
+
+	result = list.Classify(txs[:1])
+	assert.True(t, result.Compliant)
+	assert.Empty(t, result.FlaggedTxs)
+}
+
+func TestReadAddressList(t *testing.T) {
+	list, err := ReadAddressList("")
+	require.NoError(t, err)
+	assert.Nil(t, list)
+
+	path := filepath.Join(t.TempDir(), "addresses.txt")
+	content := "# sanctioned\n0x8589427373d6d84e98730d7795d8f6f8731fda16\n\n  0x0000000000000000000000000000000000000001  \n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	list, err = ReadAddressList(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Len())
+	assert.True(t, list.Touches(spec.AgnosticTransaction{From: listed}))
+
+	require.NoError(t, os.WriteFile(path, []byte("not an address\n"), 0o644))
+	_, err = ReadAddressList(path)
+	assert.Error(t, err)
+}
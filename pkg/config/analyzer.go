@@ -10,7 +10,13 @@ type AnalyzerConfig struct {
 	InitSlot                 phase0.Slot `json:"init-slot"`
 	FinalSlot                phase0.Slot `json:"final-slot"`
 	RewardsAggregationEpochs int         `json:"rewards-aggregation-epochs"`
+	RewardsAggregationLevels string      `json:"rewards-aggregation-levels"`
+	BackfillShardEpochs      int         `json:"backfill-shard-epochs"`
+	BackfillWorkerID         string      `json:"backfill-worker-id"`
 	BnEndpoint               string      `json:"bn-endpoint"`
+	BnConsistencyChecks      bool        `json:"bn-consistency-checks"`
+	BnNonArchival            bool        `json:"bn-non-archival"`
+	BnSSZ                    bool        `json:"bn-ssz"`
 	BnApiKey                 string      `json:"bn-api-key"`
 	CfAccessClientID         string      `json:"cf-access-client-id"`
 	CfAccessClientSecret     string      `json:"cf-access-client-secret"`
@@ -23,6 +29,14 @@ type AnalyzerConfig struct {
 	PrometheusPort           int         `json:"prometheus-port"`
 	MaxRequestRetries        int         `json:"max-request-retries"`
 	BeaconContractAddress    string      `json:"beacon-contract-address"`
+	BuildersFile             string      `json:"builders-file"`
+	ComplianceList           string      `json:"compliance-list"`
+	ABIDir                   string      `json:"abi-dir"`
+	TraceConcurrency         int         `json:"trace-concurrency"`
+	RollupsFile              string      `json:"rollups-file"`
+	VerifyKZG                bool        `json:"verify-kzg"`
+	BlobStore                string      `json:"blob-store"`
+	GraffitiRules            string      `json:"graffiti-rules"`
 }
 
 // TODO: read from config-file
@@ -33,7 +47,13 @@ func NewAnalyzerConfig() *AnalyzerConfig {
 		InitSlot:                 phase0.Slot(DefaultInitSlot),
 		FinalSlot:                phase0.Slot(DefaultFinalSlot),
 		RewardsAggregationEpochs: DefaultRewardsAggregationEpochs,
+		RewardsAggregationLevels: DefaultRewardsAggregationLevels,
+		BackfillShardEpochs:      DefaultBackfillShardEpochs,
+		BackfillWorkerID:         DefaultBackfillWorkerID,
 		BnEndpoint:               DefaultBnEndpoint,
+		BnConsistencyChecks:      DefaultBnConsistencyChecks,
+		BnNonArchival:            DefaultBnNonArchival,
+		BnSSZ:                    DefaultBnSSZ,
 		BnApiKey:                 DefaultBnApiKey,
 		CfAccessClientID:         DefaultCfAccessClientID,
 		CfAccessClientSecret:     DefaultCfAccessClientSecret,
@@ -46,6 +66,14 @@ func NewAnalyzerConfig() *AnalyzerConfig {
 		PrometheusPort:           DefaultPrometheusPort,
 		MaxRequestRetries:        DefaultMaxRequestRetries,
 		BeaconContractAddress:    DefaultBeaconContractAddress,
+		BuildersFile:             DefaultBuildersFile,
+		ComplianceList:           DefaultComplianceList,
+		ABIDir:                   DefaultABIDir,
+		TraceConcurrency:         DefaultTraceConcurrency,
+		RollupsFile:              DefaultRollupsFile,
+		VerifyKZG:                DefaultVerifyKZG,
+		BlobStore:                DefaultBlobStore,
+		GraffitiRules:            DefaultGraffitiRules,
 	}
 }
 
@@ -67,10 +95,34 @@ func (c *AnalyzerConfig) Apply(ctx *cli.Context) {
 	if ctx.IsSet("rewards-aggregation-epochs") {
 		c.RewardsAggregationEpochs = ctx.Int("rewards-aggregation-epochs")
 	}
+	// additional rewards aggregation levels
+	if ctx.IsSet("rewards-aggregation-levels") {
+		c.RewardsAggregationLevels = ctx.String("rewards-aggregation-levels")
+	}
+	// epochs per backfill shard
+	if ctx.IsSet("backfill-shard-epochs") {
+		c.BackfillShardEpochs = ctx.Int("backfill-shard-epochs")
+	}
+	// backfill worker id
+	if ctx.IsSet("backfill-worker-id") {
+		c.BackfillWorkerID = ctx.String("backfill-worker-id")
+	}
 	// cl url
 	if ctx.IsSet("bn-endpoint") {
 		c.BnEndpoint = ctx.String("bn-endpoint")
 	}
+	// cross check roots between beacon nodes
+	if ctx.IsSet("bn-consistency-checks") {
+		c.BnConsistencyChecks = ctx.Bool("bn-consistency-checks")
+	}
+	// build states from per-resource endpoints
+	if ctx.IsSet("bn-non-archival") {
+		c.BnNonArchival = ctx.Bool("bn-non-archival")
+	}
+	// ssz encoded states and blocks
+	if ctx.IsSet("bn-ssz") {
+		c.BnSSZ = ctx.Bool("bn-ssz")
+	}
 	// bn api key
 	if ctx.IsSet("bn-api-key") {
 		c.BnApiKey = ctx.String("bn-api-key")
@@ -122,4 +174,36 @@ func (c *AnalyzerConfig) Apply(ctx *cli.Context) {
 	if ctx.IsSet("beacon-contract-address") {
 		c.BeaconContractAddress = ctx.String("beacon-contract-address")
 	}
+	// builders identification file
+	if ctx.IsSet("builders-file") {
+		c.BuildersFile = ctx.String("builders-file")
+	}
+	// addresses blocks are checked against
+	if ctx.IsSet("compliance-list") {
+		c.ComplianceList = ctx.String("compliance-list")
+	}
+	// extra ABIs to decode logs with
+	if ctx.IsSet("abi-dir") {
+		c.ABIDir = ctx.String("abi-dir")
+	}
+	// blocks traced in parallel
+	if ctx.IsSet("trace-concurrency") {
+		c.TraceConcurrency = ctx.Int("trace-concurrency")
+	}
+	// rollup addresses blob transactions are attributed with
+	if ctx.IsSet("rollups-file") {
+		c.RollupsFile = ctx.String("rollups-file")
+	}
+	// check the downloaded blobs against their commitments
+	if ctx.IsSet("verify-kzg") {
+		c.VerifyKZG = ctx.Bool("verify-kzg")
+	}
+	// where the blob contents are written to
+	if ctx.IsSet("blob-store") {
+		c.BlobStore = ctx.String("blob-store")
+	}
+	// rules the clients are parsed from the graffiti with
+	if ctx.IsSet("graffiti-rules") {
+		c.GraffitiRules = ctx.String("graffiti-rules")
+	}
 }
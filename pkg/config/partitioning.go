@@ -0,0 +1,62 @@
+package config
+
+import (
+	cli "github.com/urfave/cli/v2"
+)
+
+type PartitioningConfig struct {
+	LogLevel string `json:"log-level"`
+	DBUrl    string `json:"db-url"`
+	Tables   string `json:"tables"`
+	TTL      string `json:"ttl"`
+	DropOld  bool   `json:"drop-old"`
+
+	BnEndpoint        string `json:"bn-endpoint"`
+	MaxRequestRetries int    `json:"max-request-retries"`
+}
+
+func NewPartitioningConfig() *PartitioningConfig {
+	// Return Default values for the partitioning configuration
+	return &PartitioningConfig{
+		LogLevel: DefaultLogLevel,
+		DBUrl:    DefaultDBUrl,
+		Tables:   DefaultPartitionTables,
+		TTL:      DefaultPartitionTTL,
+		DropOld:  DefaultPartitionDropOld,
+
+		BnEndpoint:        DefaultBnEndpoint,
+		MaxRequestRetries: DefaultMaxRequestRetries,
+	}
+}
+
+func (c *PartitioningConfig) Apply(ctx *cli.Context) {
+	// apply to the existing Default configuration the set flags
+	// log level
+	if ctx.IsSet("log-level") {
+		c.LogLevel = ctx.String("log-level")
+	}
+	// db url
+	if ctx.IsSet("db-url") {
+		c.DBUrl = ctx.String("db-url")
+	}
+	// tables to partition
+	if ctx.IsSet("tables") {
+		c.Tables = ctx.String("tables")
+	}
+	// per table ttl
+	if ctx.IsSet("ttl") {
+		c.TTL = ctx.String("ttl")
+	}
+	// drop the unpartitioned rows once swapped
+	if ctx.IsSet("drop-old") {
+		c.DropOld = ctx.Bool("drop-old")
+	}
+	// cl url
+	if ctx.IsSet("bn-endpoint") {
+		c.BnEndpoint = ctx.String("bn-endpoint")
+	}
+	// max request retries
+	if ctx.IsSet("max-request-retries") {
+		c.MaxRequestRetries = ctx.Int("max-request-retries")
+	}
+}
@@ -8,6 +8,8 @@ type ValidatorWindowConfig struct {
 	LogLevel          string `json:"log-level"`
 	DBUrl             string `json:"db-url"`
 	NumEpochs         int    `json:"num-epochs"`
+	Retention         string `json:"retention"`
+	DryRun            bool   `json:"dry-run"`
 	BnEndpoint        string `json:"bn-endpoint"`
 	BnApiKey          string `json:"bn-api-key"`
 	MaxRequestRetries int    `json:"max-request-retries"`
@@ -20,6 +22,8 @@ func NewValidatorWindowConfig() *ValidatorWindowConfig {
 		LogLevel:          DefaultLogLevel,
 		DBUrl:             DefaultDBUrl,
 		NumEpochs:         DefaultValidatorWindowEpochs,
+		Retention:         DefaultRetention,
+		DryRun:            DefaultRetentionDryRun,
 		BnEndpoint:        DefaultBnEndpoint,
 		MaxRequestRetries: DefaultMaxRequestRetries,
 		BnApiKey:          DefaultBnApiKey,
@@ -40,6 +44,14 @@ func (c *ValidatorWindowConfig) Apply(ctx *cli.Context) {
 	if ctx.IsSet("num-epochs") {
 		c.NumEpochs = ctx.Int("num-epochs")
 	}
+	// per table retention
+	if ctx.IsSet("retention") {
+		c.Retention = ctx.String("retention")
+	}
+	// only report what would be removed
+	if ctx.IsSet("dry-run") {
+		c.DryRun = ctx.Bool("dry-run")
+	}
 	// cl url
 	if ctx.IsSet("bn-endpoint") {
 		c.BnEndpoint = ctx.String("bn-endpoint")
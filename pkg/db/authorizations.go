@@ -0,0 +1,87 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	authorizationsTable       = "t_transaction_authorizations"
+	insertAuthorizationsQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_block_root,
+		f_tx_hash,
+		f_tx_idx,
+		f_index,
+		f_chain_id,
+		f_address,
+		f_nonce,
+		f_authority,
+		f_valid)
+		VALUES`
+)
+
+func authorizationsInput(authorizations []spec.AgnosticAuthorization) proto.Input {
+	// one object per column
+	var (
+		f_slot       proto.ColUInt64
+		f_block_root proto.ColStr
+		f_tx_hash    proto.ColStr
+		f_tx_idx     proto.ColUInt64
+		f_index      proto.ColUInt64
+		f_chain_id   proto.ColStr
+		f_address    proto.ColStr
+		f_nonce      proto.ColUInt64
+		f_authority  proto.ColStr
+		f_valid      proto.ColBool
+	)
+
+	for _, authorization := range authorizations {
+		f_slot.Append(uint64(authorization.Slot))
+		f_block_root.Append(authorization.BlockRoot.String())
+		f_tx_hash.Append(authorization.TxHash.String())
+		f_tx_idx.Append(authorization.TxIdx)
+		f_index.Append(authorization.Index)
+		chainId := "0"
+		if authorization.ChainId != nil {
+			chainId = authorization.ChainId.String()
+		}
+		f_chain_id.Append(chainId)
+		f_address.Append(authorization.Address.String())
+		f_nonce.Append(authorization.Nonce)
+		f_authority.Append(authorization.AuthorityString())
+		f_valid.Append(authorization.Valid)
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_tx_hash", Data: f_tx_hash},
+		{Name: "f_tx_idx", Data: f_tx_idx},
+		{Name: "f_index", Data: f_index},
+		{Name: "f_chain_id", Data: f_chain_id},
+		{Name: "f_address", Data: f_address},
+		{Name: "f_nonce", Data: f_nonce},
+		{Name: "f_authority", Data: f_authority},
+		{Name: "f_valid", Data: f_valid},
+	}
+}
+
+func (p *DBService) PersistAuthorizations(data []spec.AgnosticAuthorization) error {
+	persistObj := PersistableObject[spec.AgnosticAuthorization]{
+		input: authorizationsInput,
+		table: authorizationsTable,
+		query: insertAuthorizationsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting transaction authorizations: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	backfillLeasesTable      = "t_backfill_leases"
+	insertBackfillLeaseQuery = `
+	INSERT INTO %s (
+		f_start_epoch,
+		f_end_epoch,
+		f_owner,
+		f_status,
+		f_expires_at,
+		f_version)
+		VALUES`
+
+	selectBackfillLeasesQuery = `
+		SELECT
+			f_start_epoch,
+			f_end_epoch,
+			f_owner,
+			f_status,
+			f_expires_at,
+			f_version
+		FROM %s FINAL
+		WHERE f_start_epoch >= %d AND f_start_epoch < %d
+		ORDER BY f_start_epoch`
+)
+
+const (
+	BackfillLeaseRunning = "running"
+	BackfillLeaseDone    = "done"
+)
+
+// BackfillLease is the claim of one goteth instance over a range of epochs to backfill.
+// Rows are never updated: every claim, renewal or completion inserts a new version.
+type BackfillLease struct {
+	StartEpoch phase0.Epoch
+	EndEpoch   phase0.Epoch // not included
+	Owner      string
+	Status     string
+	ExpiresAt  time.Time
+	Version    uint64
+}
+
+// Claimable returns whether another instance may take over the range.
+func (l BackfillLease) Claimable(now time.Time) bool {
+	if l.Status == BackfillLeaseDone {
+		return false
+	}
+	return now.After(l.ExpiresAt)
+}
+
+func backfillLeasesInput(leases []BackfillLease) proto.Input {
+	// one object per column
+	var (
+		f_start_epoch proto.ColUInt64
+		f_end_epoch   proto.ColUInt64
+		f_owner       proto.ColStr
+		f_status      proto.ColStr
+		f_expires_at  proto.ColUInt64
+		f_version     proto.ColUInt64
+	)
+
+	for _, lease := range leases {
+		f_start_epoch.Append(uint64(lease.StartEpoch))
+		f_end_epoch.Append(uint64(lease.EndEpoch))
+		f_owner.Append(lease.Owner)
+		f_status.Append(lease.Status)
+		f_expires_at.Append(uint64(lease.ExpiresAt.Unix()))
+		f_version.Append(lease.Version)
+	}
+
+	return proto.Input{
+		{Name: "f_start_epoch", Data: f_start_epoch},
+		{Name: "f_end_epoch", Data: f_end_epoch},
+		{Name: "f_owner", Data: f_owner},
+		{Name: "f_status", Data: f_status},
+		{Name: "f_expires_at", Data: f_expires_at},
+		{Name: "f_version", Data: f_version},
+	}
+}
+
+func (p *DBService) PersistBackfillLease(lease BackfillLease) error {
+	persistObj := PersistableObject[BackfillLease]{
+		input: backfillLeasesInput,
+		table: backfillLeasesTable,
+		query: insertBackfillLeaseQuery,
+	}
+	persistObj.Append(lease)
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting backfill lease: %s", err.Error())
+	}
+	return err
+}
+
+// RetrieveBackfillLeases returns the latest version of the leases starting in [from, to).
+func (p *DBService) RetrieveBackfillLeases(from phase0.Epoch, to phase0.Epoch) (map[phase0.Epoch]BackfillLease, error) {
+	var dest []struct {
+		F_start_epoch uint64 `ch:"f_start_epoch"`
+		F_end_epoch   uint64 `ch:"f_end_epoch"`
+		F_owner       string `ch:"f_owner"`
+		F_status      string `ch:"f_status"`
+		F_expires_at  uint64 `ch:"f_expires_at"`
+		F_version     uint64 `ch:"f_version"`
+	}
+
+	err := p.highSelect(
+		fmt.Sprintf(selectBackfillLeasesQuery, backfillLeasesTable, from, to),
+		&dest)
+	if err != nil {
+		return nil, err
+	}
+
+	leases := make(map[phase0.Epoch]BackfillLease, len(dest))
+	for _, item := range dest {
+		leases[phase0.Epoch(item.F_start_epoch)] = BackfillLease{
+			StartEpoch: phase0.Epoch(item.F_start_epoch),
+			EndEpoch:   phase0.Epoch(item.F_end_epoch),
+			Owner:      item.F_owner,
+			Status:     item.F_status,
+			ExpiresAt:  time.Unix(int64(item.F_expires_at), 0),
+			Version:    item.F_version,
+		}
+	}
+	return leases, nil
+}
@@ -0,0 +1,18 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackfillLeaseClaimable(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	running := BackfillLease{Status: BackfillLeaseRunning, ExpiresAt: now.Add(time.Minute)}
+	assert.False(t, running.Claimable(now))
+	assert.True(t, running.Claimable(now.Add(2*time.Minute)), "expired")
+
+	done := BackfillLease{Status: BackfillLeaseDone, ExpiresAt: now.Add(-time.Hour)}
+	assert.False(t, done.Claimable(now), "done shards are never taken over")
+}
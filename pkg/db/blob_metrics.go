@@ -15,26 +15,26 @@ var (
 		f_index,
 		f_kzg_commitment,
 		f_kzg_proof,
-		f_ending_0s)
+		f_ending_0s,
+		f_block_root,
+		f_kzg_verification,
+		f_storage_key)
 		VALUES`
-
-	deleteBlobsQuery = `
-		DELETE FROM %s
-		WHERE f_slot = $1;
-
-`
 )
 
 func blobSidecarsInput(blobSidecars []spec.AgnosticBlobSidecar) proto.Input {
 	// one object per column
 	var (
-		f_blob_hash      proto.ColStr
-		f_tx_hash        proto.ColStr
-		f_slot           proto.ColUInt64
-		f_index          proto.ColUInt8
-		f_kzg_commitment proto.ColStr
-		f_kzg_proof      proto.ColStr
-		f_ending_0s      proto.ColUInt64
+		f_blob_hash        proto.ColStr
+		f_tx_hash          proto.ColStr
+		f_slot             proto.ColUInt64
+		f_index            proto.ColUInt8
+		f_kzg_commitment   proto.ColStr
+		f_kzg_proof        proto.ColStr
+		f_ending_0s        proto.ColUInt64
+		f_block_root       proto.ColStr
+		f_kzg_verification proto.ColStr
+		f_storage_key      proto.ColStr
 	)
 
 	for _, blobSidecar := range blobSidecars {
@@ -46,6 +46,9 @@ func blobSidecarsInput(blobSidecars []spec.AgnosticBlobSidecar) proto.Input {
 		f_kzg_commitment.Append(blobSidecar.KZGCommitment.String())
 		f_kzg_proof.Append(blobSidecar.KZGProof.String())
 		f_ending_0s.Append(uint64(blobSidecar.BlobEnding0s))
+		f_block_root.Append(blobSidecar.BlockRoot.String())
+		f_kzg_verification.Append(blobSidecar.KZGVerification)
+		f_storage_key.Append(blobSidecar.StorageKey)
 
 	}
 
@@ -58,6 +61,9 @@ func blobSidecarsInput(blobSidecars []spec.AgnosticBlobSidecar) proto.Input {
 		{Name: "f_kzg_commitment", Data: f_kzg_commitment},
 		{Name: "f_kzg_proof", Data: f_kzg_proof},
 		{Name: "f_ending_0s", Data: f_ending_0s},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_kzg_verification", Data: f_kzg_verification},
+		{Name: "f_storage_key", Data: f_storage_key},
 	}
 }
 
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"encoding/hex"
 	"fmt"
 	"strings"
 
@@ -40,21 +41,34 @@ var (
 		f_snappy_size_bytes,
 		f_compression_time_ms,
 		f_decompression_time_ms,
-		f_payload_size_bytes)
+		f_payload_size_bytes,
+		f_block_root,
+		f_version,
+		f_finalized,
+		f_el_extra_data,
+		f_el_extra_data_text,
+		f_el_prev_randao,
+		f_el_parent_hash,
+		f_el_logs_bloom,
+		f_el_blob_gas_used,
+		f_el_excess_blob_gas,
+		f_el_blob_base_fee,
+		f_blobs,
+		f_blob_target,
+		f_blob_max,
+		f_cl_client,
+		f_cl_client_version,
+		f_el_client,
+		f_el_client_version)
 		VALUES`
 	selectLastSlotQuery = `
 		SELECT f_slot
 		FROM %s
 		ORDER BY f_slot DESC
 		LIMIT 1`
-
-	deleteBlockQuery = `
-		DELETE FROM %s
-		WHERE f_slot = $1;
-`
 )
 
-func blocksInput(blocks []spec.AgnosticBlock) proto.Input {
+func (p *DBService) blocksInput(blocks []spec.AgnosticBlock) proto.Input {
 	// one object per column
 	var (
 		f_timestamp                  proto.ColUInt64
@@ -84,15 +98,32 @@ func blocksInput(blocks []spec.AgnosticBlock) proto.Input {
 		f_snappy_size_bytes          proto.ColFloat32
 		f_compression_time_ms        proto.ColFloat32
 		f_decompression_time_ms      proto.ColFloat32
+		f_block_root                 proto.ColStr
+		f_version                    proto.ColUInt64
+		f_finalized                  proto.ColBool
+		f_el_extra_data              proto.ColStr
+		f_el_extra_data_text         proto.ColStr
+		f_el_prev_randao             proto.ColStr
+		f_el_parent_hash             proto.ColStr
+		f_el_logs_bloom              proto.ColStr
+		f_el_blob_gas_used           proto.ColUInt64
+		f_el_excess_blob_gas         proto.ColUInt64
+		f_el_blob_base_fee           proto.ColUInt256
+		f_blobs                      proto.ColUInt64
+		f_blob_target                proto.ColUInt64
+		f_blob_max                   proto.ColUInt64
+		f_cl_client                  proto.ColStr
+		f_cl_client_version          proto.ColStr
+		f_el_client                  proto.ColStr
+		f_el_client_version          proto.ColStr
 	)
+	version := nextRowVersion()
 	for _, block := range blocks {
 		f_timestamp.Append(uint64(block.ExecutionPayload.Timestamp))
 		f_epoch.Append(uint64(block.Slot / spec.SlotsPerEpoch))
 		f_slot.Append(uint64(block.Slot))
 
-		graffiti := strings.ToValidUTF8(string(block.Graffiti[:]), "?")
-		graffiti = strings.ReplaceAll(graffiti, "\u0000", "")
-		f_graffiti.Append(graffiti)
+		f_graffiti.Append(printableString(block.Graffiti[:]))
 
 		f_proposer_index.Append(uint64(block.ProposerIndex))
 		f_proposed.Append(block.Proposed)
@@ -138,6 +169,29 @@ func blocksInput(blocks []spec.AgnosticBlock) proto.Input {
 		f_compression_time_ms.Append(float32(utils.DurationToFloat64Millis(block.CompressionTime)))
 		f_decompression_time_ms.Append(float32(utils.DurationToFloat64Millis(block.DecompressionTime)))
 
+		f_block_root.Append(block.Root.String())
+		f_version.Append(version)
+		f_finalized.Append(p.isSlotFinalized(block.Slot))
+
+		f_el_extra_data.Append("0x" + hex.EncodeToString(block.ExecutionPayload.ExtraData))
+		f_el_extra_data_text.Append(printableString(block.ExecutionPayload.ExtraData))
+		f_el_prev_randao.Append("0x" + hex.EncodeToString(block.ExecutionPayload.PrevRandao[:]))
+		f_el_parent_hash.Append(block.ExecutionPayload.ParentHash.String())
+		f_el_logs_bloom.Append("0x" + hex.EncodeToString(block.ExecutionPayload.LogsBloom[:]))
+
+		// Blobs
+		f_el_blob_gas_used.Append(block.ExecutionPayload.BlobGasUsed)
+		f_el_excess_blob_gas.Append(block.ExecutionPayload.ExcessBlobGas)
+		f_el_blob_base_fee.Append(uint256FromBig(block.BlobBaseFee()))
+		f_blobs.Append(block.Blobs())
+		f_blob_target.Append(block.BlobParameters.Target)
+		f_blob_max.Append(block.BlobParameters.Max)
+
+		// Clients advertised in the graffiti
+		f_cl_client.Append(block.GraffitiClients.CLClient)
+		f_cl_client_version.Append(block.GraffitiClients.CLVersion)
+		f_el_client.Append(block.GraffitiClients.ELClient)
+		f_el_client_version.Append(block.GraffitiClients.ELVersion)
 	}
 
 	return proto.Input{
@@ -168,50 +222,37 @@ func blocksInput(blocks []spec.AgnosticBlock) proto.Input {
 		{Name: "f_compression_time_ms", Data: f_compression_time_ms},
 		{Name: "f_decompression_time_ms", Data: f_decompression_time_ms},
 		{Name: "f_payload_size_bytes", Data: f_payload_size_bytes},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_version", Data: f_version},
+		{Name: "f_finalized", Data: f_finalized},
+		{Name: "f_el_extra_data", Data: f_el_extra_data},
+		{Name: "f_el_extra_data_text", Data: f_el_extra_data_text},
+		{Name: "f_el_prev_randao", Data: f_el_prev_randao},
+		{Name: "f_el_parent_hash", Data: f_el_parent_hash},
+		{Name: "f_el_logs_bloom", Data: f_el_logs_bloom},
+		{Name: "f_el_blob_gas_used", Data: f_el_blob_gas_used},
+		{Name: "f_el_excess_blob_gas", Data: f_el_excess_blob_gas},
+		{Name: "f_el_blob_base_fee", Data: f_el_blob_base_fee},
+		{Name: "f_blobs", Data: f_blobs},
+		{Name: "f_blob_target", Data: f_blob_target},
+		{Name: "f_blob_max", Data: f_blob_max},
+		{Name: "f_cl_client", Data: f_cl_client},
+		{Name: "f_cl_client_version", Data: f_cl_client_version},
+		{Name: "f_el_client", Data: f_el_client},
+		{Name: "f_el_client_version", Data: f_el_client_version},
 	}
 }
 
-func (s *DBService) DeleteBlockMetrics(slot phase0.Slot) error {
-
-	err := s.Delete(DeletableObject{
-		query: deleteBlockQuery,
-		table: blocksTable,
-		args:  []any{slot},
-	})
-	if err != nil {
-		return err
-	}
-
-	err = s.Delete(DeletableObject{
-		query: deleteTransactionsQuery,
-		table: transactionsTable,
-		args:  []any{slot},
-	})
-	if err != nil {
-		return err
-	}
-	err = s.Delete(DeletableObject{
-		query: deleteWithdrawalsQuery,
-		table: withdrawalsTable,
-		args:  []any{slot},
-	})
-	if err != nil {
-		return err
-	}
-	err = s.Delete(DeletableObject{
-		query: deleteBlobsQuery,
-		table: blobsTable,
-		args:  []any{slot},
-	})
-	if err != nil {
-		return err
-	}
-	return nil
+// printableString turns free text set by proposers and builders into valid UTF-8
+// without the zero padding.
+func printableString(data []byte) string {
+	text := strings.ToValidUTF8(string(data), "?")
+	return strings.ReplaceAll(text, "\u0000", "")
 }
 
 func (p *DBService) PersistBlocks(data []spec.AgnosticBlock) error {
 	persistObj := PersistableObject[spec.AgnosticBlock]{
-		input: blocksInput,
+		input: p.blocksInput,
 		table: blocksTable,
 		query: insertBlockQuery,
 	}
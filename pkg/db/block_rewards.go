@@ -1,6 +1,8 @@
 package db
 
 import (
+	"math/big"
+
 	"github.com/ClickHouse/ch-go/proto"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
@@ -16,21 +18,37 @@ var (
 		f_cl_api_reward,
 		f_relays,
 		f_builder_pubkey,
-		f_bid_commission)
+		f_bid_commission,
+		f_finalized,
+		f_builder,
+		f_build_type,
+		f_el_reward,
+		f_cl_reward,
+		f_max_bid_value,
+		f_value_left,
+		f_blob_burnt_fees)
 		VALUES`
 )
 
-func blockRewardsInput(blocks []BlockReward) proto.Input {
+func (p *DBService) blockRewardsInput(blocks []BlockReward) proto.Input {
 	// one object per column
 	var (
 		f_slot             proto.ColUInt64
-		f_reward_fees      proto.ColUInt64
-		f_burnt_fees       proto.ColUInt64
+		f_reward_fees      proto.ColUInt256
+		f_burnt_fees       proto.ColUInt256
 		f_cl_manual_reward proto.ColUInt64
 		f_cl_api_reward    proto.ColUInt64
 		f_relays           = new(proto.ColStr).Array()
 		f_builder_pubkey   proto.ColStr
-		f_bid_commission   proto.ColUInt64
+		f_bid_commission   proto.ColUInt256
+		f_finalized        proto.ColBool
+		f_builder          proto.ColStr
+		f_build_type       proto.ColStr
+		f_el_reward        proto.ColUInt256
+		f_cl_reward        proto.ColUInt64
+		f_max_bid_value    proto.ColUInt256
+		f_value_left       proto.ColUInt256
+		f_blob_burnt_fees  proto.ColUInt256
 	)
 
 	for _, blockReward := range blocks {
@@ -41,13 +59,21 @@ func blockRewardsInput(blocks []BlockReward) proto.Input {
 		}
 
 		f_slot.Append(uint64(blockReward.Slot))
-		f_reward_fees.Append(blockReward.RewardFees)
-		f_burnt_fees.Append(blockReward.BurntFees)
+		f_reward_fees.Append(uint256FromBig(blockReward.RewardFees))
+		f_burnt_fees.Append(uint256FromBig(blockReward.BurntFees))
 		f_cl_manual_reward.Append(uint64(blockReward.CLManualReward))
 		f_cl_api_reward.Append(uint64(blockReward.CLApiReward))
 		f_relays.Append(blockReward.Relays)
 		f_builder_pubkey.Append(builder_pubkey)
-		f_bid_commission.Append(blockReward.BidCommision)
+		f_bid_commission.Append(uint256FromBig(blockReward.BidCommision))
+		f_finalized.Append(p.isSlotFinalized(blockReward.Slot))
+		f_builder.Append(blockReward.Builder)
+		f_build_type.Append(blockReward.BuildType)
+		f_el_reward.Append(uint256FromBig(blockReward.ELReward))
+		f_cl_reward.Append(uint64(blockReward.CLReward))
+		f_max_bid_value.Append(uint256FromBig(blockReward.MaxBidValue))
+		f_value_left.Append(uint256FromBig(blockReward.ValueLeft))
+		f_blob_burnt_fees.Append(uint256FromBig(blockReward.BlobBurntFees))
 	}
 
 	return proto.Input{
@@ -60,12 +86,20 @@ func blockRewardsInput(blocks []BlockReward) proto.Input {
 		{Name: "f_relays", Data: f_relays},
 		{Name: "f_builder_pubkey", Data: f_builder_pubkey},
 		{Name: "f_bid_commission", Data: f_bid_commission},
+		{Name: "f_finalized", Data: f_finalized},
+		{Name: "f_builder", Data: f_builder},
+		{Name: "f_build_type", Data: f_build_type},
+		{Name: "f_el_reward", Data: f_el_reward},
+		{Name: "f_cl_reward", Data: f_cl_reward},
+		{Name: "f_max_bid_value", Data: f_max_bid_value},
+		{Name: "f_value_left", Data: f_value_left},
+		{Name: "f_blob_burnt_fees", Data: f_blob_burnt_fees},
 	}
 }
 
 func (p *DBService) PersistBlockRewards(data []BlockReward) error {
 	persistObj := PersistableObject[BlockReward]{
-		input: blockRewardsInput,
+		input: p.blockRewardsInput,
 		table: blockRewardsTable,
 		query: insertBlockRewardsQuery,
 	}
@@ -85,9 +119,16 @@ type BlockReward struct {
 	Slot           phase0.Slot
 	CLManualReward phase0.Gwei // Gwei
 	CLApiReward    phase0.Gwei // Gwei
-	RewardFees     uint64      // Gwei
-	BurntFees      uint64      // Gwei
+	RewardFees     *big.Int    // Wei, priority fees
+	BurntFees      *big.Int    // Wei, base fees burnt
+	BlobBurntFees  *big.Int    // Wei, blob fees burnt
 	Relays         []string
 	BuilderPubkeys []string
-	BidCommision   uint64
+	BidCommision   *big.Int    // Wei
+	Builder        string      // name of the builder, empty if not identified
+	BuildType      string      // local, relay or unknown
+	ELReward       *big.Int    // Wei, what the proposer earned on the execution layer
+	CLReward       phase0.Gwei // Gwei, API reward or the manual one when not available
+	MaxBidValue    *big.Int    // Wei, highest bid received by the relays for the slot
+	ValueLeft      *big.Int    // Wei, what a local build missed compared to the best delivered bid
 }
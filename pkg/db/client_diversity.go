@@ -0,0 +1,72 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	clientDiversityTable       = "t_client_diversity"
+	insertClientDiversityQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_layer,
+		f_client,
+		f_blocks,
+		f_share)
+		VALUES`
+)
+
+// ClientDiversity counts the blocks of an epoch whose graffiti advertised a client.
+type ClientDiversity struct {
+	Epoch  phase0.Epoch
+	Layer  string // cl or el
+	Client string // unknown when not advertised
+	Blocks uint64
+	Share  float64 // of the proposed blocks of the epoch
+}
+
+func clientDiversityInput(diversity []ClientDiversity) proto.Input {
+	// one object per column
+	var (
+		f_epoch  proto.ColUInt64
+		f_layer  proto.ColStr
+		f_client proto.ColStr
+		f_blocks proto.ColUInt64
+		f_share  proto.ColFloat64
+	)
+
+	for _, item := range diversity {
+		f_epoch.Append(uint64(item.Epoch))
+		f_layer.Append(item.Layer)
+		f_client.Append(item.Client)
+		f_blocks.Append(item.Blocks)
+		f_share.Append(item.Share)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_layer", Data: f_layer},
+		{Name: "f_client", Data: f_client},
+		{Name: "f_blocks", Data: f_blocks},
+		{Name: "f_share", Data: f_share},
+	}
+}
+
+func (p *DBService) PersistClientDiversity(data []ClientDiversity) error {
+	persistObj := PersistableObject[ClientDiversity]{
+		input: clientDiversityInput,
+		table: clientDiversityTable,
+		query: insertClientDiversityQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting client diversity: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	blockComplianceTable       = "t_block_compliance"
+	insertBlockComplianceQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_epoch,
+		f_block_root,
+		f_proposer_index,
+		f_compliant,
+		f_transactions,
+		f_flagged_transactions,
+		f_flagged_tx_hashes,
+		f_relays,
+		f_builder,
+		f_build_type,
+		f_finalized)
+		VALUES`
+
+	complianceSummaryTable       = "t_compliance_summary"
+	insertComplianceSummaryQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_group,
+		f_name,
+		f_blocks,
+		f_compliant_blocks,
+		f_transactions,
+		f_flagged_transactions)
+		VALUES`
+)
+
+// BlockCompliance tells whether a block included transactions touching an address of the
+// compliance list, together with who built and delivered it.
+type BlockCompliance struct {
+	Slot          phase0.Slot
+	Epoch         phase0.Epoch
+	BlockRoot     phase0.Root
+	ProposerIndex phase0.ValidatorIndex
+	Compliant     bool
+	Transactions  uint64
+	FlaggedTxs    []phase0.Hash32
+	Relays        []string
+	Builder       string
+	BuildType     string
+}
+
+// ComplianceSummary aggregates the blocks of an epoch delivered by a relay or built by a builder.
+type ComplianceSummary struct {
+	Epoch           phase0.Epoch
+	Group           string // relay or builder
+	Name            string
+	Blocks          uint64
+	CompliantBlocks uint64
+	Transactions    uint64
+	FlaggedTxs      uint64
+}
+
+func (p *DBService) blockComplianceInput(blocks []BlockCompliance) proto.Input {
+	// one object per column
+	var (
+		f_slot                 proto.ColUInt64
+		f_epoch                proto.ColUInt64
+		f_block_root           proto.ColStr
+		f_proposer_index       proto.ColUInt64
+		f_compliant            proto.ColBool
+		f_transactions         proto.ColUInt64
+		f_flagged_transactions proto.ColUInt64
+		f_flagged_tx_hashes    = new(proto.ColStr).Array()
+		f_relays               = new(proto.ColStr).Array()
+		f_builder              proto.ColStr
+		f_build_type           proto.ColStr
+		f_finalized            proto.ColBool
+	)
+
+	for _, block := range blocks {
+		f_slot.Append(uint64(block.Slot))
+		f_epoch.Append(uint64(block.Epoch))
+		f_block_root.Append(block.BlockRoot.String())
+		f_proposer_index.Append(uint64(block.ProposerIndex))
+		f_compliant.Append(block.Compliant)
+		f_transactions.Append(block.Transactions)
+		f_flagged_transactions.Append(uint64(len(block.FlaggedTxs)))
+		hashes := make([]string, 0, len(block.FlaggedTxs))
+		for _, hash := range block.FlaggedTxs {
+			hashes = append(hashes, hash.String())
+		}
+		f_flagged_tx_hashes.Append(hashes)
+		f_relays.Append(block.Relays)
+		f_builder.Append(block.Builder)
+		f_build_type.Append(block.BuildType)
+		f_finalized.Append(p.isSlotFinalized(block.Slot))
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_proposer_index", Data: f_proposer_index},
+		{Name: "f_compliant", Data: f_compliant},
+		{Name: "f_transactions", Data: f_transactions},
+		{Name: "f_flagged_transactions", Data: f_flagged_transactions},
+		{Name: "f_flagged_tx_hashes", Data: f_flagged_tx_hashes},
+		{Name: "f_relays", Data: f_relays},
+		{Name: "f_builder", Data: f_builder},
+		{Name: "f_build_type", Data: f_build_type},
+		{Name: "f_finalized", Data: f_finalized},
+	}
+}
+
+func complianceSummaryInput(summaries []ComplianceSummary) proto.Input {
+	// one object per column
+	var (
+		f_epoch                proto.ColUInt64
+		f_group                proto.ColStr
+		f_name                 proto.ColStr
+		f_blocks               proto.ColUInt64
+		f_compliant_blocks     proto.ColUInt64
+		f_transactions         proto.ColUInt64
+		f_flagged_transactions proto.ColUInt64
+	)
+
+	for _, summary := range summaries {
+		f_epoch.Append(uint64(summary.Epoch))
+		f_group.Append(summary.Group)
+		f_name.Append(summary.Name)
+		f_blocks.Append(summary.Blocks)
+		f_compliant_blocks.Append(summary.CompliantBlocks)
+		f_transactions.Append(summary.Transactions)
+		f_flagged_transactions.Append(summary.FlaggedTxs)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_group", Data: f_group},
+		{Name: "f_name", Data: f_name},
+		{Name: "f_blocks", Data: f_blocks},
+		{Name: "f_compliant_blocks", Data: f_compliant_blocks},
+		{Name: "f_transactions", Data: f_transactions},
+		{Name: "f_flagged_transactions", Data: f_flagged_transactions},
+	}
+}
+
+func (p *DBService) PersistBlockCompliance(data []BlockCompliance) error {
+	persistObj := PersistableObject[BlockCompliance]{
+		input: p.blockComplianceInput,
+		table: blockComplianceTable,
+		query: insertBlockComplianceQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting block compliance: %s", err.Error())
+	}
+	return err
+}
+
+func (p *DBService) PersistComplianceSummary(data []ComplianceSummary) error {
+	persistObj := PersistableObject[ComplianceSummary]{
+		input: complianceSummaryInput,
+		table: complianceSummaryTable,
+		query: insertComplianceSummaryQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting compliance summary: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	dataColumnsTable              = "t_data_column_sidecars"
+	insertDataColumnSidecarsQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_block_root,
+		f_index,
+		f_cells,
+		f_kzg_commitments_inclusion_proof)
+		VALUES`
+
+	dataColumnEventsTable              = "t_data_column_sidecars_events"
+	insertDataColumnSidecarEventsQuery = `
+	INSERT INTO %s (
+		f_arrival_timestamp_ms,
+		f_slot,
+		f_block_root,
+		f_index,
+		f_blobs)
+		VALUES`
+)
+
+func dataColumnSidecarsInput(columns []spec.AgnosticDataColumnSidecar) proto.Input {
+	// one object per column
+	var (
+		f_slot                            proto.ColUInt64
+		f_block_root                      proto.ColStr
+		f_index                           proto.ColUInt64
+		f_cells                           proto.ColUInt64
+		f_kzg_commitments_inclusion_proof = new(proto.ColStr).Array()
+	)
+
+	for _, column := range columns {
+		f_slot.Append(uint64(column.Slot))
+		f_block_root.Append(column.BlockRoot.String())
+		f_index.Append(column.Index)
+		f_cells.Append(column.Cells)
+		proof := make([]string, 0, len(column.KZGCommitmentsInclusionProof))
+		for _, node := range column.KZGCommitmentsInclusionProof {
+			proof = append(proof, node.String())
+		}
+		f_kzg_commitments_inclusion_proof.Append(proof)
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_index", Data: f_index},
+		{Name: "f_cells", Data: f_cells},
+		{Name: "f_kzg_commitments_inclusion_proof", Data: f_kzg_commitments_inclusion_proof},
+	}
+}
+
+func dataColumnSidecarEventsInput(events []spec.DataColumnSidecarEventWrapper) proto.Input {
+	// one object per column
+	var (
+		f_arrival_timestamp_ms proto.ColUInt64
+		f_slot                 proto.ColUInt64
+		f_block_root           proto.ColStr
+		f_index                proto.ColUInt64
+		f_blobs                proto.ColUInt64
+	)
+
+	for _, event := range events {
+		f_arrival_timestamp_ms.Append(uint64(event.Timestamp.UnixMilli()))
+		f_slot.Append(uint64(event.DataColumnSidecarEvent.Slot))
+		f_block_root.Append(event.DataColumnSidecarEvent.BlockRoot.String())
+		f_index.Append(event.DataColumnSidecarEvent.Index)
+		f_blobs.Append(uint64(len(event.DataColumnSidecarEvent.KZGCommitments)))
+	}
+
+	return proto.Input{
+		{Name: "f_arrival_timestamp_ms", Data: f_arrival_timestamp_ms},
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_index", Data: f_index},
+		{Name: "f_blobs", Data: f_blobs},
+	}
+}
+
+func (p *DBService) PersistDataColumnSidecars(data []spec.AgnosticDataColumnSidecar) error {
+	persistObj := PersistableObject[spec.AgnosticDataColumnSidecar]{
+		input: dataColumnSidecarsInput,
+		table: dataColumnsTable,
+		query: insertDataColumnSidecarsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting data column sidecars: %s", err.Error())
+	}
+	return err
+}
+
+func (p *DBService) PersistDataColumnSidecarEvents(data []spec.DataColumnSidecarEventWrapper) error {
+	persistObj := PersistableObject[spec.DataColumnSidecarEventWrapper]{
+		input: dataColumnSidecarEventsInput,
+		table: dataColumnEventsTable,
+		query: insertDataColumnSidecarEventsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting data column events: %s", err.Error())
+	}
+	return err
+}
@@ -43,7 +43,10 @@ var (
 		f_deposit_requests_num,
 		f_withdrawal_requests_num,
 		f_consolidations_processed_num,
-		f_consolidations_processed_amount	
+		f_consolidations_processed_amount,
+		f_unavailable_metrics,
+		f_version,
+		f_finalized
 		)
 		VALUES`
 
@@ -52,14 +55,9 @@ var (
 		FROM %s
 		ORDER BY f_epoch DESC
 		LIMIT 1`
-
-	deleteEpochsQuery = `
-		DELETE FROM %s
-		WHERE f_epoch = $1;
-`
 )
 
-func epochsInput(epochs []spec.Epoch) proto.Input {
+func (p *DBService) epochsInput(epochs []spec.Epoch) proto.Input {
 	// one object per column
 	var (
 		f_epoch                            proto.ColUInt64
@@ -94,8 +92,13 @@ func epochsInput(epochs []spec.Epoch) proto.Input {
 		f_withdrawal_requests_num          proto.ColUInt64
 		f_consolidations_processed_num     proto.ColUInt64
 		f_consolidations_processed_amount  proto.ColUInt64
+		f_unavailable_metrics              = new(proto.ColStr).Array()
+		f_version                          proto.ColUInt64
+		f_finalized                        proto.ColBool
 	)
 
+	version := nextRowVersion()
+
 	for _, epoch := range epochs {
 		f_epoch.Append(uint64(epoch.Epoch))
 		f_slot.Append(uint64(epoch.Slot))
@@ -129,6 +132,9 @@ func epochsInput(epochs []spec.Epoch) proto.Input {
 		f_withdrawal_requests_num.Append(uint64(epoch.WithdrawalRequestsNum))
 		f_consolidations_processed_num.Append(epoch.ConsolidationsProcessedNum)
 		f_consolidations_processed_amount.Append(uint64(epoch.ConsolidationsProcessedAmount))
+		f_unavailable_metrics.Append(epoch.UnavailableMetrics)
+		f_version.Append(version)
+		f_finalized.Append(p.isFinalized(epoch.Epoch))
 	}
 
 	return proto.Input{
@@ -164,12 +170,15 @@ func epochsInput(epochs []spec.Epoch) proto.Input {
 		{Name: "f_withdrawal_requests_num", Data: f_withdrawal_requests_num},
 		{Name: "f_consolidations_processed_num", Data: f_consolidations_processed_num},
 		{Name: "f_consolidations_processed_amount", Data: f_consolidations_processed_amount},
+		{Name: "f_unavailable_metrics", Data: f_unavailable_metrics},
+		{Name: "f_version", Data: f_version},
+		{Name: "f_finalized", Data: f_finalized},
 	}
 }
 
 func (p *DBService) PersistEpochs(data []spec.Epoch) error {
 	persistObj := PersistableObject[spec.Epoch]{
-		input: epochsInput,
+		input: p.epochsInput,
 		table: epochsTable,
 		query: insertEpochQuery,
 	}
@@ -201,65 +210,3 @@ func (p *DBService) RetrieveLastEpoch() (phase0.Epoch, error) {
 	return 0, err
 
 }
-
-// delete metrics that use the state at epoch x
-func (s *DBService) DeleteStateMetrics(epoch phase0.Epoch) error {
-	var err error
-
-	// epochs are written at currentState using current state and nextState
-	err = s.Delete(DeletableObject{
-		query: deleteEpochsQuery,
-		table: epochsTable,
-		args:  []any{epoch - 1},
-	}) // when deleteState -> nextState
-
-	if err != nil {
-		return err
-	}
-	err = s.Delete(DeletableObject{
-		query: deleteEpochsQuery,
-		table: epochsTable,
-		args:  []any{epoch},
-	}) // when deleteState -> currentState
-	if err != nil {
-		return err
-	}
-
-	// proposer duties are writter using nextState
-	err = s.Delete(DeletableObject{
-		query: deleteProposerDutiesQuery,
-		table: proposerDutiesTable,
-		args:  []any{epoch},
-	})
-	if err != nil {
-		return err
-	}
-
-	// valRewards are written at nextState using prevState, currentState and nextState
-	err = s.Delete(DeletableObject{
-		query: deleteValidatorRewardsInEpochQuery,
-		table: valRewardsTable,
-		args:  []any{epoch + 2},
-	}) // when deleteState -> prevState
-	if err != nil {
-		return err
-	}
-	err = s.Delete(DeletableObject{
-		query: deleteValidatorRewardsInEpochQuery,
-		table: valRewardsTable,
-		args:  []any{epoch + 1},
-	}) // when deleteState -> currentState
-	if err != nil {
-		return err
-	}
-	err = s.Delete(DeletableObject{
-		query: deleteValidatorRewardsInEpochQuery,
-		table: valRewardsTable,
-		args:  []any{epoch},
-	}) // when deleteState -> nextState
-	if err != nil {
-		return err
-	}
-	return nil
-
-}
@@ -35,7 +35,7 @@ func eth1DepositsInput(eth1Deposits []spec.ETH1Deposit) proto.Input {
 		f_sender                 proto.ColStr
 		f_recipient              proto.ColStr
 		f_gas_used               proto.ColUInt64
-		f_gas_price              proto.ColUInt64
+		f_gas_price              proto.ColUInt256
 		f_deposit_index          proto.ColUInt64
 		f_validator_pubkey       proto.ColStr
 		f_withdrawal_credentials proto.ColStr
@@ -51,7 +51,7 @@ func eth1DepositsInput(eth1Deposits []spec.ETH1Deposit) proto.Input {
 		f_sender.Append(eth1Deposit.Sender)
 		f_recipient.Append(eth1Deposit.Recipient)
 		f_gas_used.Append(uint64(eth1Deposit.GasUsed))
-		f_gas_price.Append(uint64(eth1Deposit.GasPrice))
+		f_gas_price.Append(uint256FromBig(eth1Deposit.GasPrice))
 		f_deposit_index.Append(uint64(eth1Deposit.DepositIndex))
 		f_validator_pubkey.Append(eth1Deposit.ValidatorPubkey)
 		f_withdrawal_credentials.Append(eth1Deposit.WithdrawalCredentials)
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	finalityTransitionsTable       = "t_finality_transitions"
+	insertFinalityTransitionsQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_finalized_epoch,
+		f_state_root,
+		f_rewritten_slots,
+		f_state_rewritten,
+		f_timestamp)
+		VALUES`
+
+	selectLastFinalityTransitionQuery = `
+		SELECT f_epoch
+		FROM %s
+		ORDER BY f_epoch DESC
+		LIMIT 1`
+)
+
+// FinalityTransition records that the blocks and state of an epoch, written before
+// they were final, were checked against the finalized chain.
+type FinalityTransition struct {
+	Epoch          phase0.Epoch
+	FinalizedEpoch phase0.Epoch // epoch up to which the chain was checked
+	StateRoot      phase0.Root
+	RewrittenSlots uint8 // slots whose block root differed and were written again
+	StateRewritten bool  // the epoch metrics were written again
+	Timestamp      time.Time
+}
+
+func finalityTransitionsInput(transitions []FinalityTransition) proto.Input {
+	// one object per column
+	var (
+		f_epoch           proto.ColUInt64
+		f_finalized_epoch proto.ColUInt64
+		f_state_root      proto.ColStr
+		f_rewritten_slots proto.ColUInt8
+		f_state_rewritten proto.ColBool
+		f_timestamp       proto.ColUInt64
+	)
+
+	for _, transition := range transitions {
+		f_epoch.Append(uint64(transition.Epoch))
+		f_finalized_epoch.Append(uint64(transition.FinalizedEpoch))
+		f_state_root.Append(transition.StateRoot.String())
+		f_rewritten_slots.Append(transition.RewrittenSlots)
+		f_state_rewritten.Append(transition.StateRewritten)
+		f_timestamp.Append(uint64(transition.Timestamp.Unix()))
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_finalized_epoch", Data: f_finalized_epoch},
+		{Name: "f_state_root", Data: f_state_root},
+		{Name: "f_rewritten_slots", Data: f_rewritten_slots},
+		{Name: "f_state_rewritten", Data: f_state_rewritten},
+		{Name: "f_timestamp", Data: f_timestamp},
+	}
+}
+
+func (p *DBService) PersistFinalityTransitions(data []FinalityTransition) error {
+	persistObj := PersistableObject[FinalityTransition]{
+		input: finalityTransitionsInput,
+		table: finalityTransitionsTable,
+		query: insertFinalityTransitionsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting finality transitions: %s", err.Error())
+	}
+	return err
+}
+
+func (p *DBService) RetrieveLastFinalityTransition() (phase0.Epoch, error) {
+	var dest []struct {
+		F_epoch uint64 `ch:"f_epoch"`
+	}
+
+	err := p.highSelect(
+		fmt.Sprintf(selectLastFinalityTransitionQuery, finalityTransitionsTable),
+		&dest)
+
+	if len(dest) > 0 {
+		return phase0.Epoch(dest[0].F_epoch), err
+	}
+	return 0, err
+}
+
+// SetFinalizedEpoch tells the epoch before which the chain is finalized. Rows of earlier
+// epochs are persisted with f_finalized set, the others are confirmed later by a transition.
+func (p *DBService) SetFinalizedEpoch(epoch phase0.Epoch) {
+	for {
+		current := p.finalizedEpoch.Load()
+		if uint64(epoch) <= current || p.finalizedEpoch.CompareAndSwap(current, uint64(epoch)) {
+			return
+		}
+	}
+}
+
+func (p *DBService) isFinalized(epoch phase0.Epoch) bool {
+	return uint64(epoch) < p.finalizedEpoch.Load()
+}
+
+func (p *DBService) isSlotFinalized(slot phase0.Slot) bool {
+	return p.isFinalized(spec.EpochAtSlot(slot))
+}
@@ -0,0 +1,22 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFinalizedEpoch(t *testing.T) {
+	p := &DBService{}
+	assert.False(t, p.isFinalized(0))
+
+	p.SetFinalizedEpoch(10)
+	assert.True(t, p.isFinalized(9))
+	assert.False(t, p.isFinalized(10))
+	assert.True(t, p.isSlotFinalized(319))
+	assert.False(t, p.isSlotFinalized(320))
+
+	// finality never goes backwards
+	p.SetFinalizedEpoch(5)
+	assert.True(t, p.isFinalized(9))
+}
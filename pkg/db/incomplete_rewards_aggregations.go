@@ -0,0 +1,69 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	incompleteRewardsAggregationsTable      = "t_incomplete_rewards_aggregations"
+	insertIncompleteRewardsAggregationQuery = `
+	INSERT INTO %s (
+		f_group,
+		f_start_epoch,
+		f_end_epoch,
+		f_missing_epochs)
+		VALUES`
+)
+
+// IncompleteRewardsAggregation is an aggregation window that was not persisted
+// because some of its epochs were never processed.
+type IncompleteRewardsAggregation struct {
+	Group         string // validator or entity
+	StartEpoch    phase0.Epoch
+	EndEpoch      phase0.Epoch
+	MissingEpochs []phase0.Epoch
+}
+
+func incompleteRewardsAggregationsInput(windows []IncompleteRewardsAggregation) proto.Input {
+	// one object per column
+	var (
+		f_group          proto.ColStr
+		f_start_epoch    proto.ColUInt64
+		f_end_epoch      proto.ColUInt64
+		f_missing_epochs = new(proto.ColUInt64).Array()
+	)
+
+	for _, window := range windows {
+		f_group.Append(window.Group)
+		f_start_epoch.Append(uint64(window.StartEpoch))
+		f_end_epoch.Append(uint64(window.EndEpoch))
+		missing := make([]uint64, 0, len(window.MissingEpochs))
+		for _, epoch := range window.MissingEpochs {
+			missing = append(missing, uint64(epoch))
+		}
+		f_missing_epochs.Append(missing)
+	}
+
+	return proto.Input{
+		{Name: "f_group", Data: f_group},
+		{Name: "f_start_epoch", Data: f_start_epoch},
+		{Name: "f_end_epoch", Data: f_end_epoch},
+		{Name: "f_missing_epochs", Data: f_missing_epochs},
+	}
+}
+
+func (p *DBService) PersistIncompleteRewardsAggregation(window IncompleteRewardsAggregation) error {
+	persistObj := PersistableObject[IncompleteRewardsAggregation]{
+		input: incompleteRewardsAggregationsInput,
+		table: incompleteRewardsAggregationsTable,
+		query: insertIncompleteRewardsAggregationQuery,
+	}
+	persistObj.Append(window)
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting incomplete rewards aggregation: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	internalCallsTable       = "t_internal_calls"
+	insertInternalCallsQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_block_root,
+		f_tx_hash,
+		f_tx_idx,
+		f_trace_address,
+		f_call_type,
+		f_from,
+		f_to,
+		f_value,
+		f_gas,
+		f_gas_used,
+		f_error,
+		f_reverted)
+		VALUES`
+)
+
+func internalCallsInput(calls []spec.InternalCall) proto.Input {
+	// one object per column
+	var (
+		f_slot          proto.ColUInt64
+		f_block_root    proto.ColStr
+		f_tx_hash       proto.ColStr
+		f_tx_idx        proto.ColUInt64
+		f_trace_address = new(proto.ColUInt64).Array()
+		f_call_type     proto.ColStr
+		f_from          proto.ColStr
+		f_to            proto.ColStr
+		f_value         proto.ColStr
+		f_gas           proto.ColUInt64
+		f_gas_used      proto.ColUInt64
+		f_error         proto.ColStr
+		f_reverted      proto.ColBool
+	)
+
+	for _, call := range calls {
+		f_slot.Append(uint64(call.Slot))
+		f_block_root.Append(call.BlockRoot.String())
+		f_tx_hash.Append(call.TxHash.String())
+		f_tx_idx.Append(call.TxIdx)
+		f_trace_address.Append(call.TraceAddress)
+		f_call_type.Append(call.CallType)
+		f_from.Append(call.From.String())
+		f_to.Append(call.To.String())
+		value := "0"
+		if call.Value != nil {
+			value = call.Value.String()
+		}
+		f_value.Append(value)
+		f_gas.Append(call.Gas)
+		f_gas_used.Append(call.GasUsed)
+		f_error.Append(call.Error)
+		f_reverted.Append(call.Reverted)
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_tx_hash", Data: f_tx_hash},
+		{Name: "f_tx_idx", Data: f_tx_idx},
+		{Name: "f_trace_address", Data: f_trace_address},
+		{Name: "f_call_type", Data: f_call_type},
+		{Name: "f_from", Data: f_from},
+		{Name: "f_to", Data: f_to},
+		{Name: "f_value", Data: f_value},
+		{Name: "f_gas", Data: f_gas},
+		{Name: "f_gas_used", Data: f_gas_used},
+		{Name: "f_error", Data: f_error},
+		{Name: "f_reverted", Data: f_reverted},
+	}
+}
+
+func (p *DBService) PersistInternalCalls(data []spec.InternalCall) error {
+	persistObj := PersistableObject[spec.InternalCall]{
+		input: internalCallsInput,
+		table: internalCallsTable,
+		query: insertInternalCallsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting internal calls: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,302 @@
+package db
+
+import (
+	"encoding/hex"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+var (
+	logsTable       = "t_logs"
+	insertLogsQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_block_root,
+		f_el_block_number,
+		f_tx_hash,
+		f_tx_idx,
+		f_log_index,
+		f_address,
+		f_topics,
+		f_data)
+		VALUES`
+
+	decodedLogsTable       = "t_decoded_logs"
+	insertDecodedLogsQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_block_root,
+		f_tx_hash,
+		f_log_index,
+		f_address,
+		f_abi,
+		f_event,
+		f_signature,
+		f_arg_names,
+		f_arg_values)
+		VALUES`
+
+	tokenTransfersTable       = "t_token_transfers"
+	insertTokenTransfersQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_block_root,
+		f_tx_hash,
+		f_log_index,
+		f_token,
+		f_standard,
+		f_from,
+		f_to,
+		f_value)
+		VALUES`
+
+	tokenApprovalsTable       = "t_token_approvals"
+	insertTokenApprovalsQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_block_root,
+		f_tx_hash,
+		f_log_index,
+		f_token,
+		f_standard,
+		f_owner,
+		f_spender,
+		f_value)
+		VALUES`
+)
+
+func logsInput(logs []spec.AgnosticLog) proto.Input {
+	// one object per column
+	var (
+		f_slot            proto.ColUInt64
+		f_block_root      proto.ColStr
+		f_el_block_number proto.ColUInt64
+		f_tx_hash         proto.ColStr
+		f_tx_idx          proto.ColUInt64
+		f_log_index       proto.ColUInt64
+		f_address         proto.ColStr
+		f_topics          = new(proto.ColStr).Array()
+		f_data            proto.ColStr
+	)
+
+	for _, item := range logs {
+		f_slot.Append(uint64(item.Slot))
+		f_block_root.Append(item.BlockRoot.String())
+		f_el_block_number.Append(item.BlockNumber)
+		f_tx_hash.Append(item.TxHash.String())
+		f_tx_idx.Append(item.TxIdx)
+		f_log_index.Append(item.LogIndex)
+		f_address.Append(item.Address.String())
+		topics := make([]string, 0, len(item.Topics))
+		for _, topic := range item.Topics {
+			topics = append(topics, topic.String())
+		}
+		f_topics.Append(topics)
+		f_data.Append("0x" + hex.EncodeToString(item.Data))
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_el_block_number", Data: f_el_block_number},
+		{Name: "f_tx_hash", Data: f_tx_hash},
+		{Name: "f_tx_idx", Data: f_tx_idx},
+		{Name: "f_log_index", Data: f_log_index},
+		{Name: "f_address", Data: f_address},
+		{Name: "f_topics", Data: f_topics},
+		{Name: "f_data", Data: f_data},
+	}
+}
+
+func decodedLogsInput(logs []spec.DecodedLog) proto.Input {
+	// one object per column
+	var (
+		f_slot       proto.ColUInt64
+		f_block_root proto.ColStr
+		f_tx_hash    proto.ColStr
+		f_log_index  proto.ColUInt64
+		f_address    proto.ColStr
+		f_abi        proto.ColStr
+		f_event      proto.ColStr
+		f_signature  proto.ColStr
+		f_arg_names  = new(proto.ColStr).Array()
+		f_arg_values = new(proto.ColStr).Array()
+	)
+
+	for _, item := range logs {
+		f_slot.Append(uint64(item.Slot))
+		f_block_root.Append(item.BlockRoot.String())
+		f_tx_hash.Append(item.TxHash.String())
+		f_log_index.Append(item.LogIndex)
+		f_address.Append(item.Address.String())
+		f_abi.Append(item.ABI)
+		f_event.Append(item.Event)
+		f_signature.Append(item.Signature)
+		f_arg_names.Append(item.ArgNames)
+		f_arg_values.Append(item.ArgValues)
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_tx_hash", Data: f_tx_hash},
+		{Name: "f_log_index", Data: f_log_index},
+		{Name: "f_address", Data: f_address},
+		{Name: "f_abi", Data: f_abi},
+		{Name: "f_event", Data: f_event},
+		{Name: "f_signature", Data: f_signature},
+		{Name: "f_arg_names", Data: f_arg_names},
+		{Name: "f_arg_values", Data: f_arg_values},
+	}
+}
+
+func tokenTransfersInput(transfers []spec.TokenTransfer) proto.Input {
+	// one object per column
+	var (
+		f_slot       proto.ColUInt64
+		f_block_root proto.ColStr
+		f_tx_hash    proto.ColStr
+		f_log_index  proto.ColUInt64
+		f_token      proto.ColStr
+		f_standard   proto.ColStr
+		f_from       proto.ColStr
+		f_to         proto.ColStr
+		f_value      proto.ColUInt256
+	)
+
+	for _, item := range transfers {
+		f_slot.Append(uint64(item.Slot))
+		f_block_root.Append(item.BlockRoot.String())
+		f_tx_hash.Append(item.TxHash.String())
+		f_log_index.Append(item.LogIndex)
+		f_token.Append(item.Token.String())
+		f_standard.Append(item.Standard)
+		f_from.Append(item.From.String())
+		f_to.Append(item.To.String())
+		f_value.Append(uint256FromBig(item.Value))
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_tx_hash", Data: f_tx_hash},
+		{Name: "f_log_index", Data: f_log_index},
+		{Name: "f_token", Data: f_token},
+		{Name: "f_standard", Data: f_standard},
+		{Name: "f_from", Data: f_from},
+		{Name: "f_to", Data: f_to},
+		{Name: "f_value", Data: f_value},
+	}
+}
+
+func tokenApprovalsInput(approvals []spec.TokenApproval) proto.Input {
+	// one object per column
+	var (
+		f_slot       proto.ColUInt64
+		f_block_root proto.ColStr
+		f_tx_hash    proto.ColStr
+		f_log_index  proto.ColUInt64
+		f_token      proto.ColStr
+		f_standard   proto.ColStr
+		f_owner      proto.ColStr
+		f_spender    proto.ColStr
+		f_value      proto.ColUInt256
+	)
+
+	for _, item := range approvals {
+		f_slot.Append(uint64(item.Slot))
+		f_block_root.Append(item.BlockRoot.String())
+		f_tx_hash.Append(item.TxHash.String())
+		f_log_index.Append(item.LogIndex)
+		f_token.Append(item.Token.String())
+		f_standard.Append(item.Standard)
+		f_owner.Append(item.Owner.String())
+		f_spender.Append(item.Spender.String())
+		f_value.Append(uint256FromBig(item.Value))
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_tx_hash", Data: f_tx_hash},
+		{Name: "f_log_index", Data: f_log_index},
+		{Name: "f_token", Data: f_token},
+		{Name: "f_standard", Data: f_standard},
+		{Name: "f_owner", Data: f_owner},
+		{Name: "f_spender", Data: f_spender},
+		{Name: "f_value", Data: f_value},
+	}
+}
+
+func (p *DBService) PersistLogs(data []spec.AgnosticLog) error {
+	persistObj := PersistableObject[spec.AgnosticLog]{
+		input: logsInput,
+		table: logsTable,
+		query: insertLogsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting logs: %s", err.Error())
+	}
+	return err
+}
+
+func (p *DBService) PersistDecodedLogs(data []spec.DecodedLog) error {
+	persistObj := PersistableObject[spec.DecodedLog]{
+		input: decodedLogsInput,
+		table: decodedLogsTable,
+		query: insertDecodedLogsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting decoded logs: %s", err.Error())
+	}
+	return err
+}
+
+func (p *DBService) PersistTokenTransfers(data []spec.TokenTransfer) error {
+	persistObj := PersistableObject[spec.TokenTransfer]{
+		input: tokenTransfersInput,
+		table: tokenTransfersTable,
+		query: insertTokenTransfersQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting token transfers: %s", err.Error())
+	}
+	return err
+}
+
+func (p *DBService) PersistTokenApprovals(data []spec.TokenApproval) error {
+	persistObj := PersistableObject[spec.TokenApproval]{
+		input: tokenApprovalsInput,
+		table: tokenApprovalsTable,
+		query: insertTokenApprovalsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting token approvals: %s", err.Error())
+	}
+	return err
+}
@@ -12,6 +12,9 @@ type DBMetrics struct {
 	APIRewards       bool
 	Transactions     bool
 	BlobSidecars     bool
+	Logs             bool
+	Traces           bool
+	DataColumns      bool
 }
 
 func NewMetrics(input string) (DBMetrics, error) {
@@ -34,9 +37,20 @@ func NewMetrics(input string) (DBMetrics, error) {
 		case "transactions":
 			dbMetrics.Transactions = true
 			dbMetrics.Block = true
+		case "logs":
+			dbMetrics.Logs = true
+			dbMetrics.Transactions = true
+			dbMetrics.Block = true
+		case "traces":
+			dbMetrics.Traces = true
+			dbMetrics.Transactions = true
+			dbMetrics.Block = true
 		case "blob_sidecars":
 			dbMetrics.Block = true
 			dbMetrics.BlobSidecars = true
+		case "data_columns":
+			dbMetrics.Block = true
+			dbMetrics.DataColumns = true
 		default:
 			return DBMetrics{}, fmt.Errorf("could not parse metric: %s", item)
 		}
@@ -0,0 +1 @@
+ALTER TABLE t_epoch_metrics_summary DROP COLUMN IF EXISTS f_unavailable_metrics;
@@ -0,0 +1 @@
+ALTER TABLE t_epoch_metrics_summary ADD COLUMN IF NOT EXISTS f_unavailable_metrics Array(TEXT) DEFAULT [];
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS t_backfill_leases(
+	f_start_epoch UInt64,
+	f_end_epoch UInt64,
+	f_owner TEXT,
+	f_status TEXT,
+	f_expires_at UInt64,
+	f_version UInt64)
+	ENGINE = ReplacingMergeTree(f_version)
+	ORDER BY (f_start_epoch);
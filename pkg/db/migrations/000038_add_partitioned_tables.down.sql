@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS t_validator_rewards_summary_partitioned;
+DROP TABLE IF EXISTS t_transactions_partitioned;
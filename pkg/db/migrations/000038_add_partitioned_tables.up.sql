@@ -0,0 +1,11 @@
+-- Partitioned copies of the largest tables, filled and swapped in by the partition-tables command.
+-- Partitions hold 6750 epochs (~30 days).
+CREATE TABLE IF NOT EXISTS t_validator_rewards_summary_partitioned AS t_validator_rewards_summary
+	ENGINE = ReplacingMergeTree()
+	PARTITION BY intDiv(f_epoch, 6750)
+	ORDER BY (f_epoch, f_val_idx);
+
+CREATE TABLE IF NOT EXISTS t_transactions_partitioned AS t_transactions
+	ENGINE = ReplacingMergeTree()
+	PARTITION BY intDiv(f_slot, 216000)
+	ORDER BY (f_slot, f_el_block_number, f_hash);
@@ -0,0 +1,29 @@
+DROP VIEW IF EXISTS v_validator_rewards_summary;
+DROP VIEW IF EXISTS v_proposer_duties;
+DROP VIEW IF EXISTS v_epoch_metrics_summary;
+DROP VIEW IF EXISTS v_blob_sidecars;
+DROP VIEW IF EXISTS v_withdrawals;
+DROP VIEW IF EXISTS v_transactions;
+DROP VIEW IF EXISTS v_block_metrics;
+
+ALTER TABLE t_validator_rewards_summary
+DROP COLUMN IF EXISTS f_version;
+
+ALTER TABLE t_proposer_duties
+DROP COLUMN IF EXISTS f_version;
+
+ALTER TABLE t_epoch_metrics_summary
+DROP COLUMN IF EXISTS f_version;
+
+ALTER TABLE t_blob_sidecars
+DROP COLUMN IF EXISTS f_block_root;
+
+ALTER TABLE t_withdrawals
+DROP COLUMN IF EXISTS f_block_root;
+
+ALTER TABLE t_transactions
+DROP COLUMN IF EXISTS f_block_root;
+
+ALTER TABLE t_block_metrics
+DROP COLUMN IF EXISTS f_version,
+DROP COLUMN IF EXISTS f_block_root;
@@ -0,0 +1,64 @@
+-- Reorged or non-finalized slots and epochs are rewritten by inserting a newer version
+-- of their rows instead of deleting the previous ones.
+ALTER TABLE t_block_metrics
+ADD COLUMN IF NOT EXISTS f_block_root TEXT DEFAULT '',
+ADD COLUMN IF NOT EXISTS f_version UInt64 DEFAULT 0;
+
+ALTER TABLE t_transactions
+ADD COLUMN IF NOT EXISTS f_block_root TEXT DEFAULT '';
+
+ALTER TABLE t_withdrawals
+ADD COLUMN IF NOT EXISTS f_block_root TEXT DEFAULT '';
+
+ALTER TABLE t_blob_sidecars
+ADD COLUMN IF NOT EXISTS f_block_root TEXT DEFAULT '';
+
+ALTER TABLE t_epoch_metrics_summary
+ADD COLUMN IF NOT EXISTS f_version UInt64 DEFAULT 0;
+
+ALTER TABLE t_proposer_duties
+ADD COLUMN IF NOT EXISTS f_version UInt64 DEFAULT 0;
+
+ALTER TABLE t_validator_rewards_summary
+ADD COLUMN IF NOT EXISTS f_version UInt64 DEFAULT 0;
+
+-- Latest version of every slot, the block content of other versions is left out by its block root.
+CREATE VIEW IF NOT EXISTS v_block_metrics AS
+SELECT *
+FROM t_block_metrics
+ORDER BY f_slot, f_version DESC
+LIMIT 1 BY f_slot;
+
+CREATE VIEW IF NOT EXISTS v_transactions AS
+SELECT *
+FROM t_transactions
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+CREATE VIEW IF NOT EXISTS v_withdrawals AS
+SELECT *
+FROM t_withdrawals
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+CREATE VIEW IF NOT EXISTS v_blob_sidecars AS
+SELECT *
+FROM t_blob_sidecars
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+-- Latest version of every epoch, proposer slot and validator reward.
+CREATE VIEW IF NOT EXISTS v_epoch_metrics_summary AS
+SELECT *
+FROM t_epoch_metrics_summary
+ORDER BY f_epoch, f_version DESC
+LIMIT 1 BY f_epoch;
+
+CREATE VIEW IF NOT EXISTS v_proposer_duties AS
+SELECT *
+FROM t_proposer_duties
+ORDER BY f_proposer_slot, f_version DESC
+LIMIT 1 BY f_proposer_slot;
+
+CREATE VIEW IF NOT EXISTS v_validator_rewards_summary AS
+SELECT *
+FROM t_validator_rewards_summary
+ORDER BY f_epoch, f_val_idx, f_version DESC
+LIMIT 1 BY f_epoch, f_val_idx;
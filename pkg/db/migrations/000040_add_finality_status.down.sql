@@ -0,0 +1,40 @@
+CREATE OR REPLACE VIEW v_block_metrics AS
+SELECT *
+FROM t_block_metrics
+ORDER BY f_slot, f_version DESC
+LIMIT 1 BY f_slot;
+
+CREATE OR REPLACE VIEW v_epoch_metrics_summary AS
+SELECT *
+FROM t_epoch_metrics_summary
+ORDER BY f_epoch, f_version DESC
+LIMIT 1 BY f_epoch;
+
+CREATE OR REPLACE VIEW v_proposer_duties AS
+SELECT *
+FROM t_proposer_duties
+ORDER BY f_proposer_slot, f_version DESC
+LIMIT 1 BY f_proposer_slot;
+
+CREATE OR REPLACE VIEW v_validator_rewards_summary AS
+SELECT *
+FROM t_validator_rewards_summary
+ORDER BY f_epoch, f_val_idx, f_version DESC
+LIMIT 1 BY f_epoch, f_val_idx;
+
+DROP TABLE IF EXISTS t_finality_transitions;
+
+ALTER TABLE t_validator_rewards_summary
+DROP COLUMN IF EXISTS f_finalized;
+
+ALTER TABLE t_proposer_duties
+DROP COLUMN IF EXISTS f_finalized;
+
+ALTER TABLE t_epoch_metrics_summary
+DROP COLUMN IF EXISTS f_finalized;
+
+ALTER TABLE t_block_rewards
+DROP COLUMN IF EXISTS f_finalized;
+
+ALTER TABLE t_block_metrics
+DROP COLUMN IF EXISTS f_finalized;
@@ -0,0 +1,51 @@
+-- Rows written before their epoch was finalized have f_finalized = false until AdvanceFinalized
+-- checks them against the finalized chain, which records a row in t_finality_transitions.
+ALTER TABLE t_block_metrics
+ADD COLUMN IF NOT EXISTS f_finalized Bool DEFAULT false;
+
+ALTER TABLE t_block_rewards
+ADD COLUMN IF NOT EXISTS f_finalized Bool DEFAULT false;
+
+ALTER TABLE t_epoch_metrics_summary
+ADD COLUMN IF NOT EXISTS f_finalized Bool DEFAULT false;
+
+ALTER TABLE t_proposer_duties
+ADD COLUMN IF NOT EXISTS f_finalized Bool DEFAULT false;
+
+ALTER TABLE t_validator_rewards_summary
+ADD COLUMN IF NOT EXISTS f_finalized Bool DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS t_finality_transitions(
+	f_epoch UInt64,
+	f_finalized_epoch UInt64,
+	f_state_root TEXT,
+	f_rewritten_slots UInt8,
+	f_state_rewritten Bool,
+	f_timestamp UInt64)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY f_epoch;
+
+-- f_finalized of the views also accounts for the epochs confirmed after the rows were written.
+CREATE OR REPLACE VIEW v_block_metrics AS
+SELECT * REPLACE (f_finalized OR f_epoch IN (SELECT f_epoch FROM t_finality_transitions) AS f_finalized)
+FROM t_block_metrics
+ORDER BY f_slot, f_version DESC
+LIMIT 1 BY f_slot;
+
+CREATE OR REPLACE VIEW v_epoch_metrics_summary AS
+SELECT * REPLACE (f_finalized OR f_epoch IN (SELECT f_epoch FROM t_finality_transitions) AS f_finalized)
+FROM t_epoch_metrics_summary
+ORDER BY f_epoch, f_version DESC
+LIMIT 1 BY f_epoch;
+
+CREATE OR REPLACE VIEW v_proposer_duties AS
+SELECT * REPLACE (f_finalized OR intDiv(f_proposer_slot, 32) IN (SELECT f_epoch FROM t_finality_transitions) AS f_finalized)
+FROM t_proposer_duties
+ORDER BY f_proposer_slot, f_version DESC
+LIMIT 1 BY f_proposer_slot;
+
+CREATE OR REPLACE VIEW v_validator_rewards_summary AS
+SELECT * REPLACE (f_finalized OR f_epoch IN (SELECT f_epoch FROM t_finality_transitions) AS f_finalized)
+FROM t_validator_rewards_summary
+ORDER BY f_epoch, f_val_idx, f_version DESC
+LIMIT 1 BY f_epoch, f_val_idx;
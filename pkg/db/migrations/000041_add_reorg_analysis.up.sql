@@ -0,0 +1,20 @@
+-- One row per fork found from a block whose parent root is not the previous block in the cache.
+CREATE TABLE IF NOT EXISTS t_reorg_analysis(
+	f_new_head_slot UInt64,
+	f_new_head_block_root TEXT,
+	f_old_head_slot UInt64,
+	f_old_head_block_root TEXT,
+	f_ancestor_slot UInt64,
+	f_ancestor_block_root TEXT,
+	f_depth_slots UInt64,
+	f_depth_blocks UInt64,
+	f_new_branch_blocks UInt64,
+	f_orphaned_slots Array(UInt64),
+	f_orphaned_proposers Array(UInt64),
+	f_dropped_attestations UInt64,
+	f_dropped_transactions UInt64,
+	f_dropped_tx_hashes Array(TEXT),
+	f_mode TEXT,
+	f_timestamp UInt64)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_new_head_slot, f_new_head_block_root);
@@ -0,0 +1,5 @@
+DROP VIEW IF EXISTS v_orphaned_transactions;
+DROP TABLE IF EXISTS t_orphaned_attestations;
+DROP TABLE IF EXISTS t_orphaned_blob_sidecars;
+DROP TABLE IF EXISTS t_orphaned_withdrawals;
+DROP TABLE IF EXISTS t_orphaned_transactions;
@@ -0,0 +1,42 @@
+-- Content of orphaned blocks, with the columns of the canonical tables.
+-- Keyed by block root as different forks may orphan blocks at the same slot.
+CREATE TABLE IF NOT EXISTS t_orphaned_transactions AS t_transactions
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_hash);
+
+CREATE TABLE IF NOT EXISTS t_orphaned_withdrawals AS t_withdrawals
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_index);
+
+CREATE TABLE IF NOT EXISTS t_orphaned_blob_sidecars AS t_blob_sidecars
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_index);
+
+CREATE TABLE IF NOT EXISTS t_orphaned_attestations(
+	f_slot UInt64,
+	f_block_root TEXT,
+	f_index UInt64,
+	f_attestation_slot UInt64,
+	f_committee_indices Array(UInt64),
+	f_beacon_block_root TEXT,
+	f_source_epoch UInt64,
+	f_target_epoch UInt64,
+	f_target_root TEXT,
+	f_aggregation_bits TEXT,
+	f_attesting_bits UInt64)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_index);
+
+-- A transaction is re-included when the canonical chain has it, the slot is the one it was included at.
+CREATE VIEW IF NOT EXISTS v_orphaned_transactions AS
+SELECT
+	o.*,
+	r.f_reinclusion_slot > 0 AS f_reincluded,
+	r.f_reinclusion_slot AS f_reinclusion_slot
+FROM t_orphaned_transactions AS o
+LEFT JOIN (
+	SELECT f_hash, min(f_slot) AS f_reinclusion_slot
+	FROM v_transactions
+	WHERE f_hash IN (SELECT f_hash FROM t_orphaned_transactions)
+	GROUP BY f_hash) AS r
+ON o.f_hash = r.f_hash;
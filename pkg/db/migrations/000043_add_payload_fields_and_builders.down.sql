@@ -0,0 +1,19 @@
+ALTER TABLE t_block_rewards DROP COLUMN IF EXISTS f_build_type;
+
+ALTER TABLE t_block_rewards DROP COLUMN IF EXISTS f_builder;
+
+ALTER TABLE t_block_metrics DROP COLUMN IF EXISTS f_el_logs_bloom;
+
+ALTER TABLE t_block_metrics DROP COLUMN IF EXISTS f_el_parent_hash;
+
+ALTER TABLE t_block_metrics DROP COLUMN IF EXISTS f_el_prev_randao;
+
+ALTER TABLE t_block_metrics DROP COLUMN IF EXISTS f_el_extra_data_text;
+
+ALTER TABLE t_block_metrics DROP COLUMN IF EXISTS f_el_extra_data;
+
+CREATE OR REPLACE VIEW v_block_metrics AS
+SELECT * REPLACE (f_finalized OR f_epoch IN (SELECT f_epoch FROM t_finality_transitions) AS f_finalized)
+FROM t_block_metrics
+ORDER BY f_slot, f_version DESC
+LIMIT 1 BY f_slot;
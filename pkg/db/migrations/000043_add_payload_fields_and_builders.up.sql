@@ -0,0 +1,28 @@
+ALTER TABLE t_block_metrics
+ADD COLUMN IF NOT EXISTS f_el_extra_data TEXT DEFAULT '';
+
+ALTER TABLE t_block_metrics
+ADD COLUMN IF NOT EXISTS f_el_extra_data_text TEXT DEFAULT '';
+
+ALTER TABLE t_block_metrics
+ADD COLUMN IF NOT EXISTS f_el_prev_randao TEXT DEFAULT '';
+
+ALTER TABLE t_block_metrics
+ADD COLUMN IF NOT EXISTS f_el_parent_hash TEXT DEFAULT '';
+
+ALTER TABLE t_block_metrics
+ADD COLUMN IF NOT EXISTS f_el_logs_bloom TEXT DEFAULT '';
+
+-- builder name when identified, and whether the block was a local build, came through a relay or is unknown
+ALTER TABLE t_block_rewards
+ADD COLUMN IF NOT EXISTS f_builder TEXT DEFAULT '';
+
+ALTER TABLE t_block_rewards
+ADD COLUMN IF NOT EXISTS f_build_type TEXT DEFAULT '';
+
+-- the columns of a view are fixed when created
+CREATE OR REPLACE VIEW v_block_metrics AS
+SELECT * REPLACE (f_finalized OR f_epoch IN (SELECT f_epoch FROM t_finality_transitions) AS f_finalized)
+FROM t_block_metrics
+ORDER BY f_slot, f_version DESC
+LIMIT 1 BY f_slot;
@@ -0,0 +1,9 @@
+DROP VIEW IF EXISTS v_block_value_comparison;
+
+ALTER TABLE t_block_rewards DROP COLUMN IF EXISTS f_value_left;
+
+ALTER TABLE t_block_rewards DROP COLUMN IF EXISTS f_max_bid_value;
+
+ALTER TABLE t_block_rewards DROP COLUMN IF EXISTS f_cl_reward;
+
+ALTER TABLE t_block_rewards DROP COLUMN IF EXISTS f_el_reward;
@@ -0,0 +1,29 @@
+-- realised proposer rewards and the best bid delivered by the relays for the slot
+ALTER TABLE t_block_rewards
+ADD COLUMN IF NOT EXISTS f_el_reward UInt64 DEFAULT 0;
+
+ALTER TABLE t_block_rewards
+ADD COLUMN IF NOT EXISTS f_cl_reward UInt64 DEFAULT 0;
+
+ALTER TABLE t_block_rewards
+ADD COLUMN IF NOT EXISTS f_max_bid_value UInt64 DEFAULT 0;
+
+ALTER TABLE t_block_rewards
+ADD COLUMN IF NOT EXISTS f_value_left UInt64 DEFAULT 0;
+
+-- value comparison of every block with its proposer entity
+CREATE OR REPLACE VIEW v_block_value_comparison AS
+SELECT
+	r.f_slot AS f_slot,
+	m.f_epoch AS f_epoch,
+	m.f_proposer_index AS f_proposer_index,
+	p.f_pool_name AS f_entity,
+	r.f_build_type AS f_build_type,
+	r.f_builder AS f_builder,
+	r.f_el_reward AS f_el_reward,
+	r.f_cl_reward AS f_cl_reward,
+	r.f_max_bid_value AS f_max_bid_value,
+	r.f_value_left AS f_value_left
+FROM t_block_rewards AS r FINAL
+INNER JOIN v_block_metrics AS m ON r.f_slot = m.f_slot
+LEFT JOIN t_eth2_pubkeys AS p FINAL ON m.f_proposer_index = p.f_val_idx;
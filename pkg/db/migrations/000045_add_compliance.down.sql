@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS t_compliance_summary;
+
+DROP TABLE IF EXISTS t_block_compliance;
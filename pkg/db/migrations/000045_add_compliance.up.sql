@@ -0,0 +1,28 @@
+-- One row per block checked against the compliance address list.
+CREATE TABLE IF NOT EXISTS t_block_compliance(
+	f_slot UInt64,
+	f_epoch UInt64,
+	f_block_root TEXT,
+	f_proposer_index UInt64,
+	f_compliant BOOLEAN,
+	f_transactions UInt64,
+	f_flagged_transactions UInt64,
+	f_flagged_tx_hashes Array(TEXT),
+	f_relays Array(TEXT),
+	f_builder TEXT,
+	f_build_type TEXT,
+	f_finalized BOOLEAN DEFAULT false)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root);
+
+-- Blocks of an epoch per relay that delivered them and per builder.
+CREATE TABLE IF NOT EXISTS t_compliance_summary(
+	f_epoch UInt64,
+	f_group TEXT,
+	f_name TEXT,
+	f_blocks UInt64,
+	f_compliant_blocks UInt64,
+	f_transactions UInt64,
+	f_flagged_transactions UInt64)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_epoch, f_group, f_name);
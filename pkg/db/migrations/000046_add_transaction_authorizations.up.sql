@@ -0,0 +1,45 @@
+-- number of authorizations of set code transactions (EIP-7702)
+ALTER TABLE t_transactions
+ADD COLUMN IF NOT EXISTS f_authorizations UInt64 DEFAULT 0;
+
+ALTER TABLE t_orphaned_transactions
+ADD COLUMN IF NOT EXISTS f_authorizations UInt64 DEFAULT 0;
+
+-- One row per authorization tuple of a set code transaction.
+CREATE TABLE IF NOT EXISTS t_transaction_authorizations(
+	f_slot UInt64,
+	f_block_root TEXT,
+	f_tx_hash TEXT,
+	f_tx_idx UInt64,
+	f_index UInt64,
+	f_chain_id TEXT,
+	f_address TEXT,
+	f_nonce UInt64,
+	f_authority TEXT,
+	f_valid BOOLEAN)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_tx_idx, f_index);
+
+-- the columns of a view are fixed when created
+CREATE OR REPLACE VIEW v_transactions AS
+SELECT *
+FROM t_transactions
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+CREATE OR REPLACE VIEW v_orphaned_transactions AS
+SELECT
+	o.*,
+	r.f_reinclusion_slot > 0 AS f_reincluded,
+	r.f_reinclusion_slot AS f_reinclusion_slot
+FROM t_orphaned_transactions AS o
+LEFT JOIN (
+	SELECT f_hash, min(f_slot) AS f_reinclusion_slot
+	FROM v_transactions
+	WHERE f_hash IN (SELECT f_hash FROM t_orphaned_transactions)
+	GROUP BY f_hash) AS r
+ON o.f_hash = r.f_hash;
+
+CREATE VIEW IF NOT EXISTS v_transaction_authorizations AS
+SELECT *
+FROM t_transaction_authorizations
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
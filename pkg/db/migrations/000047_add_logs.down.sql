@@ -0,0 +1,15 @@
+DROP VIEW IF EXISTS v_token_approvals;
+
+DROP VIEW IF EXISTS v_token_transfers;
+
+DROP VIEW IF EXISTS v_decoded_logs;
+
+DROP VIEW IF EXISTS v_logs;
+
+DROP TABLE IF EXISTS t_token_approvals;
+
+DROP TABLE IF EXISTS t_token_transfers;
+
+DROP TABLE IF EXISTS t_decoded_logs;
+
+DROP TABLE IF EXISTS t_logs;
@@ -0,0 +1,73 @@
+-- Receipt logs and the events decoded from them, written with the logs metric.
+CREATE TABLE IF NOT EXISTS t_logs(
+	f_slot UInt64,
+	f_block_root TEXT,
+	f_el_block_number UInt64,
+	f_tx_hash TEXT,
+	f_tx_idx UInt64,
+	f_log_index UInt64,
+	f_address TEXT,
+	f_topics Array(TEXT),
+	f_data TEXT)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_log_index);
+
+CREATE TABLE IF NOT EXISTS t_decoded_logs(
+	f_slot UInt64,
+	f_block_root TEXT,
+	f_tx_hash TEXT,
+	f_log_index UInt64,
+	f_address TEXT,
+	f_abi TEXT,
+	f_event TEXT,
+	f_signature TEXT,
+	f_arg_names Array(TEXT),
+	f_arg_values Array(TEXT))
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_log_index);
+
+CREATE TABLE IF NOT EXISTS t_token_transfers(
+	f_slot UInt64,
+	f_block_root TEXT,
+	f_tx_hash TEXT,
+	f_log_index UInt64,
+	f_token TEXT,
+	f_standard TEXT,
+	f_from TEXT,
+	f_to TEXT,
+	f_value TEXT)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_log_index);
+
+CREATE TABLE IF NOT EXISTS t_token_approvals(
+	f_slot UInt64,
+	f_block_root TEXT,
+	f_tx_hash TEXT,
+	f_log_index UInt64,
+	f_token TEXT,
+	f_standard TEXT,
+	f_owner TEXT,
+	f_spender TEXT,
+	f_value TEXT)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_log_index);
+
+CREATE VIEW IF NOT EXISTS v_logs AS
+SELECT *
+FROM t_logs
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+CREATE VIEW IF NOT EXISTS v_decoded_logs AS
+SELECT *
+FROM t_decoded_logs
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+CREATE VIEW IF NOT EXISTS v_token_transfers AS
+SELECT *
+FROM t_token_transfers
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+CREATE VIEW IF NOT EXISTS v_token_approvals AS
+SELECT *
+FROM t_token_approvals
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
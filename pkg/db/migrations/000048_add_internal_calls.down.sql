@@ -0,0 +1,3 @@
+DROP VIEW IF EXISTS v_internal_calls;
+
+DROP TABLE IF EXISTS t_internal_calls;
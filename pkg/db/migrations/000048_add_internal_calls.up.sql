@@ -0,0 +1,22 @@
+-- Internal calls of the transactions moving ETH or failing, written with the traces metric.
+CREATE TABLE IF NOT EXISTS t_internal_calls(
+	f_slot UInt64,
+	f_block_root TEXT,
+	f_tx_hash TEXT,
+	f_tx_idx UInt64,
+	f_trace_address Array(UInt64),
+	f_call_type TEXT,
+	f_from TEXT,
+	f_to TEXT,
+	f_value TEXT,
+	f_gas UInt64,
+	f_gas_used UInt64,
+	f_error TEXT,
+	f_reverted BOOLEAN)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_tx_idx, f_trace_address);
+
+CREATE VIEW IF NOT EXISTS v_internal_calls AS
+SELECT *
+FROM t_internal_calls
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
@@ -0,0 +1,49 @@
+ALTER TABLE t_eth1_deposits
+MODIFY COLUMN f_gas_price UInt64;
+
+ALTER TABLE t_block_rewards
+DROP COLUMN IF EXISTS f_blob_burnt_fees,
+MODIFY COLUMN f_reward_fees UInt64,
+MODIFY COLUMN f_burnt_fees UInt64,
+MODIFY COLUMN f_bid_commission UInt64,
+MODIFY COLUMN f_el_reward UInt64 DEFAULT 0,
+MODIFY COLUMN f_max_bid_value UInt64 DEFAULT 0,
+MODIFY COLUMN f_value_left UInt64 DEFAULT 0;
+
+ALTER TABLE t_orphaned_transactions
+DROP COLUMN IF EXISTS f_tip_fee,
+DROP COLUMN IF EXISTS f_burnt_fee,
+DROP COLUMN IF EXISTS f_blob_burnt_fee,
+MODIFY COLUMN f_gas_price UInt64,
+MODIFY COLUMN f_gas_tip_cap UInt64,
+MODIFY COLUMN f_gas_fee_cap UInt64,
+MODIFY COLUMN f_blob_gas_price UInt64,
+MODIFY COLUMN f_blob_gas_fee_cap UInt64;
+
+ALTER TABLE t_transactions
+DROP COLUMN IF EXISTS f_tip_fee,
+DROP COLUMN IF EXISTS f_burnt_fee,
+DROP COLUMN IF EXISTS f_blob_burnt_fee,
+MODIFY COLUMN f_gas_price UInt64,
+MODIFY COLUMN f_gas_tip_cap UInt64,
+MODIFY COLUMN f_gas_fee_cap UInt64,
+MODIFY COLUMN f_blob_gas_price UInt64,
+MODIFY COLUMN f_blob_gas_fee_cap UInt64;
+
+CREATE OR REPLACE VIEW v_transactions AS
+SELECT *
+FROM t_transactions
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+CREATE OR REPLACE VIEW v_orphaned_transactions AS
+SELECT
+	o.*,
+	r.f_reinclusion_slot > 0 AS f_reincluded,
+	r.f_reinclusion_slot AS f_reinclusion_slot
+FROM t_orphaned_transactions AS o
+LEFT JOIN (
+	SELECT f_hash, min(f_slot) AS f_reinclusion_slot
+	FROM v_transactions
+	WHERE f_hash IN (SELECT f_hash FROM t_orphaned_transactions)
+	GROUP BY f_hash) AS r
+ON o.f_hash = r.f_hash;
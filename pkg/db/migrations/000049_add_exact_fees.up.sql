@@ -0,0 +1,51 @@
+-- fee caps and prices can exceed 64 bits, fees are stored in Wei with full precision
+ALTER TABLE t_transactions
+MODIFY COLUMN f_gas_price UInt256,
+MODIFY COLUMN f_gas_tip_cap UInt256,
+MODIFY COLUMN f_gas_fee_cap UInt256,
+MODIFY COLUMN f_blob_gas_price UInt256,
+MODIFY COLUMN f_blob_gas_fee_cap UInt256,
+ADD COLUMN IF NOT EXISTS f_tip_fee UInt256 DEFAULT 0,
+ADD COLUMN IF NOT EXISTS f_burnt_fee UInt256 DEFAULT 0,
+ADD COLUMN IF NOT EXISTS f_blob_burnt_fee UInt256 DEFAULT 0;
+
+ALTER TABLE t_orphaned_transactions
+MODIFY COLUMN f_gas_price UInt256,
+MODIFY COLUMN f_gas_tip_cap UInt256,
+MODIFY COLUMN f_gas_fee_cap UInt256,
+MODIFY COLUMN f_blob_gas_price UInt256,
+MODIFY COLUMN f_blob_gas_fee_cap UInt256,
+ADD COLUMN IF NOT EXISTS f_tip_fee UInt256 DEFAULT 0,
+ADD COLUMN IF NOT EXISTS f_burnt_fee UInt256 DEFAULT 0,
+ADD COLUMN IF NOT EXISTS f_blob_burnt_fee UInt256 DEFAULT 0;
+
+ALTER TABLE t_block_rewards
+MODIFY COLUMN f_reward_fees UInt256,
+MODIFY COLUMN f_burnt_fees UInt256,
+MODIFY COLUMN f_bid_commission UInt256,
+MODIFY COLUMN f_el_reward UInt256 DEFAULT 0,
+MODIFY COLUMN f_max_bid_value UInt256 DEFAULT 0,
+MODIFY COLUMN f_value_left UInt256 DEFAULT 0,
+ADD COLUMN IF NOT EXISTS f_blob_burnt_fees UInt256 DEFAULT 0;
+
+ALTER TABLE t_eth1_deposits
+MODIFY COLUMN f_gas_price UInt256;
+
+-- the columns of a view are fixed when created
+CREATE OR REPLACE VIEW v_transactions AS
+SELECT *
+FROM t_transactions
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+CREATE OR REPLACE VIEW v_orphaned_transactions AS
+SELECT
+	o.*,
+	r.f_reinclusion_slot > 0 AS f_reincluded,
+	r.f_reinclusion_slot AS f_reinclusion_slot
+FROM t_orphaned_transactions AS o
+LEFT JOIN (
+	SELECT f_hash, min(f_slot) AS f_reinclusion_slot
+	FROM v_transactions
+	WHERE f_hash IN (SELECT f_hash FROM t_orphaned_transactions)
+	GROUP BY f_hash) AS r
+ON o.f_hash = r.f_hash;
@@ -0,0 +1,13 @@
+ALTER TABLE t_block_metrics
+DROP COLUMN IF EXISTS f_el_blob_gas_used,
+DROP COLUMN IF EXISTS f_el_excess_blob_gas,
+DROP COLUMN IF EXISTS f_el_blob_base_fee,
+DROP COLUMN IF EXISTS f_blobs,
+DROP COLUMN IF EXISTS f_blob_target,
+DROP COLUMN IF EXISTS f_blob_max;
+
+CREATE OR REPLACE VIEW v_block_metrics AS
+SELECT * REPLACE (f_finalized OR f_epoch IN (SELECT f_epoch FROM t_finality_transitions) AS f_finalized)
+FROM t_block_metrics
+ORDER BY f_slot, f_version DESC
+LIMIT 1 BY f_slot;
@@ -0,0 +1,15 @@
+-- blob gas market of Deneb+ blocks, target and max from the blob schedule of the epoch
+ALTER TABLE t_block_metrics
+ADD COLUMN IF NOT EXISTS f_el_blob_gas_used UInt64 DEFAULT 0,
+ADD COLUMN IF NOT EXISTS f_el_excess_blob_gas UInt64 DEFAULT 0,
+ADD COLUMN IF NOT EXISTS f_el_blob_base_fee UInt256 DEFAULT 0,
+ADD COLUMN IF NOT EXISTS f_blobs UInt64 DEFAULT 0,
+ADD COLUMN IF NOT EXISTS f_blob_target UInt64 DEFAULT 0,
+ADD COLUMN IF NOT EXISTS f_blob_max UInt64 DEFAULT 0;
+
+-- the columns of a view are fixed when created
+CREATE OR REPLACE VIEW v_block_metrics AS
+SELECT * REPLACE (f_finalized OR f_epoch IN (SELECT f_epoch FROM t_finality_transitions) AS f_finalized)
+FROM t_block_metrics
+ORDER BY f_slot, f_version DESC
+LIMIT 1 BY f_slot;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS t_rollup_blob_usage;
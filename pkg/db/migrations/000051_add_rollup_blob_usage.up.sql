@@ -0,0 +1,13 @@
+-- Blob usage of the rollups per epoch, written when a rollups file is given.
+CREATE TABLE IF NOT EXISTS t_rollup_blob_usage(
+	f_epoch UInt64,
+	f_rollup TEXT,
+	f_transactions UInt64,
+	f_blobs UInt64,
+	f_blob_gas_used UInt64,
+	f_blob_fees UInt256,
+	f_sidecar_blobs UInt64,
+	f_used_bytes UInt64,
+	f_fill_ratio Float64)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_epoch, f_rollup);
@@ -0,0 +1,10 @@
+ALTER TABLE t_orphaned_blob_sidecars
+DROP COLUMN IF EXISTS f_kzg_verification;
+
+ALTER TABLE t_blob_sidecars
+DROP COLUMN IF EXISTS f_kzg_verification;
+
+CREATE OR REPLACE VIEW v_blob_sidecars AS
+SELECT *
+FROM t_blob_sidecars
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
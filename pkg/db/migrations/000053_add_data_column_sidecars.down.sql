@@ -0,0 +1,5 @@
+DROP VIEW IF EXISTS v_data_column_availability;
+
+DROP TABLE IF EXISTS t_data_column_sidecars_events;
+
+DROP TABLE IF EXISTS t_data_column_sidecars;
@@ -0,0 +1,48 @@
+-- PeerDAS columns held by the beacon node for every Fulu block with blobs, written with the data_columns metric.
+CREATE TABLE IF NOT EXISTS t_data_column_sidecars(
+	f_slot UInt64,
+	f_block_root TEXT,
+	f_index UInt64,
+	f_cells UInt64,
+	f_kzg_commitments_inclusion_proof Array(TEXT))
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_index);
+
+-- data_column_sidecar events received in head mode, every arrival is kept
+CREATE TABLE IF NOT EXISTS t_data_column_sidecars_events(
+	f_arrival_timestamp_ms UInt64,
+	f_slot UInt64,
+	f_block_root TEXT,
+	f_index UInt64,
+	f_blobs UInt64)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_index, f_arrival_timestamp_ms);
+
+-- columns available per canonical block, with when the first and the last gossiped columns arrived
+CREATE VIEW IF NOT EXISTS v_data_column_availability AS
+SELECT
+	c.f_slot AS f_slot,
+	c.f_block_root AS f_block_root,
+	c.f_columns AS f_columns,
+	c.f_indices AS f_indices,
+	e.f_gossiped_columns AS f_gossiped_columns,
+	e.f_first_arrival_timestamp_ms AS f_first_arrival_timestamp_ms,
+	e.f_last_arrival_timestamp_ms AS f_last_arrival_timestamp_ms
+FROM (
+	SELECT f_slot, f_block_root, uniqExact(f_index) AS f_columns, arraySort(groupUniqArray(f_index)) AS f_indices
+	FROM t_data_column_sidecars
+	WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics)
+	GROUP BY f_slot, f_block_root) AS c
+LEFT JOIN (
+	SELECT
+		f_slot,
+		f_block_root,
+		uniqExact(f_index) AS f_gossiped_columns,
+		min(f_index_arrival_ms) AS f_first_arrival_timestamp_ms,
+		max(f_index_arrival_ms) AS f_last_arrival_timestamp_ms
+	FROM (
+		SELECT f_slot, f_block_root, f_index, min(f_arrival_timestamp_ms) AS f_index_arrival_ms
+		FROM t_data_column_sidecars_events
+		GROUP BY f_slot, f_block_root, f_index)
+	GROUP BY f_slot, f_block_root) AS e
+ON c.f_slot = e.f_slot AND c.f_block_root = e.f_block_root;
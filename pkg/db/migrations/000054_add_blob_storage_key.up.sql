@@ -0,0 +1,12 @@
+-- key of the blob contents in the blob store, empty when not stored
+ALTER TABLE t_blob_sidecars
+ADD COLUMN IF NOT EXISTS f_storage_key TEXT DEFAULT '';
+
+ALTER TABLE t_orphaned_blob_sidecars
+ADD COLUMN IF NOT EXISTS f_storage_key TEXT DEFAULT '';
+
+-- the columns of a view are fixed when created
+CREATE OR REPLACE VIEW v_blob_sidecars AS
+SELECT *
+FROM t_blob_sidecars
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
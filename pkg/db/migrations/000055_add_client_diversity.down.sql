@@ -0,0 +1,15 @@
+DROP VIEW IF EXISTS v_entity_client_diversity;
+
+DROP TABLE IF EXISTS t_client_diversity;
+
+ALTER TABLE t_block_metrics
+DROP COLUMN IF EXISTS f_cl_client,
+DROP COLUMN IF EXISTS f_cl_client_version,
+DROP COLUMN IF EXISTS f_el_client,
+DROP COLUMN IF EXISTS f_el_client_version;
+
+CREATE OR REPLACE VIEW v_block_metrics AS
+SELECT * REPLACE (f_finalized OR f_epoch IN (SELECT f_epoch FROM t_finality_transitions) AS f_finalized)
+FROM t_block_metrics
+ORDER BY f_slot, f_version DESC
+LIMIT 1 BY f_slot;
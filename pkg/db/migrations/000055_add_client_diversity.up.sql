@@ -0,0 +1,43 @@
+-- clients advertised in the graffiti, empty when not advertised
+ALTER TABLE t_block_metrics
+ADD COLUMN IF NOT EXISTS f_cl_client TEXT DEFAULT '',
+ADD COLUMN IF NOT EXISTS f_cl_client_version TEXT DEFAULT '',
+ADD COLUMN IF NOT EXISTS f_el_client TEXT DEFAULT '',
+ADD COLUMN IF NOT EXISTS f_el_client_version TEXT DEFAULT '';
+
+-- the columns of a view are fixed when created
+CREATE OR REPLACE VIEW v_block_metrics AS
+SELECT * REPLACE (f_finalized OR f_epoch IN (SELECT f_epoch FROM t_finality_transitions) AS f_finalized)
+FROM t_block_metrics
+ORDER BY f_slot, f_version DESC
+LIMIT 1 BY f_slot;
+
+-- Share of the consensus (cl) and execution (el) clients among the proposed blocks of each epoch.
+CREATE TABLE IF NOT EXISTS t_client_diversity(
+	f_epoch UInt64,
+	f_layer TEXT,
+	f_client TEXT,
+	f_blocks UInt64,
+	f_share Float64)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_epoch, f_layer, f_client);
+
+CREATE OR REPLACE VIEW v_entity_client_diversity AS
+SELECT
+	f_epoch,
+	f_entity,
+	f_layer,
+	f_client,
+	count() AS f_blocks,
+	count() / sum(count()) OVER (PARTITION BY f_epoch, f_entity, f_layer) AS f_share
+FROM (
+	SELECT
+		m.f_epoch AS f_epoch,
+		p.f_pool_name AS f_entity,
+		layer.1 AS f_layer,
+		if(layer.2 = '', 'unknown', layer.2) AS f_client
+	FROM v_block_metrics AS m
+	ARRAY JOIN [('cl', m.f_cl_client), ('el', m.f_el_client)] AS layer
+	LEFT JOIN t_eth2_pubkeys AS p FINAL ON m.f_proposer_index = p.f_val_idx
+	WHERE m.f_proposed)
+GROUP BY f_epoch, f_entity, f_layer, f_client;
@@ -0,0 +1,7 @@
+-- Aggregation windows not written to t_validator_rewards_aggregation because some of their epochs were not processed.
+CREATE TABLE IF NOT EXISTS t_incomplete_rewards_aggregations(
+	f_start_epoch UInt64,
+	f_end_epoch UInt64,
+	f_missing_epochs Array(UInt64))
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_start_epoch, f_end_epoch);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS t_incomplete_rewards_aggregations_levels(
+	f_start_epoch UInt64,
+	f_end_epoch UInt64,
+	f_missing_epochs Array(UInt64))
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_start_epoch, f_end_epoch);
+
+INSERT INTO t_incomplete_rewards_aggregations_levels
+SELECT f_start_epoch, f_end_epoch, f_missing_epochs FROM t_incomplete_rewards_aggregations WHERE f_group = 'validator';
+
+EXCHANGE TABLES t_incomplete_rewards_aggregations AND t_incomplete_rewards_aggregations_levels;
+
+DROP TABLE IF EXISTS t_incomplete_rewards_aggregations_levels;
+
+DROP TABLE IF EXISTS t_entity_rewards_aggregation;
+
+CREATE TABLE IF NOT EXISTS t_validator_rewards_aggregation_levels AS t_validator_rewards_aggregation
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_start_epoch, f_val_idx);
+
+-- windows of different sizes starting at the same epoch collapse under the previous key
+INSERT INTO t_validator_rewards_aggregation_levels SELECT * FROM t_validator_rewards_aggregation;
+
+EXCHANGE TABLES t_validator_rewards_aggregation AND t_validator_rewards_aggregation_levels;
+
+DROP TABLE IF EXISTS t_validator_rewards_aggregation_levels;
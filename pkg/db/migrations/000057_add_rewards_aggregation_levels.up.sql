@@ -0,0 +1,49 @@
+-- windows of several sizes share the table, so the end epoch is part of the key
+CREATE TABLE IF NOT EXISTS t_validator_rewards_aggregation_levels AS t_validator_rewards_aggregation
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_start_epoch, f_end_epoch, f_val_idx);
+
+INSERT INTO t_validator_rewards_aggregation_levels SELECT * FROM t_validator_rewards_aggregation;
+
+EXCHANGE TABLES t_validator_rewards_aggregation AND t_validator_rewards_aggregation_levels;
+
+DROP TABLE IF EXISTS t_validator_rewards_aggregation_levels;
+
+-- Rewards of the validators of each f_pool_name in t_eth2_pubkeys, for the entity levels.
+CREATE TABLE IF NOT EXISTS t_entity_rewards_aggregation(
+	f_entity TEXT,
+	f_start_epoch UInt64,
+	f_end_epoch UInt64,
+	f_validators UInt64,
+	f_reward Int64,
+	f_max_reward UInt64,
+	f_max_att_reward UInt64,
+	f_max_sync_reward UInt64,
+	f_base_reward UInt64,
+	f_in_sync_committee_count UInt64,
+	f_sync_committee_participations_included UInt64,
+	f_attestations_included UInt64,
+	f_missing_source_count UInt64,
+	f_missing_target_count UInt64,
+	f_missing_head_count UInt64,
+	f_block_api_reward UInt64,
+	f_block_experimental_reward UInt64,
+	f_inclusion_delay_sum UInt64)
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_start_epoch, f_end_epoch, f_entity);
+
+-- incomplete windows of the validator and entity levels
+CREATE TABLE IF NOT EXISTS t_incomplete_rewards_aggregations_levels(
+	f_group TEXT,
+	f_start_epoch UInt64,
+	f_end_epoch UInt64,
+	f_missing_epochs Array(UInt64))
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_group, f_start_epoch, f_end_epoch);
+
+INSERT INTO t_incomplete_rewards_aggregations_levels
+SELECT 'validator', f_start_epoch, f_end_epoch, f_missing_epochs FROM t_incomplete_rewards_aggregations;
+
+EXCHANGE TABLES t_incomplete_rewards_aggregations AND t_incomplete_rewards_aggregations_levels;
+
+DROP TABLE IF EXISTS t_incomplete_rewards_aggregations_levels;
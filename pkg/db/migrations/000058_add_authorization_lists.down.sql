@@ -0,0 +1,27 @@
+DROP TABLE IF EXISTS t_orphaned_transaction_authorizations;
+
+ALTER TABLE t_orphaned_transactions
+DROP COLUMN IF EXISTS f_authorization_authorities,
+DROP COLUMN IF EXISTS f_authorization_addresses;
+
+ALTER TABLE t_transactions
+DROP COLUMN IF EXISTS f_authorization_authorities,
+DROP COLUMN IF EXISTS f_authorization_addresses;
+
+CREATE OR REPLACE VIEW v_transactions AS
+SELECT *
+FROM t_transactions
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+CREATE OR REPLACE VIEW v_orphaned_transactions AS
+SELECT
+	o.*,
+	r.f_reinclusion_slot > 0 AS f_reincluded,
+	r.f_reinclusion_slot AS f_reinclusion_slot
+FROM t_orphaned_transactions AS o
+LEFT JOIN (
+	SELECT f_hash, min(f_slot) AS f_reinclusion_slot
+	FROM v_transactions
+	WHERE f_hash IN (SELECT f_hash FROM t_orphaned_transactions)
+	GROUP BY f_hash) AS r
+ON o.f_hash = r.f_hash;
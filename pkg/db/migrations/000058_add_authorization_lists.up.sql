@@ -0,0 +1,32 @@
+-- authorities and delegated addresses of the authorizations of set code transactions (EIP-7702),
+-- in the order of the authorization list, an empty authority if it could not be recovered
+ALTER TABLE t_transactions
+ADD COLUMN IF NOT EXISTS f_authorization_authorities Array(TEXT),
+ADD COLUMN IF NOT EXISTS f_authorization_addresses Array(TEXT);
+
+ALTER TABLE t_orphaned_transactions
+ADD COLUMN IF NOT EXISTS f_authorization_authorities Array(TEXT),
+ADD COLUMN IF NOT EXISTS f_authorization_addresses Array(TEXT);
+
+CREATE TABLE IF NOT EXISTS t_orphaned_transaction_authorizations AS t_transaction_authorizations
+	ENGINE = ReplacingMergeTree()
+	ORDER BY (f_slot, f_block_root, f_tx_idx, f_index);
+
+-- the columns of a view are fixed when created
+CREATE OR REPLACE VIEW v_transactions AS
+SELECT *
+FROM t_transactions
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+CREATE OR REPLACE VIEW v_orphaned_transactions AS
+SELECT
+	o.*,
+	r.f_reinclusion_slot > 0 AS f_reincluded,
+	r.f_reinclusion_slot AS f_reinclusion_slot
+FROM t_orphaned_transactions AS o
+LEFT JOIN (
+	SELECT f_hash, min(f_slot) AS f_reinclusion_slot
+	FROM v_transactions
+	WHERE f_hash IN (SELECT f_hash FROM t_orphaned_transactions)
+	GROUP BY f_hash) AS r
+ON o.f_hash = r.f_hash;
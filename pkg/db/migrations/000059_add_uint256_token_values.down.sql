@@ -0,0 +1,15 @@
+ALTER TABLE t_token_transfers
+MODIFY COLUMN f_value TEXT;
+
+ALTER TABLE t_token_approvals
+MODIFY COLUMN f_value TEXT;
+
+CREATE OR REPLACE VIEW v_token_transfers AS
+SELECT *
+FROM t_token_transfers
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+CREATE OR REPLACE VIEW v_token_approvals AS
+SELECT *
+FROM t_token_approvals
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
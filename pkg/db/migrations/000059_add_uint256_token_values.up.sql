@@ -0,0 +1,17 @@
+-- token amounts and IDs are uint256, compared and summed as numbers
+ALTER TABLE t_token_transfers
+MODIFY COLUMN f_value UInt256;
+
+ALTER TABLE t_token_approvals
+MODIFY COLUMN f_value UInt256;
+
+-- the columns of a view are fixed when created
+CREATE OR REPLACE VIEW v_token_transfers AS
+SELECT *
+FROM t_token_transfers
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
+
+CREATE OR REPLACE VIEW v_token_approvals AS
+SELECT *
+FROM t_token_approvals
+WHERE (f_slot, f_block_root) IN (SELECT f_slot, f_block_root FROM v_block_metrics);
@@ -0,0 +1,168 @@
+package db
+
+import (
+	"encoding/hex"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+// The content of orphaned blocks is written with the same columns as the canonical
+// tables, into tables of their own so that it never mixes with the canonical chain.
+var (
+	orphanedTransactionsTable   = "t_orphaned_transactions"
+	orphanedWithdrawalsTable    = "t_orphaned_withdrawals"
+	orphanedBlobsTable          = "t_orphaned_blob_sidecars"
+	orphanedAttestationsTable   = "t_orphaned_attestations"
+	orphanedAuthorizationsTable = "t_orphaned_transaction_authorizations"
+
+	insertOrphanedAttestationsQuery = `
+	INSERT INTO %s (
+		f_slot,
+		f_block_root,
+		f_index,
+		f_attestation_slot,
+		f_committee_indices,
+		f_beacon_block_root,
+		f_source_epoch,
+		f_target_epoch,
+		f_target_root,
+		f_aggregation_bits,
+		f_attesting_bits)
+		VALUES`
+)
+
+func orphanedAttestationsInput(attestations []spec.AgnosticAttestation) proto.Input {
+	// one object per column
+	var (
+		f_slot              proto.ColUInt64
+		f_block_root        proto.ColStr
+		f_index             proto.ColUInt64
+		f_attestation_slot  proto.ColUInt64
+		f_committee_indices = new(proto.ColUInt64).Array()
+		f_beacon_block_root proto.ColStr
+		f_source_epoch      proto.ColUInt64
+		f_target_epoch      proto.ColUInt64
+		f_target_root       proto.ColStr
+		f_aggregation_bits  proto.ColStr
+		f_attesting_bits    proto.ColUInt64
+	)
+
+	for _, attestation := range attestations {
+		f_slot.Append(uint64(attestation.Slot))
+		f_block_root.Append(attestation.BlockRoot.String())
+		f_index.Append(attestation.Index)
+		f_attestation_slot.Append(uint64(attestation.AttestationSlot))
+		f_committee_indices.Append(attestation.CommitteeIndices)
+		f_beacon_block_root.Append(attestation.BeaconBlockRoot.String())
+		f_source_epoch.Append(uint64(attestation.SourceEpoch))
+		f_target_epoch.Append(uint64(attestation.TargetEpoch))
+		f_target_root.Append(attestation.TargetRoot.String())
+		f_aggregation_bits.Append("0x" + hex.EncodeToString(attestation.AggregationBits))
+		f_attesting_bits.Append(attestation.AttestingBits)
+	}
+
+	return proto.Input{
+		{Name: "f_slot", Data: f_slot},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_index", Data: f_index},
+		{Name: "f_attestation_slot", Data: f_attestation_slot},
+		{Name: "f_committee_indices", Data: f_committee_indices},
+		{Name: "f_beacon_block_root", Data: f_beacon_block_root},
+		{Name: "f_source_epoch", Data: f_source_epoch},
+		{Name: "f_target_epoch", Data: f_target_epoch},
+		{Name: "f_target_root", Data: f_target_root},
+		{Name: "f_aggregation_bits", Data: f_aggregation_bits},
+		{Name: "f_attesting_bits", Data: f_attesting_bits},
+	}
+}
+
+func (p *DBService) PersistOrphanedTransactions(data []spec.AgnosticTransaction) error {
+	persistObj := PersistableObject[spec.AgnosticTransaction]{
+		input: transactionsInput,
+		table: orphanedTransactionsTable,
+		query: insertTransactionsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting orphaned transactions: %s", err.Error())
+	}
+	return err
+}
+
+func (p *DBService) PersistOrphanedAuthorizations(data []spec.AgnosticAuthorization) error {
+	persistObj := PersistableObject[spec.AgnosticAuthorization]{
+		input: authorizationsInput,
+		table: orphanedAuthorizationsTable,
+		query: insertAuthorizationsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting orphaned transaction authorizations: %s", err.Error())
+	}
+	return err
+}
+
+func (p *DBService) PersistOrphanedWithdrawals(data []spec.Withdrawal) error {
+	persistObj := PersistableObject[spec.Withdrawal]{
+		input: withdrawalsInput,
+		table: orphanedWithdrawalsTable,
+		query: insertWithdrawalsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting orphaned withdrawals: %s", err.Error())
+	}
+	return err
+}
+
+func (p *DBService) PersistOrphanedBlobSidecars(data []*spec.AgnosticBlobSidecar) error {
+	persistObj := PersistableObject[spec.AgnosticBlobSidecar]{
+		input: blobSidecarsInput,
+		table: orphanedBlobsTable,
+		query: insertBlobSidecarsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(*item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting orphaned blob sidecars: %s", err.Error())
+	}
+	return err
+}
+
+func (p *DBService) PersistOrphanedAttestations(data []spec.AgnosticAttestation) error {
+	persistObj := PersistableObject[spec.AgnosticAttestation]{
+		input: orphanedAttestationsInput,
+		table: orphanedAttestationsTable,
+		query: insertOrphanedAttestationsQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting orphaned attestations: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,454 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	partitionedSuffix = "_partitioned"
+	partitionSeconds  = 30 * 24 * 60 * 60 // time spanned by a partition
+)
+
+// partitionScheme describes the partitioned version of a table.
+type partitionScheme struct {
+	column    string // slot or epoch column the partitions are built on
+	unit      string // retentionBySlot or retentionByEpoch
+	timestamp string // column with the time of a row used by the TTL, otherwise derived from the genesis
+	orderBy   string
+	version   string // column ReplacingMergeTree keeps the highest of, empty keeps the last inserted row
+}
+
+var partitionSchemes = map[string]partitionScheme{
+	valRewardsTable: {
+		column:  "f_epoch",
+		unit:    retentionByEpoch,
+		orderBy: "f_epoch, f_val_idx",
+		version: "f_version",
+	},
+	transactionsTable: {
+		column:    "f_slot",
+		unit:      retentionBySlot,
+		timestamp: "f_timestamp",
+		orderBy:   "f_slot, f_el_block_number, f_hash",
+	},
+	blocksTable: {
+		column:    "f_slot",
+		unit:      retentionBySlot,
+		timestamp: "f_timestamp",
+		orderBy:   "f_slot",
+		version:   "f_version",
+	},
+	epochsTable: {
+		column:  "f_epoch",
+		unit:    retentionByEpoch,
+		orderBy: "f_epoch",
+		version: "f_version",
+	},
+	proposerDutiesTable: {
+		column:  "f_proposer_slot",
+		unit:    retentionBySlot,
+		orderBy: "f_proposer_slot, f_val_idx",
+		version: "f_version",
+	},
+}
+
+// unitSeconds returns the seconds spanned by a value of the partition column.
+func (s partitionScheme) unitSeconds(secondsPerSlot uint64, slotsPerEpoch uint64) uint64 {
+	if s.unit == retentionByEpoch {
+		return secondsPerSlot * slotsPerEpoch
+	}
+	return secondsPerSlot
+}
+
+// size returns the values of the partition column per partition, e.g. 6750 epochs
+// or 216000 slots with 12 second slots.
+func (s partitionScheme) size(secondsPerSlot uint64, slotsPerEpoch uint64) uint64 {
+	return partitionSeconds / s.unitSeconds(secondsPerSlot, slotsPerEpoch)
+}
+
+// time returns the expression giving the time of a row, used by the TTL.
+func (s partitionScheme) time(genesis int64, secondsPerSlot uint64, slotsPerEpoch uint64) string {
+	if s.timestamp != "" {
+		return fmt.Sprintf("toDateTime(%s)", s.timestamp)
+	}
+	return fmt.Sprintf("toDateTime(%d + %s * %d)", genesis, s.column, s.unitSeconds(secondsPerSlot, slotsPerEpoch))
+}
+
+func (s partitionScheme) engine() string {
+	return fmt.Sprintf("ReplacingMergeTree(%s)", s.version)
+}
+
+// PartitionedTables returns the tables that can be moved to a partitioned version.
+func PartitionedTables() []string {
+	tables := make([]string, 0, len(partitionSchemes))
+	for table := range partitionSchemes {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+var (
+	selectColumnsQuery = `
+		SELECT name, type, default_kind, default_expression
+		FROM system.columns
+		WHERE database = currentDatabase() AND table = '%s'
+		ORDER BY position`
+
+	selectPartitionRangeQuery = `
+		SELECT
+			toUInt64(intDiv(min(%[1]s), %[2]d)) AS f_first,
+			toUInt64(intDiv(max(%[1]s), %[2]d)) AS f_last,
+			count() AS f_rows
+		FROM %[3]s`
+
+	createPartitionedTableQuery = `
+		CREATE TABLE IF NOT EXISTS %[1]s AS %[2]s
+		ENGINE = %[3]s
+		PARTITION BY intDiv(%[4]s, %[5]d)
+		ORDER BY (%[6]s)%[7]s`
+
+	selectTableRowsQuery = `
+		SELECT count() AS f_rows
+		FROM %s`
+
+	selectPartitionRowsQuery = `
+		SELECT count() AS f_rows
+		FROM %s
+		WHERE intDiv(%s, %d) = %d`
+
+	copyPartitionQuery = `
+		INSERT INTO %[1]s (%[2]s)
+		SELECT %[2]s
+		FROM %[3]s
+		WHERE intDiv(%[4]s, %[5]d) = %[6]d`
+
+	addColumnQuery = `
+		ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s`
+
+	modifyColumnQuery = `
+		ALTER TABLE %s MODIFY COLUMN %s %s`
+
+	exchangeTablesQuery = `
+		EXCHANGE TABLES %s AND %s`
+
+	dropTableQuery = `
+		DROP TABLE IF EXISTS %s`
+
+	modifyTTLQuery = `
+		ALTER TABLE %s MODIFY TTL %s + INTERVAL %d DAY`
+
+	removeTTLQuery = `
+		ALTER TABLE %s REMOVE TTL`
+)
+
+func partitionSchemeOf(table string) (partitionScheme, error) {
+	scheme, ok := partitionSchemes[table]
+	if !ok {
+		return scheme, fmt.Errorf("table %s has no partitioned version, supported: %s",
+			table, strings.Join(PartitionedTables(), ","))
+	}
+	return scheme, nil
+}
+
+func (p *DBService) partitionSize(scheme partitionScheme) uint64 {
+	return scheme.size(p.secondsPerSlot, p.slotsPerEpoch)
+}
+
+func (p *DBService) exec(query string) error {
+	p.highMu.Lock()
+	defer p.highMu.Unlock()
+	return p.highLevelClient.Exec(p.ctx, query)
+}
+
+type tableInfo struct {
+	F_partition_key string `ch:"partition_key"`
+	F_engine_full   string `ch:"engine_full"`
+}
+
+// matches tells whether the table has a partition key and, for versioned tables,
+// keeps the highest version.
+func (t tableInfo) matches(scheme partitionScheme) bool {
+	if t.F_partition_key == "" {
+		return false
+	}
+	return scheme.version == "" || strings.HasPrefix(t.F_engine_full, scheme.engine())
+}
+
+// ttl returns the TTL clause of the table, if any.
+func (t tableInfo) ttl() string {
+	_, ttl, found := strings.Cut(t.F_engine_full, " TTL ")
+	if !found {
+		return ""
+	}
+	ttl, _, _ = strings.Cut(ttl, " SETTINGS ")
+	return " TTL " + ttl
+}
+
+func (p *DBService) tableInfo(table string) (tableInfo, bool, error) {
+	var info []tableInfo
+	err := p.highSelect(fmt.Sprintf(selectTableInfoQuery, table), &info)
+	if err != nil || len(info) == 0 {
+		return tableInfo{}, false, err
+	}
+	return info[0], true, nil
+}
+
+func (p *DBService) tableRows(table string) (uint64, error) {
+	var dest []struct {
+		F_rows uint64 `ch:"f_rows"`
+	}
+	err := p.highSelect(fmt.Sprintf(selectTableRowsQuery, table), &dest)
+	if err != nil || len(dest) == 0 {
+		return 0, err
+	}
+	return dest[0].F_rows, nil
+}
+
+// IsPartitioned tells whether the table already has the partitions and engine of its partitioned version.
+func (p *DBService) IsPartitioned(table string) (bool, error) {
+	scheme, err := partitionSchemeOf(table)
+	if err != nil {
+		return false, err
+	}
+	info, found, err := p.tableInfo(table)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, fmt.Errorf("table %s not found", table)
+	}
+	return info.matches(scheme), nil
+}
+
+// PreparePartitionedTable creates the partitioned version of the table, keeping its TTL.
+// A partitioned version left by a previous run is reused if it has the expected engine,
+// otherwise it is created again: a partial copy of the rows is simply dropped, while the
+// original rows of a previous exchange are only dropped if dropOld is set.
+func (p *DBService) PreparePartitionedTable(table string, dropOld bool) error {
+	scheme, err := partitionSchemeOf(table)
+	if err != nil {
+		return err
+	}
+	info, found, err := p.tableInfo(table)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("table %s not found", table)
+	}
+
+	partitionedTable := table + partitionedSuffix
+	dest, found, err := p.tableInfo(partitionedTable)
+	if err != nil {
+		return err
+	}
+	if found && dest.matches(scheme) {
+		return nil
+	}
+	if found {
+		rows, err := p.tableRows(partitionedTable)
+		if err != nil {
+			return err
+		}
+		// the table itself being partitioned means it was already exchanged once
+		if rows > 0 && info.F_partition_key != "" && !dropOld {
+			return fmt.Errorf("%s holds the original rows of a previous exchange, drop them with --drop-old", partitionedTable)
+		}
+		if err := p.exec(fmt.Sprintf(dropTableQuery, partitionedTable)); err != nil {
+			return err
+		}
+		log.Infof("dropped %s, created again with %s", partitionedTable, scheme.engine())
+	}
+	return p.exec(fmt.Sprintf(createPartitionedTableQuery,
+		partitionedTable, table, scheme.engine(), scheme.column, p.partitionSize(scheme), scheme.orderBy, info.ttl()))
+}
+
+type tableColumn struct {
+	F_name               string `ch:"name"`
+	F_type               string `ch:"type"`
+	F_default_kind       string `ch:"default_kind"`
+	F_default_expression string `ch:"default_expression"`
+}
+
+func (p *DBService) tableColumns(table string) ([]tableColumn, error) {
+	var columns []tableColumn
+	err := p.highSelect(fmt.Sprintf(selectColumnsQuery, table), &columns)
+	return columns, err
+}
+
+// syncColumns adds to the destination table the columns of the source table it lacks and
+// aligns the types of the ones that changed, as migrations after the partitioned tables
+// were created only alter the original ones.
+// Returns the columns to copy.
+func (p *DBService) syncColumns(from string, to string) ([]string, error) {
+	columns, err := p.tableColumns(from)
+	if err != nil {
+		return nil, err
+	}
+	destColumns, err := p.tableColumns(to)
+	if err != nil {
+		return nil, err
+	}
+	if len(destColumns) == 0 {
+		return nil, fmt.Errorf("table %s not found, are the migrations applied?", to)
+	}
+	existing := make(map[string]string, len(destColumns))
+	for _, column := range destColumns {
+		existing[column.F_name] = column.F_type
+	}
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, column.F_name)
+		destType, ok := existing[column.F_name]
+		if ok && destType == column.F_type {
+			continue
+		}
+		if ok {
+			// the column was widened in the source table after the copy was created
+			err := p.exec(fmt.Sprintf(modifyColumnQuery, to, column.F_name, column.F_type))
+			if err != nil {
+				return nil, fmt.Errorf("could not modify column %s of %s: %w", column.F_name, to, err)
+			}
+			log.Infof("modified column %s of %s to %s", column.F_name, to, column.F_type)
+			continue
+		}
+		definition := column.F_type
+		if column.F_default_kind != "" {
+			definition = fmt.Sprintf("%s %s %s", definition, column.F_default_kind, column.F_default_expression)
+		}
+		err := p.exec(fmt.Sprintf(addColumnQuery, to, column.F_name, definition))
+		if err != nil {
+			return nil, fmt.Errorf("could not add column %s to %s: %w", column.F_name, to, err)
+		}
+		log.Infof("added column %s to %s", column.F_name, to)
+	}
+	return names, nil
+}
+
+// PartitionRange returns the first and last partitions the rows of the table fall in,
+// and the number of rows of the table.
+func (p *DBService) PartitionRange(table string) (uint64, uint64, uint64, error) {
+	scheme, err := partitionSchemeOf(table)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	var dest []struct {
+		F_first uint64 `ch:"f_first"`
+		F_last  uint64 `ch:"f_last"`
+		F_rows  uint64 `ch:"f_rows"`
+	}
+	err = p.highSelect(fmt.Sprintf(selectPartitionRangeQuery, scheme.column, p.partitionSize(scheme), table), &dest)
+	if err != nil || len(dest) == 0 {
+		return 0, 0, 0, err
+	}
+	return dest[0].F_first, dest[0].F_last, dest[0].F_rows, nil
+}
+
+func (p *DBService) partitionRows(table string, scheme partitionScheme, partition uint64) (uint64, error) {
+	var dest []struct {
+		F_rows uint64 `ch:"f_rows"`
+	}
+	err := p.highSelect(fmt.Sprintf(selectPartitionRowsQuery, table, scheme.column, p.partitionSize(scheme), partition), &dest)
+	if err != nil || len(dest) == 0 {
+		return 0, err
+	}
+	return dest[0].F_rows, nil
+}
+
+func (p *DBService) copyPartition(table string, from string, to string, partition uint64, force bool) (bool, error) {
+	scheme, err := partitionSchemeOf(table)
+	if err != nil {
+		return false, err
+	}
+
+	if !force {
+		rows, err := p.partitionRows(from, scheme, partition)
+		if err != nil {
+			return false, err
+		}
+		copied, err := p.partitionRows(to, scheme, partition)
+		if err != nil {
+			return false, err
+		}
+		if copied >= rows {
+			return false, nil
+		}
+	}
+
+	columns, err := p.syncColumns(from, to)
+	if err != nil {
+		return false, err
+	}
+	startTime := time.Now()
+	err = p.exec(fmt.Sprintf(copyPartitionQuery,
+		to, strings.Join(columns, ", "), from, scheme.column, p.partitionSize(scheme), partition))
+	if err != nil {
+		return false, err
+	}
+	log.Infof("copied partition %d from %s to %s in %f seconds", partition, from, to, time.Since(startTime).Seconds())
+	return true, nil
+}
+
+// CopyPartition copies the rows of a single partition into the partitioned version of the table.
+// A partition that already has as many rows in the partitioned table is skipped unless force is set,
+// which makes the copy resumable. Copying twice is harmless as rows are collapsed by ReplacingMergeTree.
+func (p *DBService) CopyPartition(table string, partition uint64, force bool) (bool, error) {
+	return p.copyPartition(table, table, table+partitionedSuffix, partition, force)
+}
+
+// CatchUpPartition copies a partition back from the original rows of a swapped table,
+// to recover the rows written while the tables were being exchanged.
+func (p *DBService) CatchUpPartition(table string, partition uint64) error {
+	_, err := p.copyPartition(table, table+partitionedSuffix, table, partition, true)
+	return err
+}
+
+// SwapPartitionedTable atomically exchanges the table and its partitioned version,
+// after which the original rows are kept under the partitioned name.
+func (p *DBService) SwapPartitionedTable(table string) error {
+	return p.exec(fmt.Sprintf(exchangeTablesQuery, table, table+partitionedSuffix))
+}
+
+// DropUnpartitionedTable drops the original rows of a swapped table, if still there.
+func (p *DBService) DropUnpartitionedTable(table string) error {
+	columns, err := p.tableColumns(table + partitionedSuffix)
+	if err != nil || len(columns) == 0 {
+		return err
+	}
+	partitioned, err := p.IsPartitioned(table)
+	if err != nil {
+		return err
+	}
+	if !partitioned {
+		return fmt.Errorf("%s was not swapped yet", table)
+	}
+	return p.exec(fmt.Sprintf(dropTableQuery, table+partitionedSuffix))
+}
+
+// SetTableTTL makes ClickHouse expire the rows of the table older than the given days,
+// 0 removes the TTL.
+func (p *DBService) SetTableTTL(table string, days int) error {
+	scheme, err := partitionSchemeOf(table)
+	if err != nil {
+		return err
+	}
+	if days == 0 {
+		return p.exec(fmt.Sprintf(removeTTLQuery, table))
+	}
+	var genesis int64
+	if scheme.timestamp == "" {
+		genesis, err = p.RetrieveGenesis()
+		if err != nil {
+			return err
+		}
+		if genesis == 0 {
+			return fmt.Errorf("genesis time not found in the database")
+		}
+	}
+	timeExpr := scheme.time(genesis, p.secondsPerSlot, p.slotsPerEpoch)
+	return p.exec(fmt.Sprintf(modifyTTLQuery, table, timeExpr, days))
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableInfoMatches(t *testing.T) {
+	versioned := partitionSchemes[valRewardsTable]
+	unversioned := partitionSchemes[transactionsTable]
+
+	migrated := tableInfo{
+		F_partition_key: "intDiv(f_epoch, 6750)",
+		F_engine_full:   "ReplacingMergeTree(f_version) PARTITION BY intDiv(f_epoch, 6750) ORDER BY (f_epoch, f_val_idx) SETTINGS index_granularity = 8192",
+	}
+	assert.True(t, migrated.matches(versioned))
+
+	// partitioned by the migrations, before the engine kept the highest version
+	lastInserted := tableInfo{
+		F_partition_key: "intDiv(f_epoch, 6750)",
+		F_engine_full:   "ReplacingMergeTree PARTITION BY intDiv(f_epoch, 6750) ORDER BY (f_epoch, f_val_idx) SETTINGS index_granularity = 8192",
+	}
+	assert.False(t, lastInserted.matches(versioned))
+	assert.True(t, lastInserted.matches(unversioned))
+
+	unpartitioned := tableInfo{
+		F_engine_full: "ReplacingMergeTree(f_version) ORDER BY (f_epoch, f_val_idx) SETTINGS index_granularity = 8192",
+	}
+	assert.False(t, unpartitioned.matches(versioned))
+}
+
+func TestTableInfoTTL(t *testing.T) {
+	info := tableInfo{
+		F_engine_full: "ReplacingMergeTree PARTITION BY intDiv(f_slot, 216000) ORDER BY (f_slot, f_el_block_number, f_hash) TTL toDateTime(f_timestamp) + toIntervalDay(90) SETTINGS index_granularity = 8192",
+	}
+	assert.Equal(t, " TTL toDateTime(f_timestamp) + toIntervalDay(90)", info.ttl())
+
+	info.F_engine_full = "ReplacingMergeTree ORDER BY (f_slot, f_el_block_number, f_hash) SETTINGS index_granularity = 8192"
+	assert.Equal(t, "", info.ttl())
+}
+
+func TestPartitionSchemeTimes(t *testing.T) {
+	valRewards := partitionSchemes[valRewardsTable]
+	duties := partitionSchemes[proposerDutiesTable]
+	transactions := partitionSchemes[transactionsTable]
+
+	assert.Equal(t, uint64(6750), valRewards.size(12, 32))
+	assert.Equal(t, uint64(216000), duties.size(12, 32))
+	// 5 second slots and 16 slots per epoch
+	assert.Equal(t, uint64(32400), valRewards.size(5, 16))
+	assert.Equal(t, uint64(518400), duties.size(5, 16))
+
+	assert.Equal(t, "toDateTime(1606824023 + f_epoch * 384)", valRewards.time(1606824023, 12, 32))
+	assert.Equal(t, "toDateTime(1638993340 + f_epoch * 80)", valRewards.time(1638993340, 5, 16))
+	assert.Equal(t, "toDateTime(1638993340 + f_proposer_slot * 5)", duties.time(1638993340, 5, 16))
+	assert.Equal(t, "toDateTime(f_timestamp)", transactions.time(1638993340, 5, 16))
+}
@@ -25,6 +25,13 @@ var (
 		Help:      "Last slot processed with metrics",
 	})
 
+	LastFinalizedEpoch = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: strings.ToLower(utils.CliName),
+		Subsystem: modName,
+		Name:      "last_finalized_epoch",
+		Help:      "Last epoch whose rows were confirmed against the finalized chain",
+	})
+
 	// List of metrics that we are going to export
 	RowsPersisted = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -91,6 +98,7 @@ func (r *DBService) initMonitorMetrics() {
 		valLastStatusTable,
 		valRewardsTable,
 		valRewardsAggregationTable,
+		entityRewardsAggregationTable,
 		withdrawalsTable,
 		slashingsTable,
 		blsToExecutionChangeTable,
@@ -100,6 +108,27 @@ func (r *DBService) initMonitorMetrics() {
 		consolidationsProcessedTable,
 		withdrawalRequestsTable,
 		depositRequestsTable,
+		backfillLeasesTable,
+		finalityTransitionsTable,
+		reorgAnalysisTable,
+		orphanedTransactionsTable,
+		orphanedWithdrawalsTable,
+		orphanedBlobsTable,
+		orphanedAttestationsTable,
+		orphanedAuthorizationsTable,
+		blockComplianceTable,
+		complianceSummaryTable,
+		authorizationsTable,
+		logsTable,
+		decodedLogsTable,
+		tokenTransfersTable,
+		tokenApprovalsTable,
+		internalCallsTable,
+		rollupBlobUsageTable,
+		dataColumnsTable,
+		dataColumnEventsTable,
+		clientDiversityTable,
+		incompleteRewardsAggregationsTable,
 	}
 
 	for _, tableName := range tablesArr {
@@ -118,6 +147,7 @@ func (r *DBService) GetPrometheusMetrics() *metrics.MetricsModule {
 
 	metricsMod.AddIndvMetric(r.lastProcessedSlotMetric())
 	metricsMod.AddIndvMetric(r.lastProcessedEpochMetric())
+	metricsMod.AddIndvMetric(r.lastFinalizedEpochMetric())
 	return metricsMod
 }
 
@@ -185,6 +215,30 @@ func (r *DBService) lastProcessedEpochMetric() *metrics.IndvMetrics {
 	return lastEpoch
 }
 
+func (r *DBService) lastFinalizedEpochMetric() *metrics.IndvMetrics {
+	initFn := func() error {
+		prometheus.MustRegister(LastFinalizedEpoch)
+		return nil
+	}
+	updateFn := func() (interface{}, error) {
+		epoch, err := r.RetrieveLastFinalityTransition()
+		if err != nil {
+			return nil, err
+		}
+		LastFinalizedEpoch.Set(float64(epoch))
+		return epoch, nil
+	}
+	lastEpoch, err := metrics.NewIndvMetrics(
+		"last_finalized_epoch",
+		initFn,
+		updateFn,
+	)
+	if err != nil {
+		return nil
+	}
+	return lastEpoch
+}
+
 func (r *DBService) lastProcessedSlotMetric() *metrics.IndvMetrics {
 	initFn := func() error {
 		prometheus.MustRegister(LastProcessedSlot)
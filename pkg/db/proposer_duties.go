@@ -11,29 +11,32 @@ var (
 	INSERT INTO %s (
 		f_val_idx,
 		f_proposer_slot,
-		f_proposed)
+		f_proposed,
+		f_version,
+		f_finalized)
 		VALUES
 	`
 	// if there is a confilct the line already exists
-
-	deleteProposerDutiesQuery = `
-	DELETE FROM %s
-	WHERE f_proposer_slot/32 = $1;
-`
 )
 
-func proposerDutiesInput(duties []spec.ProposerDuty) proto.Input {
+func (p *DBService) proposerDutiesInput(duties []spec.ProposerDuty) proto.Input {
 	// one object per column
 	var (
 		f_val_idx       proto.ColUInt64
 		f_proposer_slot proto.ColUInt64
 		f_proposed      proto.ColBool
+		f_version       proto.ColUInt64
+		f_finalized     proto.ColBool
 	)
 
+	version := nextRowVersion()
+
 	for _, duty := range duties {
 		f_val_idx.Append(uint64(duty.ValIdx))
 		f_proposer_slot.Append(uint64(duty.ProposerSlot))
 		f_proposed.Append(duty.Proposed)
+		f_version.Append(version)
+		f_finalized.Append(p.isSlotFinalized(duty.ProposerSlot))
 	}
 
 	return proto.Input{
@@ -41,12 +44,14 @@ func proposerDutiesInput(duties []spec.ProposerDuty) proto.Input {
 		{Name: "f_val_idx", Data: f_val_idx},
 		{Name: "f_proposer_slot", Data: f_proposer_slot},
 		{Name: "f_proposed", Data: f_proposed},
+		{Name: "f_version", Data: f_version},
+		{Name: "f_finalized", Data: f_finalized},
 	}
 }
 
 func (p *DBService) PersistDuties(data []spec.ProposerDuty) error {
 	persistObj := PersistableObject[spec.ProposerDuty]{
-		input: proposerDutiesInput,
+		input: p.proposerDutiesInput,
 		table: proposerDutiesTable,
 		query: insertProposerDutiesQuery,
 	}
@@ -0,0 +1,144 @@
+package db
+
+import (
+	"time"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	reorgAnalysisTable       = "t_reorg_analysis"
+	insertReorgAnalysisQuery = `
+	INSERT INTO %s (
+		f_new_head_slot,
+		f_new_head_block_root,
+		f_old_head_slot,
+		f_old_head_block_root,
+		f_ancestor_slot,
+		f_ancestor_block_root,
+		f_depth_slots,
+		f_depth_blocks,
+		f_new_branch_blocks,
+		f_orphaned_slots,
+		f_orphaned_proposers,
+		f_dropped_attestations,
+		f_dropped_transactions,
+		f_dropped_tx_hashes,
+		f_mode,
+		f_timestamp)
+		VALUES`
+)
+
+// ReorgAnalysis describes a fork found because a block did not build on the previous one.
+// The old branch goes from the common ancestor to the previous head, the new branch
+// from the common ancestor to the block that revealed the fork.
+type ReorgAnalysis struct {
+	NewHeadSlot         phase0.Slot
+	NewHeadRoot         phase0.Root
+	OldHeadSlot         phase0.Slot
+	OldHeadRoot         phase0.Root
+	AncestorSlot        phase0.Slot
+	AncestorRoot        phase0.Root
+	DepthSlots          uint64 // slots from the common ancestor to the old head
+	DepthBlocks         uint64 // blocks of the old branch
+	NewBranchBlocks     uint64
+	OrphanedSlots       []phase0.Slot
+	OrphanedProposers   []phase0.ValidatorIndex
+	DroppedAttestations uint64          // attestations of the old branch not included by the new one
+	DroppedTxHashes     []phase0.Hash32 // transactions of the old branch not included by the new one
+	Mode                string          // download mode the fork was found in
+	Timestamp           time.Time
+}
+
+func reorgAnalysisInput(reorgs []ReorgAnalysis) proto.Input {
+	// one object per column
+	var (
+		f_new_head_slot        proto.ColUInt64
+		f_new_head_block_root  proto.ColStr
+		f_old_head_slot        proto.ColUInt64
+		f_old_head_block_root  proto.ColStr
+		f_ancestor_slot        proto.ColUInt64
+		f_ancestor_block_root  proto.ColStr
+		f_depth_slots          proto.ColUInt64
+		f_depth_blocks         proto.ColUInt64
+		f_new_branch_blocks    proto.ColUInt64
+		f_orphaned_slots       = new(proto.ColUInt64).Array()
+		f_orphaned_proposers   = new(proto.ColUInt64).Array()
+		f_dropped_attestations proto.ColUInt64
+		f_dropped_transactions proto.ColUInt64
+		f_dropped_tx_hashes    = new(proto.ColStr).Array()
+		f_mode                 proto.ColStr
+		f_timestamp            proto.ColUInt64
+	)
+
+	for _, reorg := range reorgs {
+		f_new_head_slot.Append(uint64(reorg.NewHeadSlot))
+		f_new_head_block_root.Append(reorg.NewHeadRoot.String())
+		f_old_head_slot.Append(uint64(reorg.OldHeadSlot))
+		f_old_head_block_root.Append(reorg.OldHeadRoot.String())
+		f_ancestor_slot.Append(uint64(reorg.AncestorSlot))
+		f_ancestor_block_root.Append(reorg.AncestorRoot.String())
+		f_depth_slots.Append(reorg.DepthSlots)
+		f_depth_blocks.Append(reorg.DepthBlocks)
+		f_new_branch_blocks.Append(reorg.NewBranchBlocks)
+
+		slots := make([]uint64, 0, len(reorg.OrphanedSlots))
+		for _, slot := range reorg.OrphanedSlots {
+			slots = append(slots, uint64(slot))
+		}
+		f_orphaned_slots.Append(slots)
+		proposers := make([]uint64, 0, len(reorg.OrphanedProposers))
+		for _, proposer := range reorg.OrphanedProposers {
+			proposers = append(proposers, uint64(proposer))
+		}
+		f_orphaned_proposers.Append(proposers)
+
+		f_dropped_attestations.Append(reorg.DroppedAttestations)
+		f_dropped_transactions.Append(uint64(len(reorg.DroppedTxHashes)))
+		hashes := make([]string, 0, len(reorg.DroppedTxHashes))
+		for _, hash := range reorg.DroppedTxHashes {
+			hashes = append(hashes, hash.String())
+		}
+		f_dropped_tx_hashes.Append(hashes)
+		f_mode.Append(reorg.Mode)
+		f_timestamp.Append(uint64(reorg.Timestamp.Unix()))
+	}
+
+	return proto.Input{
+		{Name: "f_new_head_slot", Data: f_new_head_slot},
+		{Name: "f_new_head_block_root", Data: f_new_head_block_root},
+		{Name: "f_old_head_slot", Data: f_old_head_slot},
+		{Name: "f_old_head_block_root", Data: f_old_head_block_root},
+		{Name: "f_ancestor_slot", Data: f_ancestor_slot},
+		{Name: "f_ancestor_block_root", Data: f_ancestor_block_root},
+		{Name: "f_depth_slots", Data: f_depth_slots},
+		{Name: "f_depth_blocks", Data: f_depth_blocks},
+		{Name: "f_new_branch_blocks", Data: f_new_branch_blocks},
+		{Name: "f_orphaned_slots", Data: f_orphaned_slots},
+		{Name: "f_orphaned_proposers", Data: f_orphaned_proposers},
+		{Name: "f_dropped_attestations", Data: f_dropped_attestations},
+		{Name: "f_dropped_transactions", Data: f_dropped_transactions},
+		{Name: "f_dropped_tx_hashes", Data: f_dropped_tx_hashes},
+		{Name: "f_mode", Data: f_mode},
+		{Name: "f_timestamp", Data: f_timestamp},
+	}
+}
+
+func (p *DBService) PersistReorgAnalysis(data []ReorgAnalysis) error {
+	persistObj := PersistableObject[ReorgAnalysis]{
+		input: reorgAnalysisInput,
+		table: reorgAnalysisTable,
+		query: insertReorgAnalysisQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting reorg analysis: %s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,289 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+const (
+	retentionBySlot        = "slot"
+	retentionByEpoch       = "epoch"
+	retentionByTimestampMs = "timestamp_ms"
+)
+
+// retentionColumn is the column that tells how old a row is.
+type retentionColumn struct {
+	name string
+	unit string
+}
+
+// tables not listed here (validator last status, genesis, pubkeys...) have no notion of age
+var retentionColumns = map[string]retentionColumn{
+	blocksTable:                        {"f_slot", retentionBySlot},
+	orphansTable:                       {"f_slot", retentionBySlot},
+	orphanedTransactionsTable:          {"f_slot", retentionBySlot},
+	orphanedWithdrawalsTable:           {"f_slot", retentionBySlot},
+	orphanedBlobsTable:                 {"f_slot", retentionBySlot},
+	orphanedAttestationsTable:          {"f_slot", retentionBySlot},
+	orphanedAuthorizationsTable:        {"f_slot", retentionBySlot},
+	transactionsTable:                  {"f_slot", retentionBySlot},
+	authorizationsTable:                {"f_slot", retentionBySlot},
+	logsTable:                          {"f_slot", retentionBySlot},
+	decodedLogsTable:                   {"f_slot", retentionBySlot},
+	tokenTransfersTable:                {"f_slot", retentionBySlot},
+	tokenApprovalsTable:                {"f_slot", retentionBySlot},
+	internalCallsTable:                 {"f_slot", retentionBySlot},
+	withdrawalsTable:                   {"f_slot", retentionBySlot},
+	blockRewardsTable:                  {"f_slot", retentionBySlot},
+	blobsTable:                         {"f_slot", retentionBySlot},
+	dataColumnsTable:                   {"f_slot", retentionBySlot},
+	headEventsTable:                    {"f_slot", retentionBySlot},
+	reorgsTable:                        {"f_slot", retentionBySlot},
+	reorgAnalysisTable:                 {"f_new_head_slot", retentionBySlot},
+	blockComplianceTable:               {"f_slot", retentionBySlot},
+	blsToExecutionChangeTable:          {"f_slot", retentionBySlot},
+	consolidationRequestsTable:         {"f_slot", retentionBySlot},
+	depositRequestsTable:               {"f_slot", retentionBySlot},
+	depositsTable:                      {"f_slot", retentionBySlot},
+	slashingsTable:                     {"f_slot", retentionBySlot},
+	withdrawalRequestsTable:            {"f_slot", retentionBySlot},
+	proposerDutiesTable:                {"f_proposer_slot", retentionBySlot},
+	valRewardsTable:                    {"f_epoch", retentionByEpoch},
+	epochsTable:                        {"f_epoch", retentionByEpoch},
+	poolsTables:                        {"f_epoch", retentionByEpoch},
+	consolidationsProcessedTable:       {"f_epoch", retentionByEpoch},
+	finalizedTable:                     {"f_epoch", retentionByEpoch},
+	finalityTransitionsTable:           {"f_epoch", retentionByEpoch},
+	complianceSummaryTable:             {"f_epoch", retentionByEpoch},
+	rollupBlobUsageTable:               {"f_epoch", retentionByEpoch},
+	clientDiversityTable:               {"f_epoch", retentionByEpoch},
+	valRewardsAggregationTable:         {"f_end_epoch", retentionByEpoch},
+	entityRewardsAggregationTable:      {"f_end_epoch", retentionByEpoch},
+	incompleteRewardsAggregationsTable: {"f_end_epoch", retentionByEpoch},
+	blobEventsTable:                    {"f_arrival_timestamp_ms", retentionByTimestampMs},
+	dataColumnEventsTable:              {"f_arrival_timestamp_ms", retentionByTimestampMs},
+}
+
+func retentionColumnOf(table string) (retentionColumn, bool) {
+	column, ok := retentionColumns[table]
+	return column, ok
+}
+
+// RetentionTables returns the tables a retention can be configured for.
+func RetentionTables() []string {
+	tables := make([]string, 0, len(retentionColumns))
+	for table := range retentionColumns {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// SupportsRetention tells whether rows of the table can be removed by age.
+func SupportsRetention(table string) bool {
+	_, ok := retentionColumnOf(table)
+	return ok
+}
+
+var (
+	selectTableInfoQuery = `
+		SELECT partition_key, engine_full
+		FROM system.tables
+		WHERE database = currentDatabase() AND name = '%s'`
+
+	selectTablePartsQuery = `
+		SELECT
+			partition_id,
+			toUInt64(sum(rows)) AS f_rows,
+			toUInt64(sum(bytes_on_disk)) AS f_bytes
+		FROM system.parts
+		WHERE database = currentDatabase() AND table = '%s' AND active
+		GROUP BY partition_id`
+
+	selectPartitionsMaxQuery = `
+		SELECT
+			_partition_id AS f_partition_id,
+			toUInt64(max(%s)) AS f_max
+		FROM %s
+		GROUP BY _partition_id`
+
+	selectRowsBelowQuery = `
+		SELECT count() AS f_rows
+		FROM %s
+		WHERE %s < %d`
+
+	dropPartitionQuery = `
+		ALTER TABLE %s DROP PARTITION ID '%s'`
+
+	deleteRowsBelowQuery = `
+		DELETE FROM %%s
+		WHERE %s < $1;`
+)
+
+// RetentionPlan describes what applying a retention to a table removes.
+type RetentionPlan struct {
+	Table          string
+	Column         string
+	Boundary       uint64   // rows with Column below the boundary are removed
+	TTL            bool     // the table expires its rows by itself, nothing to do
+	Rows           uint64   // rows to be removed
+	TotalRows      uint64   // rows in the table
+	TotalBytes     uint64   // bytes on disk of the table
+	Partitions     []string // partitions entirely below the boundary, dropped as a whole
+	PartitionRows  uint64   // rows in those partitions
+	PartitionBytes uint64   // bytes on disk of those partitions
+}
+
+// EstimatedBytes returns the space freed: the size of the dropped partitions, plus the rows
+// deleted from other partitions at the average row size of the table.
+func (r RetentionPlan) EstimatedBytes() uint64 {
+	if r.TTL || r.TotalRows == 0 {
+		return 0
+	}
+	deletedRows := r.Rows - min(r.Rows, r.PartitionRows)
+	return r.PartitionBytes + deletedRows*r.TotalBytes/r.TotalRows
+}
+
+func (r RetentionPlan) String() string {
+	if r.TTL {
+		return fmt.Sprintf("%s: rows expired by the table TTL", r.Table)
+	}
+	return fmt.Sprintf("%s: %d of %d rows with %s < %d, %d partitions dropped, ~%.2f MB freed",
+		r.Table, r.Rows, r.TotalRows, r.Column, r.Boundary, len(r.Partitions), float64(r.EstimatedBytes())/(1<<20))
+}
+
+// retentionBoundary converts the last epoch to remove into the first value of the column to keep.
+// Timestamps are derived from the genesis time and the slot duration of the network.
+func (p *DBService) retentionBoundary(column retentionColumn, untilEpoch phase0.Epoch, secondsPerSlot uint64) (uint64, error) {
+	switch column.unit {
+	case retentionBySlot:
+		return uint64(spec.ComputeStartSlotAtEpoch(untilEpoch + 1)), nil
+	case retentionByEpoch:
+		return uint64(untilEpoch + 1), nil
+	case retentionByTimestampMs:
+		genesis, err := p.RetrieveGenesis()
+		if err != nil {
+			return 0, err
+		}
+		if genesis == 0 {
+			return 0, fmt.Errorf("genesis time not found in the database")
+		}
+		seconds := uint64(genesis) + uint64(spec.ComputeStartSlotAtEpoch(untilEpoch+1))*secondsPerSlot
+		return seconds * 1000, nil
+	}
+	return 0, fmt.Errorf("unknown retention unit %s", column.unit)
+}
+
+// PlanRetention computes which rows of the table up to untilEpoch (included) would be removed.
+func (p *DBService) PlanRetention(table string, untilEpoch phase0.Epoch, secondsPerSlot uint64) (RetentionPlan, error) {
+	column, ok := retentionColumnOf(table)
+	if !ok {
+		return RetentionPlan{}, fmt.Errorf("table %s does not support retention", table)
+	}
+	boundary, err := p.retentionBoundary(column, untilEpoch, secondsPerSlot)
+	if err != nil {
+		return RetentionPlan{}, err
+	}
+	plan := RetentionPlan{
+		Table:      table,
+		Column:     column.name,
+		Boundary:   boundary,
+		Partitions: make([]string, 0),
+	}
+
+	var info []struct {
+		F_partition_key string `ch:"partition_key"`
+		F_engine_full   string `ch:"engine_full"`
+	}
+	err = p.highSelect(fmt.Sprintf(selectTableInfoQuery, table), &info)
+	if err != nil {
+		return plan, err
+	}
+	if len(info) == 0 {
+		return plan, fmt.Errorf("table %s not found", table)
+	}
+	if strings.Contains(info[0].F_engine_full, " TTL ") {
+		plan.TTL = true
+		return plan, nil
+	}
+
+	var parts []struct {
+		F_partition_id string `ch:"partition_id"`
+		F_rows         uint64 `ch:"f_rows"`
+		F_bytes        uint64 `ch:"f_bytes"`
+	}
+	err = p.highSelect(fmt.Sprintf(selectTablePartsQuery, table), &parts)
+	if err != nil {
+		return plan, err
+	}
+	for _, part := range parts {
+		plan.TotalRows += part.F_rows
+		plan.TotalBytes += part.F_bytes
+	}
+
+	if info[0].F_partition_key != "" {
+		var partitionsMax []struct {
+			F_partition_id string `ch:"f_partition_id"`
+			F_max          uint64 `ch:"f_max"`
+		}
+		err = p.highSelect(fmt.Sprintf(selectPartitionsMaxQuery, column.name, table), &partitionsMax)
+		if err != nil {
+			return plan, err
+		}
+		droppable := make(map[string]bool)
+		for _, partition := range partitionsMax {
+			if partition.F_max < boundary {
+				droppable[partition.F_partition_id] = true
+			}
+		}
+		for _, part := range parts {
+			if droppable[part.F_partition_id] {
+				plan.Partitions = append(plan.Partitions, part.F_partition_id)
+				plan.PartitionRows += part.F_rows
+				plan.PartitionBytes += part.F_bytes
+			}
+		}
+		sort.Strings(plan.Partitions)
+	}
+
+	var rows []struct {
+		F_rows uint64 `ch:"f_rows"`
+	}
+	err = p.highSelect(fmt.Sprintf(selectRowsBelowQuery, table, column.name, boundary), &rows)
+	if err != nil {
+		return plan, err
+	}
+	if len(rows) > 0 {
+		plan.Rows = rows[0].F_rows
+	}
+	return plan, nil
+}
+
+// ApplyRetention drops the partitions of the plan and deletes the remaining rows below the boundary.
+func (p *DBService) ApplyRetention(plan RetentionPlan) error {
+	if plan.TTL {
+		return nil
+	}
+	for _, partition := range plan.Partitions {
+		p.highMu.Lock()
+		err := p.highLevelClient.Exec(p.ctx, fmt.Sprintf(dropPartitionQuery, plan.Table, partition))
+		p.highMu.Unlock()
+		if err != nil {
+			return fmt.Errorf("could not drop partition %s of %s: %w", partition, plan.Table, err)
+		}
+		log.Infof("dropped partition %s of %s", partition, plan.Table)
+	}
+
+	if plan.Rows <= plan.PartitionRows {
+		return nil
+	}
+	return p.Delete(DeletableObject{
+		query: fmt.Sprintf(deleteRowsBelowQuery, plan.Column),
+		table: plan.Table,
+		args:  []any{plan.Boundary},
+	})
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"math/big"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	rollupBlobUsageTable       = "t_rollup_blob_usage"
+	insertRollupBlobUsageQuery = `
+	INSERT INTO %s (
+		f_epoch,
+		f_rollup,
+		f_transactions,
+		f_blobs,
+		f_blob_gas_used,
+		f_blob_fees,
+		f_sidecar_blobs,
+		f_used_bytes,
+		f_fill_ratio)
+		VALUES`
+)
+
+// RollupBlobUsage aggregates the blob transactions a rollup posted during an epoch.
+type RollupBlobUsage struct {
+	Epoch        phase0.Epoch
+	Rollup       string
+	Transactions uint64
+	Blobs        uint64
+	BlobGasUsed  uint64
+	BlobFees     *big.Int // Wei, blob fees burnt
+	SidecarBlobs uint64   // blobs whose sidecar was downloaded, the ones the fill ratio is measured on
+	UsedBytes    uint64   // bytes of those blobs before their trailing zeros
+	FillRatio    float64
+}
+
+func rollupBlobUsageInput(usages []RollupBlobUsage) proto.Input {
+	// one object per column
+	var (
+		f_epoch         proto.ColUInt64
+		f_rollup        proto.ColStr
+		f_transactions  proto.ColUInt64
+		f_blobs         proto.ColUInt64
+		f_blob_gas_used proto.ColUInt64
+		f_blob_fees     proto.ColUInt256
+		f_sidecar_blobs proto.ColUInt64
+		f_used_bytes    proto.ColUInt64
+		f_fill_ratio    proto.ColFloat64
+	)
+
+	for _, usage := range usages {
+		f_epoch.Append(uint64(usage.Epoch))
+		f_rollup.Append(usage.Rollup)
+		f_transactions.Append(usage.Transactions)
+		f_blobs.Append(usage.Blobs)
+		f_blob_gas_used.Append(usage.BlobGasUsed)
+		f_blob_fees.Append(uint256FromBig(usage.BlobFees))
+		f_sidecar_blobs.Append(usage.SidecarBlobs)
+		f_used_bytes.Append(usage.UsedBytes)
+		f_fill_ratio.Append(usage.FillRatio)
+	}
+
+	return proto.Input{
+		{Name: "f_epoch", Data: f_epoch},
+		{Name: "f_rollup", Data: f_rollup},
+		{Name: "f_transactions", Data: f_transactions},
+		{Name: "f_blobs", Data: f_blobs},
+		{Name: "f_blob_gas_used", Data: f_blob_gas_used},
+		{Name: "f_blob_fees", Data: f_blob_fees},
+		{Name: "f_sidecar_blobs", Data: f_sidecar_blobs},
+		{Name: "f_used_bytes", Data: f_used_bytes},
+		{Name: "f_fill_ratio", Data: f_fill_ratio},
+	}
+}
+
+func (p *DBService) PersistRollupBlobUsage(data []RollupBlobUsage) error {
+	persistObj := PersistableObject[RollupBlobUsage]{
+		input: rollupBlobUsageInput,
+		table: rollupBlobUsageTable,
+		query: insertRollupBlobUsageQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting rollup blob usage: %s", err.Error())
+	}
+	return err
+}
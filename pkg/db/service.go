@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ClickHouse/ch-go"
@@ -39,6 +40,11 @@ type DBService struct {
 	lowMu          sync.Mutex
 	highMu         sync.Mutex
 	metricsMu      sync.RWMutex
+
+	finalizedEpoch atomic.Uint64 // rows of earlier epochs are persisted as finalized
+
+	secondsPerSlot uint64 // partition sizes and TTLs are computed from the slot and epoch durations
+	slotsPerEpoch  uint64
 }
 
 func New(ctx context.Context, url string, options ...DBServiceOption) (*DBService, error) {
@@ -47,6 +53,8 @@ func New(ctx context.Context, url string, options ...DBServiceOption) (*DBServic
 		ctx:            ctx,
 		connectionUrl:  url,
 		monitorMetrics: make(map[string]*DBMonitorMetrics),
+		secondsPerSlot: 12,
+		slotsPerEpoch:  spec.SlotsPerEpoch,
 	}
 
 	pService.initMonitorMetrics()
@@ -82,6 +90,18 @@ func WithUrl(url string) DBServiceOption {
 	}
 }
 
+// WithSlotTimes sets the slot duration and slots per epoch of the network, mainnet by default.
+func WithSlotTimes(secondsPerSlot uint64, slotsPerEpoch uint64) DBServiceOption {
+	return func(s *DBService) error {
+		if secondsPerSlot == 0 || slotsPerEpoch == 0 {
+			return fmt.Errorf("invalid slot times: %d seconds per slot, %d slots per epoch", secondsPerSlot, slotsPerEpoch)
+		}
+		s.secondsPerSlot = secondsPerSlot
+		s.slotsPerEpoch = slotsPerEpoch
+		return nil
+	}
+}
+
 func (p *DBService) Finish() {
 
 	p.lowLevelClient.Close()
@@ -154,7 +174,24 @@ type PersistableObject[
 		spec.ConsolidationRequest |
 		spec.ConsolidationProcessed |
 		spec.WithdrawalRequest |
-		spec.DepositRequest] struct {
+		spec.DepositRequest |
+		BackfillLease |
+		FinalityTransition |
+		ReorgAnalysis |
+		spec.AgnosticAttestation |
+		BlockCompliance |
+		ComplianceSummary |
+		spec.AgnosticAuthorization |
+		spec.AgnosticLog |
+		spec.DecodedLog |
+		spec.TokenTransfer |
+		spec.TokenApproval |
+		spec.InternalCall |
+		RollupBlobUsage |
+		ClientDiversity |
+		IncompleteRewardsAggregation |
+		spec.AgnosticDataColumnSidecar |
+		spec.DataColumnSidecarEventWrapper] struct {
 	table string
 	query string
 	data  []T
@@ -29,51 +29,60 @@ var (
 			f_blob_gas_used,
 			f_blob_gas_price,
 			f_blob_gas_limit,
-			f_blob_gas_fee_cap)
+			f_blob_gas_fee_cap,
+			f_block_root,
+			f_authorizations,
+			f_authorization_authorities,
+			f_authorization_addresses,
+			f_tip_fee,
+			f_burnt_fee,
+			f_blob_burnt_fee)
 		VALUES`
-
-	deleteTransactionsQuery = `
-		DELETE FROM %s
-		WHERE f_slot = $1;
-`
 )
 
 func transactionsInput(transactions []spec.AgnosticTransaction) proto.Input {
 	// one object per column
 	var (
-		f_tx_idx           proto.ColUInt64
-		f_tx_type          proto.ColUInt64
-		f_chain_id         proto.ColUInt64
-		f_data             proto.ColStr
-		f_gas              proto.ColUInt64
-		f_gas_price        proto.ColUInt64
-		f_gas_tip_cap      proto.ColUInt64
-		f_gas_fee_cap      proto.ColUInt64
-		f_value            proto.ColStr
-		f_nonce            proto.ColUInt64
-		f_to               proto.ColStr
-		f_hash             proto.ColStr
-		f_size             proto.ColUInt64
-		f_slot             proto.ColUInt64
-		f_el_block_number  proto.ColUInt64
-		f_timestamp        proto.ColUInt64
-		f_from             proto.ColStr
-		f_contract_address proto.ColStr
-		f_blob_gas_used    proto.ColUInt64
-		f_blob_gas_price   proto.ColUInt64
-		f_blob_gas_limit   proto.ColUInt64
-		f_blob_gas_fee_cap proto.ColUInt64
+		f_tx_idx                    proto.ColUInt64
+		f_tx_type                   proto.ColUInt64
+		f_chain_id                  proto.ColUInt64
+		f_data                      proto.ColStr
+		f_gas                       proto.ColUInt64
+		f_gas_price                 proto.ColUInt256
+		f_gas_tip_cap               proto.ColUInt256
+		f_gas_fee_cap               proto.ColUInt256
+		f_value                     proto.ColStr
+		f_nonce                     proto.ColUInt64
+		f_to                        proto.ColStr
+		f_hash                      proto.ColStr
+		f_size                      proto.ColUInt64
+		f_slot                      proto.ColUInt64
+		f_el_block_number           proto.ColUInt64
+		f_timestamp                 proto.ColUInt64
+		f_from                      proto.ColStr
+		f_contract_address          proto.ColStr
+		f_blob_gas_used             proto.ColUInt64
+		f_blob_gas_price            proto.ColUInt256
+		f_blob_gas_limit            proto.ColUInt64
+		f_blob_gas_fee_cap          proto.ColUInt256
+		f_block_root                proto.ColStr
+		f_authorizations            proto.ColUInt64
+		f_authorization_authorities = new(proto.ColStr).Array()
+		f_authorization_addresses   = new(proto.ColStr).Array()
+		f_tip_fee                   proto.ColUInt256
+		f_burnt_fee                 proto.ColUInt256
+		f_blob_burnt_fee            proto.ColUInt256
 	)
 
 	for _, transaction := range transactions {
 		f_tx_idx.Append(transaction.TxIdx)
 		f_tx_type.Append(uint64(transaction.TxType))
-		f_chain_id.Append(uint64(transaction.ChainId))
+		f_chain_id.Append(transaction.ChainId)
 		f_data.Append(transaction.Data)
 		f_gas.Append(uint64(transaction.Gas))
-		f_gas_price.Append(uint64(transaction.GasPrice))
-		f_gas_tip_cap.Append(uint64(transaction.GasTipCap))
-		f_gas_fee_cap.Append(uint64(transaction.GasFeeCap))
+		f_gas_price.Append(uint256FromBig(transaction.GasPrice))
+		f_gas_tip_cap.Append(uint256FromBig(transaction.GasTipCap))
+		f_gas_fee_cap.Append(uint256FromBig(transaction.GasFeeCap))
 		f_value.Append(transaction.Value.String())
 		f_nonce.Append(transaction.Nonce)
 		// to sometimes is empty or nil
@@ -91,9 +100,22 @@ func transactionsInput(transactions []spec.AgnosticTransaction) proto.Input {
 		f_contract_address.Append(transaction.ContractAddress.String())
 
 		f_blob_gas_used.Append(transaction.BlobGasUsed)
-		f_blob_gas_price.Append(transaction.BlobGasPrice)
+		f_blob_gas_price.Append(uint256FromBig(transaction.BlobGasPrice))
 		f_blob_gas_limit.Append(transaction.BlobGasLimit)
-		f_blob_gas_fee_cap.Append(transaction.BlobGasFeeCap)
+		f_blob_gas_fee_cap.Append(uint256FromBig(transaction.BlobGasFeeCap))
+		f_block_root.Append(transaction.BlockRoot.String())
+		f_authorizations.Append(uint64(len(transaction.Authorizations)))
+		authorities := make([]string, 0, len(transaction.Authorizations))
+		addresses := make([]string, 0, len(transaction.Authorizations))
+		for _, authorization := range transaction.Authorizations {
+			authorities = append(authorities, authorization.AuthorityString())
+			addresses = append(addresses, authorization.Address.String())
+		}
+		f_authorization_authorities.Append(authorities)
+		f_authorization_addresses.Append(addresses)
+		f_tip_fee.Append(uint256FromBig(transaction.Fees.Tip))
+		f_burnt_fee.Append(uint256FromBig(transaction.Fees.BurntFee))
+		f_blob_burnt_fee.Append(uint256FromBig(transaction.Fees.BlobBurntFee))
 	}
 
 	return proto.Input{
@@ -119,6 +141,13 @@ func transactionsInput(transactions []spec.AgnosticTransaction) proto.Input {
 		{Name: "f_blob_gas_price", Data: f_blob_gas_price},
 		{Name: "f_blob_gas_limit", Data: f_blob_gas_limit},
 		{Name: "f_blob_gas_fee_cap", Data: f_blob_gas_fee_cap},
+		{Name: "f_block_root", Data: f_block_root},
+		{Name: "f_authorizations", Data: f_authorizations},
+		{Name: "f_authorization_authorities", Data: f_authorization_authorities},
+		{Name: "f_authorization_addresses", Data: f_authorization_addresses},
+		{Name: "f_tip_fee", Data: f_tip_fee},
+		{Name: "f_burnt_fee", Data: f_burnt_fee},
+		{Name: "f_blob_burnt_fee", Data: f_blob_burnt_fee},
 	}
 }
 
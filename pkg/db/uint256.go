@@ -0,0 +1,33 @@
+package db
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ClickHouse/ch-go/proto"
+)
+
+var maxUInt256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// uint256FromBig converts a Wei or token amount for a UInt256 column. Nil and negative values
+// are stored as 0, values above 256 bits as the maximum.
+func uint256FromBig(v *big.Int) proto.UInt256 {
+	if v == nil || v.Sign() <= 0 {
+		return proto.UInt256{}
+	}
+	if v.Cmp(maxUInt256) > 0 {
+		v = maxUInt256
+	}
+	var b [32]byte
+	v.FillBytes(b[:])
+	return proto.UInt256{
+		Low: proto.UInt128{
+			Low:  binary.BigEndian.Uint64(b[24:32]),
+			High: binary.BigEndian.Uint64(b[16:24]),
+		},
+		High: proto.UInt128{
+			Low:  binary.BigEndian.Uint64(b[8:16]),
+			High: binary.BigEndian.Uint64(b[0:8]),
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUInt256FromBig(t *testing.T) {
+	assert.Equal(t, proto.UInt256{}, uint256FromBig(nil))
+	assert.Equal(t, proto.UInt256{}, uint256FromBig(big.NewInt(-1)))
+	assert.Equal(t, proto.UInt256FromUInt64(math.MaxUint64), uint256FromBig(new(big.Int).SetUint64(math.MaxUint64)))
+
+	// 2^64 + 2 and 2^192 + 3 spill over the first words
+	v := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(2))
+	assert.Equal(t, proto.UInt256{Low: proto.UInt128{Low: 2, High: 1}}, uint256FromBig(v))
+	v = new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 192), big.NewInt(3))
+	assert.Equal(t, proto.UInt256{Low: proto.UInt128{Low: 3}, High: proto.UInt128{High: 1}}, uint256FromBig(v))
+
+	max := proto.UInt256{
+		Low:  proto.UInt128{Low: math.MaxUint64, High: math.MaxUint64},
+		High: proto.UInt128{Low: math.MaxUint64, High: math.MaxUint64},
+	}
+	assert.Equal(t, max, uint256FromBig(maxUInt256))
+	assert.Equal(t, max, uint256FromBig(new(big.Int).Lsh(big.NewInt(1), 300)))
+}
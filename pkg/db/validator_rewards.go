@@ -31,12 +31,9 @@ var (
 		f_status,
 		f_block_api_reward,
 		f_block_experimental_reward,
-		f_inclusion_delay) VALUES`
-
-	deleteValidatorRewardsInEpochQuery = `
-		DELETE FROM %s
-		WHERE f_epoch = $1;
-	`
+		f_inclusion_delay,
+		f_version,
+		f_finalized) VALUES`
 
 	deleteValidatorRewardsUntilEpochQuery = `
 		DELETE FROM %s
@@ -44,7 +41,7 @@ var (
 	`
 )
 
-func rewardsInput(vals []spec.ValidatorRewards) proto.Input {
+func (p *DBService) rewardsInput(vals []spec.ValidatorRewards) proto.Input {
 	// one object per column
 	var (
 		f_val_idx                                proto.ColUInt64
@@ -68,8 +65,12 @@ func rewardsInput(vals []spec.ValidatorRewards) proto.Input {
 		f_block_api_reward                       proto.ColUInt64
 		f_block_experimental_reward              proto.ColUInt64
 		f_inclusion_delay                        proto.ColUInt8
+		f_version                                proto.ColUInt64
+		f_finalized                              proto.ColBool
 	)
 
+	version := nextRowVersion()
+
 	for _, val := range vals {
 		f_val_idx.Append(uint64(val.ValidatorIndex))
 		f_epoch.Append(uint64(val.Epoch))
@@ -92,6 +93,8 @@ func rewardsInput(vals []spec.ValidatorRewards) proto.Input {
 		f_block_api_reward.Append(uint64(val.ProposerApiReward))
 		f_block_experimental_reward.Append(uint64(val.ProposerManualReward))
 		f_inclusion_delay.Append(uint8(val.InclusionDelay))
+		f_version.Append(version)
+		f_finalized.Append(p.isFinalized(val.Epoch))
 	}
 
 	return proto.Input{
@@ -116,12 +119,14 @@ func rewardsInput(vals []spec.ValidatorRewards) proto.Input {
 		{Name: "f_block_api_reward", Data: f_block_api_reward},
 		{Name: "f_block_experimental_reward", Data: f_block_experimental_reward},
 		{Name: "f_inclusion_delay", Data: f_inclusion_delay},
+		{Name: "f_version", Data: f_version},
+		{Name: "f_finalized", Data: f_finalized},
 	}
 }
 
 func (p *DBService) PersistValidatorRewards(data []spec.ValidatorRewards) error {
 	persistObj := PersistableObject[spec.ValidatorRewards]{
-		input: rewardsInput,
+		input: p.rewardsInput,
 		table: valRewardsTable,
 		query: insertValidatorRewardsQuery,
 	}
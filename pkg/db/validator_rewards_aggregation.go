@@ -1,6 +1,8 @@
 package db
 
 import (
+	"fmt"
+
 	"github.com/ClickHouse/ch-go/proto"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 
@@ -29,6 +31,62 @@ var (
 		f_block_experimental_reward,
 		f_inclusion_delay_sum) VALUES`
 
+	// aliases differ from the column names, otherwise they would be substituted inside the aggregates
+	selectValidatorRewardsAggregationQuery = `
+		SELECT
+			f_val_idx,
+			toInt64(sum(f_reward)) AS f_reward_sum,
+			toUInt64(sum(f_max_reward)) AS f_max_reward_sum,
+			toUInt64(sum(f_max_att_reward)) AS f_max_att_reward_sum,
+			toUInt64(sum(f_max_sync_reward)) AS f_max_sync_reward_sum,
+			toUInt64(sum(f_base_reward)) AS f_base_reward_sum,
+			toUInt16(countIf(f_in_sync_committee)) AS f_in_sync_committee_count,
+			toUInt16(sum(f_sync_committee_participations_included)) AS f_sync_committee_participations_sum,
+			toUInt16(countIf(f_attestation_included)) AS f_attestations_included_count,
+			toUInt16(countIf(f_missing_source)) AS f_missing_source_count,
+			toUInt16(countIf(f_missing_target)) AS f_missing_target_count,
+			toUInt16(countIf(f_missing_head)) AS f_missing_head_count,
+			toUInt64(sum(f_block_api_reward)) AS f_block_api_reward_sum,
+			toUInt64(sum(f_block_experimental_reward)) AS f_block_experimental_reward_sum,
+			toUInt32(sum(f_inclusion_delay)) AS f_inclusion_delay_sum
+		FROM %s FINAL
+		WHERE f_epoch >= %d AND f_epoch < %d
+		GROUP BY f_val_idx`
+
+	selectValidatorRewardsEpochsQuery = `
+		SELECT DISTINCT f_epoch
+		FROM %s
+		WHERE f_epoch >= %d AND f_epoch < %d
+		ORDER BY f_epoch`
+
+	entityRewardsAggregationTable       = "t_entity_rewards_aggregation"
+	insertEntityRewardsAggregationQuery = `
+	INSERT INTO %s (
+		f_entity,
+		f_start_epoch,
+		f_end_epoch,
+		f_validators,
+		f_reward,
+		f_max_reward,
+		f_max_att_reward,
+		f_max_sync_reward,
+		f_base_reward,
+		f_in_sync_committee_count,
+		f_sync_committee_participations_included,
+		f_attestations_included,
+		f_missing_source_count,
+		f_missing_target_count,
+		f_missing_head_count,
+		f_block_api_reward,
+		f_block_experimental_reward,
+		f_inclusion_delay_sum) VALUES`
+
+	// entities are the pool names of t_eth2_pubkeys, same as t_pool_summary
+	selectValidatorPoolsQuery = `
+		SELECT f_val_idx, f_pool_name
+		FROM t_eth2_pubkeys FINAL
+		WHERE f_pool_name != ''`
+
 	deleteValidatorRewardsAggregationUntilEpochQuery = `
 		DELETE FROM %s
 		WHERE f_start_epoch <= $1;
@@ -66,12 +124,12 @@ func rewardsAggregationInput(vals []spec.ValidatorRewardsAggregation) proto.Inpu
 		f_max_att_reward.Append(uint64(val.MaxAttestationReward))
 		f_max_sync_reward.Append(uint64(val.MaxSyncCommitteeReward))
 		f_base_reward.Append(uint64(val.BaseReward))
-		f_in_sync_committee_count.Append(val.InSyncCommitteeCount)
-		f_sync_committee_participations_included.Append(val.SyncCommitteeParticipationsIncluded)
-		f_attestations_included.Append(val.AttestationsIncluded)
-		f_missing_source_count.Append(val.MissingSourceCount)
-		f_missing_target_count.Append(val.MissingTargetCount)
-		f_missing_head_count.Append(val.MissingHeadCount)
+		f_in_sync_committee_count.Append(uint16(val.InSyncCommitteeCount))
+		f_sync_committee_participations_included.Append(uint16(val.SyncCommitteeParticipationsIncluded))
+		f_attestations_included.Append(uint16(val.AttestationsIncluded))
+		f_missing_source_count.Append(uint16(val.MissingSourceCount))
+		f_missing_target_count.Append(uint16(val.MissingTargetCount))
+		f_missing_head_count.Append(uint16(val.MissingHeadCount))
 		f_block_api_reward.Append(uint64(val.ProposerApiReward))
 		f_block_experimental_reward.Append(uint64(val.ProposerManualReward))
 		f_inclusion_delay_sum.Append(uint32(val.InclusionDelaySum))
@@ -98,6 +156,72 @@ func rewardsAggregationInput(vals []spec.ValidatorRewardsAggregation) proto.Inpu
 	}
 }
 
+func entityRewardsAggregationInput(entities []spec.ValidatorRewardsAggregation) proto.Input {
+	// one object per column
+	var (
+		f_entity                                 proto.ColStr
+		f_start_epoch                            proto.ColUInt64
+		f_end_epoch                              proto.ColUInt64
+		f_validators                             proto.ColUInt64
+		f_reward                                 proto.ColInt64
+		f_max_reward                             proto.ColUInt64
+		f_max_att_reward                         proto.ColUInt64
+		f_max_sync_reward                        proto.ColUInt64
+		f_base_reward                            proto.ColUInt64
+		f_in_sync_committee_count                proto.ColUInt64
+		f_sync_committee_participations_included proto.ColUInt64
+		f_attestations_included                  proto.ColUInt64
+		f_missing_source_count                   proto.ColUInt64
+		f_missing_target_count                   proto.ColUInt64
+		f_missing_head_count                     proto.ColUInt64
+		f_block_api_reward                       proto.ColUInt64
+		f_block_experimental_reward              proto.ColUInt64
+		f_inclusion_delay_sum                    proto.ColUInt64
+	)
+
+	for _, entity := range entities {
+		f_entity.Append(entity.Entity)
+		f_start_epoch.Append(uint64(entity.StartEpoch))
+		f_end_epoch.Append(uint64(entity.EndEpoch))
+		f_validators.Append(entity.Validators)
+		f_reward.Append(entity.Reward)
+		f_max_reward.Append(uint64(entity.MaxReward))
+		f_max_att_reward.Append(uint64(entity.MaxAttestationReward))
+		f_max_sync_reward.Append(uint64(entity.MaxSyncCommitteeReward))
+		f_base_reward.Append(uint64(entity.BaseReward))
+		f_in_sync_committee_count.Append(entity.InSyncCommitteeCount)
+		f_sync_committee_participations_included.Append(entity.SyncCommitteeParticipationsIncluded)
+		f_attestations_included.Append(entity.AttestationsIncluded)
+		f_missing_source_count.Append(entity.MissingSourceCount)
+		f_missing_target_count.Append(entity.MissingTargetCount)
+		f_missing_head_count.Append(entity.MissingHeadCount)
+		f_block_api_reward.Append(uint64(entity.ProposerApiReward))
+		f_block_experimental_reward.Append(uint64(entity.ProposerManualReward))
+		f_inclusion_delay_sum.Append(entity.InclusionDelaySum)
+	}
+
+	return proto.Input{
+		{Name: "f_entity", Data: f_entity},
+		{Name: "f_start_epoch", Data: f_start_epoch},
+		{Name: "f_end_epoch", Data: f_end_epoch},
+		{Name: "f_validators", Data: f_validators},
+		{Name: "f_reward", Data: f_reward},
+		{Name: "f_max_reward", Data: f_max_reward},
+		{Name: "f_max_att_reward", Data: f_max_att_reward},
+		{Name: "f_max_sync_reward", Data: f_max_sync_reward},
+		{Name: "f_base_reward", Data: f_base_reward},
+		{Name: "f_in_sync_committee_count", Data: f_in_sync_committee_count},
+		{Name: "f_sync_committee_participations_included", Data: f_sync_committee_participations_included},
+		{Name: "f_attestations_included", Data: f_attestations_included},
+		{Name: "f_missing_source_count", Data: f_missing_source_count},
+		{Name: "f_missing_target_count", Data: f_missing_target_count},
+		{Name: "f_missing_head_count", Data: f_missing_head_count},
+		{Name: "f_block_api_reward", Data: f_block_api_reward},
+		{Name: "f_block_experimental_reward", Data: f_block_experimental_reward},
+		{Name: "f_inclusion_delay_sum", Data: f_inclusion_delay_sum},
+	}
+}
+
 func (p *DBService) PersistValidatorRewardsAggregation(data map[phase0.ValidatorIndex]*spec.ValidatorRewardsAggregation) error {
 	persistObj := PersistableObject[spec.ValidatorRewardsAggregation]{
 		input: rewardsAggregationInput,
@@ -116,6 +240,42 @@ func (p *DBService) PersistValidatorRewardsAggregation(data map[phase0.Validator
 	return err
 }
 
+func (p *DBService) PersistEntityRewardsAggregation(data map[string]*spec.ValidatorRewardsAggregation) error {
+	persistObj := PersistableObject[spec.ValidatorRewardsAggregation]{
+		input: entityRewardsAggregationInput,
+		table: entityRewardsAggregationTable,
+		query: insertEntityRewardsAggregationQuery,
+	}
+
+	for _, item := range data {
+		persistObj.Append(*item)
+	}
+
+	err := p.Persist(persistObj.ExportPersist())
+	if err != nil {
+		log.Errorf("error persisting entity rewards: %s", err.Error())
+	}
+	return err
+}
+
+// RetrieveValidatorPools returns the pool name of every validator that has one.
+func (p *DBService) RetrieveValidatorPools() (map[phase0.ValidatorIndex]string, error) {
+	var dest []struct {
+		F_val_idx   uint64 `ch:"f_val_idx"`
+		F_pool_name string `ch:"f_pool_name"`
+	}
+	err := p.highSelect(selectValidatorPoolsQuery, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	pools := make(map[phase0.ValidatorIndex]string, len(dest))
+	for _, item := range dest {
+		pools[phase0.ValidatorIndex(item.F_val_idx)] = item.F_pool_name
+	}
+	return pools, nil
+}
+
 func (p *DBService) DeleteValidatorRewardsAggregationUntil(epoch phase0.Epoch) error {
 
 	deleteObj := DeletableObject{
@@ -131,3 +291,72 @@ func (p *DBService) DeleteValidatorRewardsAggregationUntil(epoch phase0.Epoch) e
 
 	return err
 }
+
+// RetrieveValidatorRewardsAggregation rebuilds the aggregation of the window [startEpoch, endEpoch]
+// out of the epochs in [startEpoch, untilEpoch) stored in t_validator_rewards_summary.
+// It also returns the epochs that were found, so the caller knows which ones are missing.
+func (p *DBService) RetrieveValidatorRewardsAggregation(
+	startEpoch phase0.Epoch,
+	endEpoch phase0.Epoch,
+	untilEpoch phase0.Epoch) (map[phase0.ValidatorIndex]*spec.ValidatorRewardsAggregation, []phase0.Epoch, error) {
+
+	var epochs []struct {
+		F_epoch uint64 `ch:"f_epoch"`
+	}
+	err := p.highSelect(
+		fmt.Sprintf(selectValidatorRewardsEpochsQuery, valRewardsTable, startEpoch, untilEpoch),
+		&epochs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dest []struct {
+		F_val_idx                       uint64 `ch:"f_val_idx"`
+		F_reward_sum                    int64  `ch:"f_reward_sum"`
+		F_max_reward_sum                uint64 `ch:"f_max_reward_sum"`
+		F_max_att_reward_sum            uint64 `ch:"f_max_att_reward_sum"`
+		F_max_sync_reward_sum           uint64 `ch:"f_max_sync_reward_sum"`
+		F_base_reward_sum               uint64 `ch:"f_base_reward_sum"`
+		F_in_sync_committee_count       uint16 `ch:"f_in_sync_committee_count"`
+		F_sync_committee_participations uint16 `ch:"f_sync_committee_participations_sum"`
+		F_attestations_included_count   uint16 `ch:"f_attestations_included_count"`
+		F_missing_source_count          uint16 `ch:"f_missing_source_count"`
+		F_missing_target_count          uint16 `ch:"f_missing_target_count"`
+		F_missing_head_count            uint16 `ch:"f_missing_head_count"`
+		F_block_api_reward_sum          uint64 `ch:"f_block_api_reward_sum"`
+		F_block_experimental_reward_sum uint64 `ch:"f_block_experimental_reward_sum"`
+		F_inclusion_delay_sum           uint32 `ch:"f_inclusion_delay_sum"`
+	}
+	err = p.highSelect(
+		fmt.Sprintf(selectValidatorRewardsAggregationQuery, valRewardsTable, startEpoch, untilEpoch),
+		&dest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	aggregations := make(map[phase0.ValidatorIndex]*spec.ValidatorRewardsAggregation, len(dest))
+	for _, item := range dest {
+		aggregation := spec.NewValidatorRewardsAggregation(phase0.ValidatorIndex(item.F_val_idx), startEpoch, endEpoch)
+		aggregation.Reward = item.F_reward_sum
+		aggregation.MaxReward = phase0.Gwei(item.F_max_reward_sum)
+		aggregation.MaxAttestationReward = phase0.Gwei(item.F_max_att_reward_sum)
+		aggregation.MaxSyncCommitteeReward = phase0.Gwei(item.F_max_sync_reward_sum)
+		aggregation.BaseReward = phase0.Gwei(item.F_base_reward_sum)
+		aggregation.InSyncCommitteeCount = uint64(item.F_in_sync_committee_count)
+		aggregation.SyncCommitteeParticipationsIncluded = uint64(item.F_sync_committee_participations)
+		aggregation.AttestationsIncluded = uint64(item.F_attestations_included_count)
+		aggregation.MissingSourceCount = uint64(item.F_missing_source_count)
+		aggregation.MissingTargetCount = uint64(item.F_missing_target_count)
+		aggregation.MissingHeadCount = uint64(item.F_missing_head_count)
+		aggregation.ProposerApiReward = phase0.Gwei(item.F_block_api_reward_sum)
+		aggregation.ProposerManualReward = phase0.Gwei(item.F_block_experimental_reward_sum)
+		aggregation.InclusionDelaySum = uint64(item.F_inclusion_delay_sum)
+		aggregations[aggregation.ValidatorIndex] = aggregation
+	}
+
+	foundEpochs := make([]phase0.Epoch, 0, len(epochs))
+	for _, item := range epochs {
+		foundEpochs = append(foundEpochs, phase0.Epoch(item.F_epoch))
+	}
+	return aggregations, foundEpochs, nil
+}
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var lastRowVersion atomic.Uint64
+
+// nextRowVersion returns the version of the rows persisted in a batch.
+// Slots and epochs rewritten after a reorg or when checking finality are inserted again
+// with a higher version instead of deleting the previous rows, and the v_* views only
+// return the latest version. Versions are nanosecond timestamps, so they keep increasing
+// across restarts, and strictly increase within the process.
+func nextRowVersion() uint64 {
+	for {
+		last := lastRowVersion.Load()
+		version := max(uint64(time.Now().UnixNano()), last+1)
+		if lastRowVersion.CompareAndSwap(last, version) {
+			return version
+		}
+	}
+}
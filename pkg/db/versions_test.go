@@ -0,0 +1,35 @@
+package db
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextRowVersion(t *testing.T) {
+	previous := nextRowVersion()
+	for range 1000 {
+		version := nextRowVersion()
+		assert.Greater(t, version, previous)
+		previous = version
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[uint64]bool)
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				version := nextRowVersion()
+				mu.Lock()
+				assert.False(t, seen[version])
+				seen[version] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -13,22 +13,20 @@ var (
 		f_index, 
 		f_val_idx,
 		f_address,
-		f_amount)
+		f_amount,
+		f_block_root)
 		VALUES`
-
-	deleteWithdrawalsQuery = `
-		DELETE FROM %s
-		WHERE f_slot = $1;`
 )
 
 func withdrawalsInput(withdrawals []spec.Withdrawal) proto.Input {
 	// one object per column
 	var (
-		f_slot    proto.ColUInt64
-		f_index   proto.ColUInt64
-		f_val_idx proto.ColUInt64
-		f_address proto.ColStr
-		f_amount  proto.ColUInt64
+		f_slot       proto.ColUInt64
+		f_index      proto.ColUInt64
+		f_val_idx    proto.ColUInt64
+		f_address    proto.ColStr
+		f_amount     proto.ColUInt64
+		f_block_root proto.ColStr
 	)
 
 	for _, withdrawal := range withdrawals {
@@ -38,6 +36,7 @@ func withdrawalsInput(withdrawals []spec.Withdrawal) proto.Input {
 		f_val_idx.Append(uint64(withdrawal.ValidatorIndex))
 		f_address.Append(withdrawal.Address.String())
 		f_amount.Append(uint64(withdrawal.Amount))
+		f_block_root.Append(withdrawal.BlockRoot.String())
 	}
 
 	return proto.Input{
@@ -47,6 +46,7 @@ func withdrawalsInput(withdrawals []spec.Withdrawal) proto.Input {
 		{Name: "f_val_idx", Data: f_val_idx},
 		{Name: "f_address", Data: f_address},
 		{Name: "f_amount", Data: f_amount},
+		{Name: "f_block_root", Data: f_block_root},
 	}
 }
 
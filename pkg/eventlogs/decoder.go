@@ -0,0 +1,241 @@
+package eventlogs
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	moduleName = "eventlogs"
+	log        = logrus.WithField(
+		"module", moduleName)
+
+	//go:embed abis/*.json
+	builtinABIs embed.FS
+)
+
+// event of an ABI, identified by its topic and the number of topics it emits
+type abiEvent struct {
+	abi   string
+	event abi.Event
+}
+
+type eventKey struct {
+	topic  common.Hash
+	topics int
+}
+
+// Decoder decodes receipt logs with the ERC-20 and ERC-721 ABIs plus the ones read from a directory.
+// When several ABIs define the same event, the first one read is used.
+type Decoder struct {
+	events map[eventKey]abiEvent
+}
+
+// NewDecoder returns a decoder knowing the built-in token ABIs only.
+func NewDecoder() (*Decoder, error) {
+	d := &Decoder{events: make(map[eventKey]abiEvent)}
+	for _, name := range []string{spec.ERC20Standard, spec.ERC721Standard} {
+		content, err := builtinABIs.ReadFile("abis/" + name + ".json")
+		if err != nil {
+			return nil, err
+		}
+		if err := d.addABI(name, content); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// ReadABIDir returns a decoder knowing the built-in token ABIs and every *.json ABI of the
+// directory, named after its file. An empty path only loads the built-in ones.
+func ReadABIDir(dir string) (*Decoder, error) {
+	d, err := NewDecoder()
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return d, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		if err := d.addABI(name, content); err != nil {
+			return nil, fmt.Errorf("could not read ABI %s: %w", path, err)
+		}
+	}
+	log.Infof("read %d ABIs from %s", len(paths), dir)
+	return d, nil
+}
+
+func (d *Decoder) addABI(name string, content []byte) error {
+	parsed, err := abi.JSON(bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	for _, event := range parsed.Events {
+		if event.Anonymous {
+			continue // no topic to find them by
+		}
+		indexed := 0
+		for _, input := range event.Inputs {
+			if input.Indexed {
+				indexed++
+			}
+		}
+		key := eventKey{topic: event.ID, topics: indexed + 1}
+		if existing, ok := d.events[key]; ok {
+			log.Debugf("%s of %s already decoded with %s", event.Sig, name, existing.abi)
+			continue
+		}
+		d.events[key] = abiEvent{abi: name, event: event}
+	}
+	return nil
+}
+
+// Decode finds the event of a log and unpacks its arguments.
+func (d *Decoder) Decode(item spec.AgnosticLog) (spec.DecodedLog, bool) {
+	match, values, ok := d.unpack(item)
+	if !ok {
+		return spec.DecodedLog{}, false
+	}
+	return newDecodedLog(item, match, values), true
+}
+
+func newDecodedLog(item spec.AgnosticLog, match abiEvent, values map[string]interface{}) spec.DecodedLog {
+	decoded := spec.DecodedLog{
+		Slot:      item.Slot,
+		BlockRoot: item.BlockRoot,
+		TxHash:    item.TxHash,
+		LogIndex:  item.LogIndex,
+		Address:   item.Address,
+		ABI:       match.abi,
+		Event:     match.event.Name,
+		Signature: match.event.Sig,
+		ArgNames:  make([]string, 0, len(match.event.Inputs)),
+		ArgValues: make([]string, 0, len(match.event.Inputs)),
+	}
+	for _, input := range match.event.Inputs {
+		decoded.ArgNames = append(decoded.ArgNames, input.Name)
+		decoded.ArgValues = append(decoded.ArgValues, formatValue(values[input.Name]))
+	}
+	return decoded
+}
+
+func (d *Decoder) unpack(item spec.AgnosticLog) (abiEvent, map[string]interface{}, bool) {
+	if len(item.Topics) == 0 {
+		return abiEvent{}, nil, false
+	}
+	match, ok := d.events[eventKey{topic: item.Topics[0], topics: len(item.Topics)}]
+	if !ok {
+		return abiEvent{}, nil, false
+	}
+
+	values := make(map[string]interface{})
+	var indexed abi.Arguments
+	for _, input := range match.event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, item.Topics[1:]); err != nil {
+		log.Debugf("could not decode topics of log %d at slot %d: %s", item.LogIndex, item.Slot, err)
+		return abiEvent{}, nil, false
+	}
+	if err := match.event.Inputs.NonIndexed().UnpackIntoMap(values, item.Data); err != nil {
+		log.Debugf("could not decode data of log %d at slot %d: %s", item.LogIndex, item.Slot, err)
+		return abiEvent{}, nil, false
+	}
+	return match, values, true
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.String()
+	case common.Hash:
+		return v.String()
+	case *big.Int:
+		return v.String()
+	case []byte:
+		return hexutil.Encode(v)
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Result holds the logs of a block: the token events in their own tables and
+// the rest of the decoded events.
+type Result struct {
+	Decoded   []spec.DecodedLog
+	Transfers []spec.TokenTransfer
+	Approvals []spec.TokenApproval
+}
+
+// DecodeLogs decodes the given logs, Transfer and Approval events of the built-in
+// token ABIs are returned as transfers and approvals.
+func (d *Decoder) DecodeLogs(logs []spec.AgnosticLog) Result {
+	var result Result
+	for _, item := range logs {
+		match, values, ok := d.unpack(item)
+		if !ok {
+			continue
+		}
+		if match.abi == spec.ERC20Standard || match.abi == spec.ERC721Standard {
+			// arguments in the order of the signature: from/owner, to/spender, value/token ID
+			inputs := match.event.Inputs
+			first, _ := values[inputs[0].Name].(common.Address)
+			second, _ := values[inputs[1].Name].(common.Address)
+			value, _ := values[inputs[2].Name].(*big.Int)
+			switch match.event.Name {
+			case "Transfer":
+				result.Transfers = append(result.Transfers, spec.TokenTransfer{
+					Slot:      item.Slot,
+					BlockRoot: item.BlockRoot,
+					TxHash:    item.TxHash,
+					LogIndex:  item.LogIndex,
+					Token:     item.Address,
+					Standard:  match.abi,
+					From:      first,
+					To:        second,
+					Value:     value,
+				})
+				continue
+			case "Approval":
+				result.Approvals = append(result.Approvals, spec.TokenApproval{
+					Slot:      item.Slot,
+					BlockRoot: item.BlockRoot,
+					TxHash:    item.TxHash,
+					LogIndex:  item.LogIndex,
+					Token:     item.Address,
+					Standard:  match.abi,
+					Owner:     first,
+					Spender:   second,
+					Value:     value,
+				})
+				continue
+			}
+		}
+		result.Decoded = append(result.Decoded, newDecodedLog(item, match, values))
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package eventlogs
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	token   = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	alice   = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	bob     = common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	topicOf = func(signature string) common.Hash { return crypto.Keccak256Hash([]byte(signature)) }
+)
+
+func uint256Word(value int64) []byte {
+	return common.LeftPadBytes(big.NewInt(value).Bytes(), 32)
+}
+
+func TestDecodeLogs(t *testing.T) {
+	decoder, err := NewDecoder()
+	require.NoError(t, err)
+
+	transfer := topicOf("Transfer(address,address,uint256)")
+	approval := topicOf("Approval(address,address,uint256)")
+	logs := []spec.AgnosticLog{
+		// ERC-20: the amount is in the data
+		{LogIndex: 0, Address: token, Topics: []common.Hash{transfer, common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes())}, Data: uint256Word(1000)},
+		// ERC-721: the token ID is indexed
+		{LogIndex: 1, Address: token, Topics: []common.Hash{transfer, common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes()), common.BigToHash(big.NewInt(42))}},
+		{LogIndex: 2, Address: token, Topics: []common.Hash{approval, common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes())}, Data: uint256Word(7)},
+		{LogIndex: 3, Address: token, Topics: []common.Hash{topicOf("ApprovalForAll(address,address,bool)"), common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes())}, Data: uint256Word(1)},
+		// unknown event and malformed data
+		{LogIndex: 4, Address: token, Topics: []common.Hash{topicOf("Unknown()")}},
+		{LogIndex: 5, Address: token, Topics: []common.Hash{transfer, common.BytesToHash(alice.Bytes()), common.BytesToHash(bob.Bytes())}, Data: []byte{1}},
+	}
+
+	result := decoder.DecodeLogs(logs)
+	require.Len(t, result.Transfers, 2)
+	assert.Equal(t, spec.TokenTransfer{LogIndex: 0, Token: token, Standard: spec.ERC20Standard, From: alice, To: bob, Value: big.NewInt(1000)}, result.Transfers[0])
+	assert.Equal(t, spec.TokenTransfer{LogIndex: 1, Token: token, Standard: spec.ERC721Standard, From: alice, To: bob, Value: big.NewInt(42)}, result.Transfers[1])
+	require.Len(t, result.Approvals, 1)
+	assert.Equal(t, spec.TokenApproval{LogIndex: 2, Token: token, Standard: spec.ERC20Standard, Owner: alice, Spender: bob, Value: big.NewInt(7)}, result.Approvals[0])
+
+	require.Len(t, result.Decoded, 1)
+	decoded := result.Decoded[0]
+	assert.Equal(t, "ApprovalForAll", decoded.Event)
+	assert.Equal(t, "ApprovalForAll(address,address,bool)", decoded.Signature)
+	assert.Equal(t, []string{"owner", "operator", "approved"}, decoded.ArgNames)
+	assert.Equal(t, []string{alice.String(), bob.String(), "true"}, decoded.ArgValues)
+}
+
+func TestReadABIDir(t *testing.T) {
+	dir := t.TempDir()
+	content := `[{"type": "event", "name": "Deposit", "inputs": [
+		{"name": "dst", "type": "address", "indexed": true},
+		{"name": "wad", "type": "uint256", "indexed": false}]}]`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "weth.json"), []byte(content), 0o644))
+
+	decoder, err := ReadABIDir(dir)
+	require.NoError(t, err)
+	decoded, ok := decoder.Decode(spec.AgnosticLog{
+		Address: token,
+		Topics:  []common.Hash{topicOf("Deposit(address,uint256)"), common.BytesToHash(alice.Bytes())},
+		Data:    uint256Word(5),
+	})
+	require.True(t, ok)
+	assert.Equal(t, "weth", decoded.ABI)
+	assert.Equal(t, []string{alice.String(), "5"}, decoded.ArgValues)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644))
+	_, err = ReadABIDir(dir)
+	assert.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"time"
+
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+func (e *Events) SubscribeToDataColumnSidecarsEvents() {
+	err := e.subscribe("data_column_sidecar", e.HandleDataColumnSidecarEvent) // every column received
+	if err != nil {
+		log.Panicf("failed to subscribe to data_column_sidecar events: %s", err)
+	}
+	log.Infof("subscribed to data_column_sidecar events")
+}
+
+func (e *Events) HandleDataColumnSidecarEvent(event *apiv1.Event) {
+	timestamp := time.Now()
+	if event.Data == nil {
+		return
+	}
+
+	data := spec.DataColumnSidecarEventWrapper{
+		Timestamp:              timestamp,
+		DataColumnSidecarEvent: *event.Data.(*apiv1.DataColumnSidecarEvent),
+	}
+
+	e.DataColumnSidecarChan <- data
+}
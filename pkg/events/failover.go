@@ -0,0 +1,138 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	eth2api "github.com/attestantio/go-eth2-client/api"
+)
+
+var (
+	// if no head event arrives within this time, the stream is considered stalled
+	// and all the subscriptions are moved to the next healthiest beacon node
+	headEventsTimeout = 90 * time.Second
+)
+
+type subscription struct {
+	topic   string
+	handler eth2api.EventHandlerFunc
+	node    string
+}
+
+// eventSubscriptions keeps track of the active event streams so that they
+// can be moved to another beacon node when the current one stalls.
+type eventSubscriptions struct {
+	mu       sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	subs     []*subscription
+	lastHead time.Time
+	watching bool
+}
+
+func newEventSubscriptions() *eventSubscriptions {
+	return &eventSubscriptions{
+		subs: make([]*subscription, 0),
+	}
+}
+
+// subscribe opens a new stream for the topic on the healthiest beacon node.
+func (e *Events) subscribe(topic string, handler eth2api.EventHandlerFunc) error {
+	e.subs.mu.Lock()
+	defer e.subs.mu.Unlock()
+
+	if e.subs.ctx == nil {
+		e.subs.ctx, e.subs.cancel = context.WithCancel(e.ctx)
+	}
+	sub := &subscription{
+		topic:   topic,
+		handler: handler,
+	}
+	node, err := e.cli.SubscribeToEvents(e.subs.ctx, []string{topic}, handler)
+	if err != nil {
+		return err
+	}
+	sub.node = node
+	e.subs.subs = append(e.subs.subs, sub)
+	log.Debugf("%s events streamed from %s", topic, node)
+	return nil
+}
+
+// resubscribe closes every stream and opens them again, which moves them
+// away from beacon nodes reported as failing.
+func (e *Events) resubscribe() error {
+	e.subs.mu.Lock()
+	defer e.subs.mu.Unlock()
+
+	if e.subs.cancel != nil {
+		e.subs.cancel()
+	}
+	e.subs.ctx, e.subs.cancel = context.WithCancel(e.ctx)
+
+	for _, sub := range e.subs.subs {
+		node, err := e.cli.SubscribeToEvents(e.subs.ctx, []string{sub.topic}, sub.handler)
+		if err != nil {
+			return fmt.Errorf("could not resubscribe to %s events: %w", sub.topic, err)
+		}
+		log.Infof("%s events now streamed from %s", sub.topic, node)
+		sub.node = node
+	}
+	return nil
+}
+
+func (e *Events) touchHead() {
+	e.subs.mu.Lock()
+	defer e.subs.mu.Unlock()
+	e.subs.lastHead = time.Now()
+}
+
+// watchHeadEvents moves the subscriptions to another beacon node
+// whenever head events stop arriving.
+func (e *Events) watchHeadEvents() {
+	e.subs.mu.Lock()
+	if e.subs.watching {
+		e.subs.mu.Unlock()
+		return
+	}
+	e.subs.watching = true
+	e.subs.lastHead = time.Now()
+	e.subs.mu.Unlock()
+
+	ticker := time.NewTicker(headEventsTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case <-ticker.C:
+			e.subs.mu.Lock()
+			stalled := time.Since(e.subs.lastHead) > headEventsTimeout
+			headNode := ""
+			for _, sub := range e.subs.subs {
+				if sub.topic == "head" {
+					headNode = sub.node
+				}
+			}
+			e.subs.mu.Unlock()
+
+			if !stalled {
+				continue
+			}
+			if e.cli.NodesNum() < 2 {
+				log.Warnf("no head event received in the last %s", headEventsTimeout)
+				e.touchHead() // do not warn on every tick
+				continue
+			}
+
+			log.Warnf("no head event received from %s in the last %s, switching beacon node", headNode, headEventsTimeout)
+			e.cli.ReportNodeFailure(headNode)
+			if err := e.resubscribe(); err != nil {
+				log.Errorf("%s", err)
+			}
+			e.touchHead()
+		}
+	}
+}
@@ -1,16 +1,12 @@
 package events
 
 import (
-	eth2api "github.com/attestantio/go-eth2-client/api"
 	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
 )
 
 func (e *Events) SubscribeToFinalizedCheckpointEvents() {
 	// subscribe to head event
-	err := e.cli.Api.Events(e.ctx, &eth2api.EventsOpts{
-		Topics:  []string{"finalized_checkpoint"},
-		Handler: e.HandleCheckpointEvent,
-	}) // every new checkpoint
+	err := e.subscribe("finalized_checkpoint", e.HandleCheckpointEvent) // every new checkpoint
 	if err != nil {
 		log.Panicf("failed to subscribe to finalized checkpoint events: %s", err)
 	}
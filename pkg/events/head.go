@@ -5,7 +5,6 @@ import (
 
 	"github.com/migalabs/goteth/pkg/db"
 
-	eth2api "github.com/attestantio/go-eth2-client/api"
 	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/migalabs/goteth/pkg/spec"
@@ -13,14 +12,12 @@ import (
 
 func (e Events) SubscribeToHeadEvents() {
 	// subscribe to head event
-	err := e.cli.Api.Events(e.ctx, &eth2api.EventsOpts{
-		Topics:  []string{"head"},
-		Handler: e.HandleHeadEvent,
-	}) // every new head
+	err := e.subscribe("head", e.HandleHeadEvent) // every new head
 	if err != nil {
 		log.Panicf("failed to subscribe to head events: %s", err)
 	}
 	log.Infof("subscribed to head events")
+	go e.watchHeadEvents()
 }
 
 func (e *Events) HandleHeadEvent(event *apiv1.Event) {
@@ -29,6 +26,7 @@ func (e *Events) HandleHeadEvent(event *apiv1.Event) {
 	if event.Data == nil {
 		return
 	}
+	e.touchHead()
 	data := event.Data.(*apiv1.HeadEvent) // cast to head event
 	headEpoch := phase0.Epoch(data.Slot) / spec.SlotsPerEpoch
 
@@ -1,16 +1,12 @@
 package events
 
 import (
-	eth2api "github.com/attestantio/go-eth2-client/api"
 	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
 )
 
 func (e *Events) SubscribeToReorgsEvents() {
 	// subscribe to head event
-	err := e.cli.Api.Events(e.ctx, &eth2api.EventsOpts{
-		Topics:  []string{"chain_reorg"},
-		Handler: e.HandleReorgEvent,
-	}) // every reorg
+	err := e.subscribe("chain_reorg", e.HandleReorgEvent) // every reorg
 	if err != nil {
 		log.Panicf("failed to subscribe to chain_reorg events: %s", err)
 	}
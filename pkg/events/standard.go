@@ -22,21 +22,26 @@ type Events struct {
 	SubscribedHead bool
 	HeadChan       chan db.HeadEvent
 
-	SubscribedFinalized bool
-	FinalizedChan       chan apiv1.FinalizedCheckpointEvent
-	ReorgChan           chan apiv1.ChainReorgEvent
-	BlobSidecarChan     chan spec.BlobSideCarEventWraper
+	SubscribedFinalized   bool
+	FinalizedChan         chan apiv1.FinalizedCheckpointEvent
+	ReorgChan             chan apiv1.ChainReorgEvent
+	BlobSidecarChan       chan spec.BlobSideCarEventWraper
+	DataColumnSidecarChan chan spec.DataColumnSidecarEventWrapper
+
+	subs *eventSubscriptions // active streams, moved across beacon nodes on failure
 }
 
 func NewEventsObj(iCtx context.Context, iCli *clientapi.APIClient) Events {
 	return Events{
-		ctx:                 iCtx,
-		cli:                 iCli,
-		SubscribedHead:      false,
-		HeadChan:            make(chan db.HeadEvent, 32),
-		SubscribedFinalized: false,
-		FinalizedChan:       make(chan apiv1.FinalizedCheckpointEvent),
-		ReorgChan:           make(chan apiv1.ChainReorgEvent),
-		BlobSidecarChan:     make(chan spec.BlobSideCarEventWraper),
+		ctx:                   iCtx,
+		cli:                   iCli,
+		SubscribedHead:        false,
+		HeadChan:              make(chan db.HeadEvent, 32),
+		SubscribedFinalized:   false,
+		FinalizedChan:         make(chan apiv1.FinalizedCheckpointEvent),
+		ReorgChan:             make(chan apiv1.ChainReorgEvent),
+		BlobSidecarChan:       make(chan spec.BlobSideCarEventWraper),
+		DataColumnSidecarChan: make(chan spec.DataColumnSidecarEventWrapper, 128),
+		subs:                  newEventSubscriptions(),
 	}
 }
@@ -0,0 +1,229 @@
+package graffiti
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	moduleName = "graffiti"
+	log        = logrus.WithField(
+		"module", moduleName)
+
+	// two letter client codes of the execution-apis ClientVersionV1 and the graffiti
+	// the consensus clients build from it, per layer so that swapped codes are not accepted
+	DefaultELCodes = map[string]string{
+		"BU": "besu",
+		"EJ": "ethereumjs",
+		"EG": "erigon",
+		"GE": "geth",
+		"NM": "nethermind",
+		"RH": "reth",
+	}
+	DefaultCLCodes = map[string]string{
+		"GR": "grandine",
+		"LH": "lighthouse",
+		"LS": "lodestar",
+		"NB": "nimbus",
+		"PM": "prysm",
+		"TK": "teku",
+	}
+
+	DefaultRules = []Rule{
+		{
+			// ELxxxxCLxxxx, ELxxCLxx or ELCL, optionally followed by the user graffiti
+			Name:    "client-version",
+			Pattern: `^(?P<el>[A-Z]{2})(?P<el_version>[0-9a-fA-F]{4}|[0-9a-fA-F]{2})?(?P<cl>[A-Z]{2})(?P<cl_version>[0-9a-fA-F]{4}|[0-9a-fA-F]{2})?(\s|$)`,
+		},
+		{
+			// RP-<EL><CL> <smartnode version>
+			Name:    "rocketpool",
+			Pattern: `^RP-(?P<el>[A-Z])(?P<cl>[A-Z])\b`,
+			ELCodes: map[string]string{"B": "besu", "G": "geth", "N": "nethermind", "R": "reth"},
+			CLCodes: map[string]string{"L": "lighthouse", "N": "nimbus", "P": "prysm", "S": "lodestar", "T": "teku"},
+		},
+		{
+			// client names, as in the default graffiti of most clients: Lighthouse/v5.1.3-a1b2c3d
+			Name:    "cl-name",
+			Pattern: `(?i)\b(?P<cl>lighthouse|prysm|teku|nimbus|lodestar|grandine)\b(?:[/ -]v?(?P<cl_version>\d+\.\d+\.\d+))?`,
+		},
+		{
+			Name:    "el-name",
+			Pattern: `(?i)\b(?P<el>geth|nethermind|besu|erigon|reth|ethereumjs)\b(?:[/ -]v?(?P<el_version>\d+\.\d+\.\d+))?`,
+		},
+	}
+)
+
+// Rule extracts clients from the graffiti matching its pattern. The named groups cl and el
+// capture client codes or names, cl_version and el_version their versions. CL and EL set
+// the clients of every matching graffiti instead.
+type Rule struct {
+	Name    string            `json:"name"`
+	Pattern string            `json:"pattern"`
+	CL      string            `json:"cl"`
+	EL      string            `json:"el"`
+	CLCodes map[string]string `json:"cl-codes"` // codes of this rule only, the rule set codes otherwise
+	ELCodes map[string]string `json:"el-codes"`
+
+	regexp *regexp.Regexp
+}
+
+// RuleSet parses the graffiti of blocks, as read from a rules file:
+//
+//	{
+//	  "rules": [{"name": "my-pool", "pattern": "^mypool-(?P<cl>[a-z]+)", "el": "geth"}],
+//	  "el-codes": {"GE": "geth"},
+//	  "cl-codes": {"LH": "lighthouse"}
+//	}
+//
+// The rules are applied in order, the first one finding the client of a layer sets it.
+type RuleSet struct {
+	Rules   []Rule            `json:"rules"`
+	ELCodes map[string]string `json:"el-codes"`
+	CLCodes map[string]string `json:"cl-codes"`
+
+	elNames map[string]bool // known client names of each layer, codes aside
+	clNames map[string]bool
+}
+
+// NewRuleSet compiles the given rules. Without rules or codes of a layer the defaults are used.
+func NewRuleSet(rules []Rule, elCodes map[string]string, clCodes map[string]string) (*RuleSet, error) {
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+	if len(elCodes) == 0 {
+		elCodes = DefaultELCodes
+	}
+	if len(clCodes) == 0 {
+		clCodes = DefaultCLCodes
+	}
+	r := &RuleSet{
+		Rules:   make([]Rule, len(rules)),
+		ELCodes: upperKeys(elCodes),
+		CLCodes: upperKeys(clCodes),
+		elNames: make(map[string]bool),
+		clNames: make(map[string]bool),
+	}
+	for _, name := range r.ELCodes {
+		r.elNames[name] = true
+	}
+	for _, name := range r.CLCodes {
+		r.clNames[name] = true
+	}
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("graffiti rule %d has no name", i)
+		}
+		compiled, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern of graffiti rule %s: %w", rule.Name, err)
+		}
+		rule.regexp = compiled
+		rule.CLCodes = upperKeys(rule.CLCodes)
+		rule.ELCodes = upperKeys(rule.ELCodes)
+		if rule.EL != "" {
+			r.elNames[strings.ToLower(rule.EL)] = true
+		}
+		if rule.CL != "" {
+			r.clNames[strings.ToLower(rule.CL)] = true
+		}
+		r.Rules[i] = rule
+	}
+	return r, nil
+}
+
+func upperKeys(codes map[string]string) map[string]string {
+	if codes == nil {
+		return nil
+	}
+	result := make(map[string]string, len(codes))
+	for code, name := range codes {
+		result[strings.ToUpper(code)] = strings.ToLower(name)
+	}
+	return result
+}
+
+// ReadRulesFile reads a rule set from a JSON file, an empty path returns the default rules.
+func ReadRulesFile(path string) (*RuleSet, error) {
+	if path == "" {
+		return NewRuleSet(nil, nil, nil)
+	}
+	log.Infof("reading graffiti rules from: %s", path)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file RuleSet
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("could not parse graffiti rules file %s: %w", path, err)
+	}
+	ruleSet, err := NewRuleSet(file.Rules, file.ELCodes, file.CLCodes)
+	if err != nil {
+		return nil, fmt.Errorf("could not read graffiti rules file %s: %w", path, err)
+	}
+	log.Infof("read %d graffiti rules", len(ruleSet.Rules))
+	return ruleSet, nil
+}
+
+// Parse returns the clients advertised in a graffiti.
+func (r *RuleSet) Parse(graffiti []byte) spec.GraffitiClients {
+	text := strings.ReplaceAll(strings.ToValidUTF8(string(graffiti), "?"), "\u0000", "")
+	clients := spec.GraffitiClients{}
+	for _, rule := range r.Rules {
+		if clients.CLClient != "" && clients.ELClient != "" {
+			break
+		}
+		match := rule.regexp.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		groups := make(map[string]string)
+		for i, name := range rule.regexp.SubexpNames() {
+			if name != "" && match[i] != "" {
+				groups[name] = match[i]
+			}
+		}
+		cl, okCL := client(rule.CL, groups["cl"], rule.CLCodes, r.CLCodes, r.clNames)
+		el, okEL := client(rule.EL, groups["el"], rule.ELCodes, r.ELCodes, r.elNames)
+		if !okCL || !okEL {
+			continue // a captured code of no known client of its layer, the graffiti only looks alike
+		}
+		if clients.CLClient == "" && cl != "" {
+			clients.CLClient = cl
+			clients.CLVersion = strings.ToLower(groups["cl_version"])
+		}
+		if clients.ELClient == "" && el != "" {
+			clients.ELClient = el
+			clients.ELVersion = strings.ToLower(groups["el_version"])
+		}
+	}
+	return clients
+}
+
+// client resolves the client of a layer from the fixed client of a rule or the captured
+// code or name, looked up in the rule codes if any, the layer codes and names otherwise.
+// False when something was captured but no client of the layer is known by it.
+func client(fixed string, captured string, ruleCodes map[string]string, codes map[string]string, names map[string]bool) (string, bool) {
+	if fixed != "" {
+		return strings.ToLower(fixed), true
+	}
+	if captured == "" {
+		return "", true
+	}
+	if ruleCodes != nil {
+		codes = ruleCodes
+	}
+	if name, ok := codes[strings.ToUpper(captured)]; ok {
+		return name, true
+	}
+	if names[strings.ToLower(captured)] {
+		return strings.ToLower(captured), true
+	}
+	return "", false
+}
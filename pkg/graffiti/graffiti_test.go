@@ -0,0 +1,65 @@
+package graffiti
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	ruleSet, err := NewRuleSet(nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		graffiti string
+		expected spec.GraffitiClients
+	}{
+		{"GE1a2bLH3c4d", spec.GraffitiClients{CLClient: "lighthouse", CLVersion: "3c4d", ELClient: "geth", ELVersion: "1a2b"}},
+		{"NM77TKa0 stakefish", spec.GraffitiClients{CLClient: "teku", CLVersion: "a0", ELClient: "nethermind", ELVersion: "77"}},
+		{"RHPM", spec.GraffitiClients{CLClient: "prysm", ELClient: "reth"}},
+		{"RP-GL v1.13.1 (solo)", spec.GraffitiClients{CLClient: "lighthouse", ELClient: "geth"}},
+		{"Lighthouse/v5.1.3-a1b2c3d", spec.GraffitiClients{CLClient: "lighthouse", CLVersion: "5.1.3"}},
+		{"teku/v24.1.0 + besu", spec.GraffitiClients{CLClient: "teku", CLVersion: "24.1.0", ELClient: "besu"}},
+		{"ZZLH", spec.GraffitiClients{}},                 // a code of no known client discards the match
+		{"LHGE", spec.GraffitiClients{}},                 // codes of the other layer
+		{"PMTK", spec.GraffitiClients{}},                 // both codes of consensus clients
+		{"GOLD", spec.GraffitiClients{}},                 // looks like codes of no known client
+		{"rethinking prysmatic", spec.GraffitiClients{}}, // names only match whole words
+		{"", spec.GraffitiClients{}},
+	}
+	for _, test := range tests {
+		graffiti := [32]byte{}
+		copy(graffiti[:], test.graffiti)
+		assert.Equal(t, test.expected, ruleSet.Parse(graffiti[:]), test.graffiti)
+	}
+}
+
+func TestReadRulesFile(t *testing.T) {
+	ruleSet, err := ReadRulesFile("")
+	require.NoError(t, err)
+	assert.Len(t, ruleSet.Rules, len(DefaultRules))
+
+	path := filepath.Join(t.TempDir(), "graffiti.json")
+	content := `{
+		"rules": [
+			{"name": "my-pool", "pattern": "^mypool-(?P<cl>[a-z]+)", "el": "Geth"},
+			{"name": "short", "pattern": "^(?P<cl>[A-Z])$", "cl-codes": {"x": "lighthouse"}}
+		],
+		"cl-codes": {"pr": "prysm"}
+	}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	ruleSet, err = ReadRulesFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, spec.GraffitiClients{CLClient: "prysm", ELClient: "geth"}, ruleSet.Parse([]byte("mypool-pr")))
+	assert.Equal(t, spec.GraffitiClients{CLClient: "lighthouse"}, ruleSet.Parse([]byte("X")))
+	assert.Equal(t, spec.GraffitiClients{}, ruleSet.Parse([]byte("GELH")), "the default rules are replaced")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"rules": [{"name": "bad", "pattern": "("}]}`), 0644))
+	_, err = ReadRulesFile(path)
+	assert.Error(t, err)
+}
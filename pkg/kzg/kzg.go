@@ -0,0 +1,57 @@
+package kzg
+
+import (
+	"time"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	moduleName = "kzg"
+	log        = logrus.WithField(
+		"module", moduleName)
+)
+
+// Verifier checks downloaded blobs against their KZG commitments and proofs, using the
+// trusted setup of the Ethereum KZG ceremony embedded in go-eth-kzg.
+type Verifier struct {
+	ctx *goethkzg.Context
+}
+
+// NewVerifier loads the trusted setup, which takes a few seconds.
+func NewVerifier() (*Verifier, error) {
+	start := time.Now()
+	ctx, err := goethkzg.NewContext4096Secure()
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("loaded the KZG trusted setup in %s", time.Since(start))
+	return &Verifier{ctx: ctx}, nil
+}
+
+// Verify recomputes the commitment of the blob and compares it with the one of the
+// sidecar, then verifies the blob proof when the sidecar carries one. Since Fulu the
+// blobs endpoint returns no proofs, so those blobs are only valid_no_proof.
+func (v *Verifier) Verify(blob *spec.AgnosticBlobSidecar, withProof bool) string {
+	commitment, err := v.ctx.BlobToKZGCommitment((*goethkzg.Blob)(&blob.Blob), 1)
+	if err != nil {
+		// the blob is not a list of field elements
+		return spec.KZGInvalidBlob
+	}
+	if commitment != goethkzg.KZGCommitment(blob.KZGCommitment) {
+		return spec.KZGInvalidCommitment
+	}
+	if withProof {
+		err = v.ctx.VerifyBlobKZGProof(
+			(*goethkzg.Blob)(&blob.Blob),
+			goethkzg.KZGCommitment(blob.KZGCommitment),
+			goethkzg.KZGProof(blob.KZGProof))
+		if err != nil {
+			return spec.KZGInvalidProof
+		}
+		return spec.KZGValid
+	}
+	return spec.KZGValidNoProof
+}
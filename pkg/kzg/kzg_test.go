@@ -0,0 +1,57 @@
+package kzg
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/deneb"
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSidecar builds a blob of a few field elements with its commitment and proof
+func newSidecar(t *testing.T, v *Verifier) *spec.AgnosticBlobSidecar {
+	sidecar := &spec.AgnosticBlobSidecar{}
+	for i := 0; i < 64; i++ {
+		// the first byte of every 32 bytes element is left 0, below the modulus
+		sidecar.Blob[i*32+31] = byte(i + 1)
+	}
+	blob := (*goethkzg.Blob)(&sidecar.Blob)
+	commitment, err := v.ctx.BlobToKZGCommitment(blob, 1)
+	require.NoError(t, err)
+	proof, err := v.ctx.ComputeBlobKZGProof(blob, commitment, 1)
+	require.NoError(t, err)
+	sidecar.KZGCommitment = deneb.KZGCommitment(commitment)
+	sidecar.KZGProof = deneb.KZGProof(proof)
+	return sidecar
+}
+
+func TestVerify(t *testing.T) {
+	v, err := NewVerifier()
+	require.NoError(t, err)
+
+	sidecar := newSidecar(t, v)
+	assert.Equal(t, spec.KZGValid, v.Verify(sidecar, true))
+
+	// a proof of another blob
+	other := newSidecar(t, v)
+	other.Blob[31] = 0xaa
+	otherCommitment, err := v.ctx.BlobToKZGCommitment((*goethkzg.Blob)(&other.Blob), 1)
+	require.NoError(t, err)
+	otherProof, err := v.ctx.ComputeBlobKZGProof((*goethkzg.Blob)(&other.Blob), otherCommitment, 1)
+	require.NoError(t, err)
+	sidecar.KZGProof = deneb.KZGProof(otherProof)
+	assert.Equal(t, spec.KZGInvalidProof, v.Verify(sidecar, true))
+	// proofs are not checked since Fulu, which the result tells
+	assert.Equal(t, spec.KZGValidNoProof, v.Verify(sidecar, false))
+
+	tampered := newSidecar(t, v)
+	tampered.Blob[100] = 0x01
+	assert.Equal(t, spec.KZGInvalidCommitment, v.Verify(tampered, false))
+
+	// an element above the modulus
+	invalid := newSidecar(t, v)
+	invalid.Blob[0] = 0xff
+	assert.Equal(t, spec.KZGInvalidBlob, v.Verify(invalid, true))
+}
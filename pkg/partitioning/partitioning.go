@@ -0,0 +1,248 @@
+package partitioning
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/migalabs/goteth/pkg/clientapi"
+	"github.com/migalabs/goteth/pkg/config"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	log = logrus.WithField(
+		"module", "partitioning",
+	)
+)
+
+// TTLPolicy makes ClickHouse expire the rows of Table older than Days, 0 removes the TTL.
+type TTLPolicy struct {
+	Table string
+	Days  int
+}
+
+// ParseTables parses a comma separated list of tables with a partitioned version.
+func ParseTables(input string) ([]string, error) {
+	tables := make([]string, 0)
+	for _, item := range strings.Split(input, ",") {
+		table := strings.TrimSpace(item)
+		if table == "" || slices.Contains(tables, table) {
+			continue
+		}
+		if !slices.Contains(db.PartitionedTables(), table) {
+			return nil, fmt.Errorf("table %s can not be partitioned, supported: %s",
+				table, strings.Join(db.PartitionedTables(), ","))
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// ParseTTL parses a comma separated list of table=days, the days optionally followed
+// by the d suffix, e.g. "t_transactions=30d,t_validator_rewards_summary=0".
+func ParseTTL(input string) ([]TTLPolicy, error) {
+	policies := make([]TTLPolicy, 0)
+	if strings.TrimSpace(input) == "" {
+		return policies, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(input, ",") {
+		table, value, found := strings.Cut(strings.TrimSpace(item), "=")
+		if !found {
+			return nil, fmt.Errorf("could not parse ttl: %s", item)
+		}
+		if !slices.Contains(db.PartitionedTables(), table) {
+			return nil, fmt.Errorf("table %s does not support a ttl, supported: %s",
+				table, strings.Join(db.PartitionedTables(), ","))
+		}
+		if seen[table] {
+			return nil, fmt.Errorf("duplicated ttl for table %s", table)
+		}
+		seen[table] = true
+
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil || days < 0 {
+			return nil, fmt.Errorf("could not parse ttl of table %s: %s", table, value)
+		}
+		policies = append(policies, TTLPolicy{
+			Table: table,
+			Days:  days,
+		})
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Table < policies[j].Table
+	})
+	return policies, nil
+}
+
+type PartitioningRunner struct {
+	ctx      context.Context
+	dbClient *db.DBService
+	tables   []string    // tables to move to their partitioned version
+	ttls     []TTLPolicy // ttl to set per table
+	dropOld  bool        // drop the unpartitioned rows once swapped
+	stop     bool
+}
+
+func NewPartitioningRunner(pCtx context.Context, iConfig config.PartitioningConfig) (*PartitioningRunner, error) {
+	tables, err := ParseTables(iConfig.Tables)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read tables.")
+	}
+	ttls, err := ParseTTL(iConfig.TTL)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read ttl.")
+	}
+
+	// partitions span ~30 days of mainnet slots unless the beacon node tells otherwise
+	options := make([]db.DBServiceOption, 0)
+	if iConfig.BnEndpoint != "" {
+		cli, err := clientapi.NewAPIClient(pCtx,
+			iConfig.BnEndpoint, "", "", "", iConfig.MaxRequestRetries)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to generate API Client.")
+		}
+		secondsPerSlot, err := cli.RequestSecondsPerSlot()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read the seconds per slot.")
+		}
+		slotsPerEpoch, err := cli.RequestSlotsPerEpoch()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read the slots per epoch.")
+		}
+		options = append(options, db.WithSlotTimes(secondsPerSlot, slotsPerEpoch))
+	} else {
+		for _, policy := range ttls {
+			if policy.Days > 0 {
+				return nil, fmt.Errorf("a beacon node endpoint is needed to set the ttl of %s", policy.Table)
+			}
+		}
+	}
+
+	idbClient, err := db.New(pCtx, iConfig.DBUrl, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to generate DB Client.")
+	}
+	err = idbClient.Connect()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to connect DB Client.")
+	}
+
+	return &PartitioningRunner{
+		ctx:      pCtx,
+		dbClient: idbClient,
+		tables:   tables,
+		ttls:     ttls,
+		dropOld:  iConfig.DropOld,
+	}, nil
+}
+
+// Run moves every table to its partitioned version and then sets the configured TTLs.
+func (s *PartitioningRunner) Run() error {
+	defer s.dbClient.Finish()
+
+	for _, table := range s.tables {
+		if err := s.partitionTable(table); err != nil {
+			return fmt.Errorf("could not partition %s: %w", table, err)
+		}
+	}
+	for _, policy := range s.ttls {
+		if err := s.dbClient.SetTableTTL(policy.Table, policy.Days); err != nil {
+			return fmt.Errorf("could not set the ttl of %s: %w", policy.Table, err)
+		}
+		if policy.Days == 0 {
+			log.Infof("removed the ttl of %s", policy.Table)
+		} else {
+			log.Infof("rows of %s now expire after %d days", policy.Table, policy.Days)
+		}
+	}
+	return nil
+}
+
+// partitionTable copies the table into its partitioned version one partition at a time,
+// so the analyzer can keep writing to the table meanwhile, and exchanges both tables at the end.
+// Versioned tables also change their engine to keep the highest version on the way.
+// Partitions already copied by a previous run are skipped. The last partition, the one the
+// analyzer is writing to, is copied again right before the exchange, and once more afterwards
+// from the old rows to catch up with whatever was written in between.
+func (s *PartitioningRunner) partitionTable(table string) error {
+	partitioned, err := s.dbClient.IsPartitioned(table)
+	if err != nil {
+		return err
+	}
+	if partitioned {
+		log.Infof("%s is already partitioned", table)
+		return s.dropUnpartitioned(table)
+	}
+
+	if err := s.dbClient.PreparePartitionedTable(table, s.dropOld); err != nil {
+		return err
+	}
+
+	first, last, rows, err := s.dbClient.PartitionRange(table)
+	if err != nil {
+		return err
+	}
+	log.Infof("copying %d rows of %s in partitions %d - %d", rows, table, first, last)
+	if rows > 0 {
+		for partition := first; partition < last; partition++ {
+			if s.stop || s.ctx.Err() != nil {
+				return fmt.Errorf("stopped at partition %d, run again to resume", partition)
+			}
+			copied, err := s.dbClient.CopyPartition(table, partition, false)
+			if err != nil {
+				return err
+			}
+			if !copied {
+				log.Debugf("partition %d of %s already copied", partition, table)
+			}
+		}
+	}
+
+	// the head may have moved to a new partition while copying
+	_, head, _, err := s.dbClient.PartitionRange(table)
+	if err != nil {
+		return err
+	}
+	if rows > 0 {
+		for partition := last; partition <= head; partition++ {
+			if _, err := s.dbClient.CopyPartition(table, partition, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.dbClient.SwapPartitionedTable(table); err != nil {
+		return err
+	}
+	log.Infof("%s swapped with its partitioned version", table)
+
+	if rows > 0 {
+		if err := s.dbClient.CatchUpPartition(table, head); err != nil {
+			return err
+		}
+	}
+	return s.dropUnpartitioned(table)
+}
+
+func (s *PartitioningRunner) dropUnpartitioned(table string) error {
+	if !s.dropOld {
+		return nil
+	}
+	if err := s.dbClient.DropUnpartitionedTable(table); err != nil {
+		return err
+	}
+	log.Infof("dropped the unpartitioned rows of %s", table)
+	return nil
+}
+
+func (s *PartitioningRunner) Close() {
+	s.stop = true
+}
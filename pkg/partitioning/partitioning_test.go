@@ -0,0 +1,41 @@
+package partitioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTables(t *testing.T) {
+	tables, err := ParseTables("t_validator_rewards_summary, t_transactions,t_transactions")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t_validator_rewards_summary", "t_transactions"}, tables)
+
+	_, err = ParseTables("t_transactions,t_head_events")
+	assert.Error(t, err)
+}
+
+func TestParseTTL(t *testing.T) {
+	policies, err := ParseTTL("t_validator_rewards_summary=0,t_transactions=90d")
+	require.NoError(t, err)
+	assert.Equal(t, []TTLPolicy{
+		{Table: "t_transactions", Days: 90},
+		{Table: "t_validator_rewards_summary", Days: 0},
+	}, policies)
+
+	policies, err = ParseTTL("")
+	require.NoError(t, err)
+	assert.Empty(t, policies)
+
+	for _, input := range []string{
+		"t_transactions",
+		"t_transactions=-1",
+		"t_transactions=ten",
+		"t_head_events=10",
+		"t_transactions=1,t_transactions=2",
+	} {
+		_, err := ParseTTL(input)
+		assert.Error(t, err, input)
+	}
+}
@@ -3,6 +3,7 @@ package relay
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"sync"
 	"time"
 
@@ -114,6 +115,30 @@ func (r *RelayClient) GetDeliveredBidsPerSlotRange(slot phase0.Slot, limit int)
 
 }
 
+// GetTopReceivedBidValue returns the highest value of the bids the relay received from
+// builders for the slot, whether or not any payload was delivered.
+func (r *RelayClient) GetTopReceivedBidValue(slot phase0.Slot) (*big.Int, error) {
+
+	if r.isOpen() {
+		return nil, fmt.Errorf("circuit breaker open for %s, skipping", r.address)
+	}
+
+	bidsReceived, err := r.client.(relayclient.ReceivedBidTracesProvider).ReceivedBidTraces(r.ctx, slot)
+	if err != nil {
+		r.recordResult(true)
+		return nil, fmt.Errorf("error obtaining received bid traces from %s: %s", r.address, err)
+	}
+	r.recordResult(false)
+
+	topValue := new(big.Int)
+	for _, bid := range bidsReceived {
+		if bid.Value != nil && bid.Value.Cmp(topValue) > 0 {
+			topValue.Set(bid.Value)
+		}
+	}
+	return topValue, nil
+}
+
 type RelaysMonitor struct {
 	relays []*RelayClient
 }
@@ -168,6 +193,39 @@ func (m RelaysMonitor) GetDeliveredBidsPerSlotRange(slot phase0.Slot, limit int)
 	return bidsDelivered, nil
 }
 
+// Returns the highest bid received by any relay from builders at each of the slots,
+// also known for slots where the proposer built the block locally
+func (m RelaysMonitor) GetTopReceivedBidValues(slots []phase0.Slot) map[phase0.Slot]*big.Int {
+	var mu sync.Mutex
+	topValues := make(map[phase0.Slot]*big.Int)
+
+	var wg sync.WaitGroup
+
+	for _, relayClient := range m.relays {
+		wg.Add(1)
+		go func(rc *RelayClient) {
+			defer wg.Done()
+
+			for _, slot := range slots {
+				value, err := rc.GetTopReceivedBidValue(slot)
+				if err != nil {
+					log.Errorf("%s", err)
+					continue
+				}
+				mu.Lock()
+				if topValues[slot] == nil || value.Cmp(topValues[slot]) > 0 {
+					topValues[slot] = value
+				}
+				mu.Unlock()
+			}
+		}(relayClient)
+	}
+
+	wg.Wait()
+
+	return topValues
+}
+
 type RelayBidsPerSlot struct {
 	mu   sync.Mutex
 	bids map[phase0.Slot]map[string]*v1.BidTrace
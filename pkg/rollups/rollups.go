@@ -0,0 +1,109 @@
+package rollups
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	moduleName = "rollups"
+	log        = logrus.WithField(
+		"module", moduleName)
+)
+
+// Unknown is the name given to blob transactions of no registered rollup
+const Unknown = "unknown"
+
+// Rollup gathers the addresses a rollup posts its blob transactions from or to.
+type Rollup struct {
+	Name string   `json:"name"`
+	From []string `json:"from"` // batcher addresses sending the blob transactions
+	To   []string `json:"to"`   // inbox addresses receiving them
+}
+
+// Registry attributes blob transactions to rollups, as read from a rollups file:
+//
+//	{
+//	  "rollups": [{"name": "base", "from": ["0x5050..."], "to": ["0xff00..."]}]
+//	}
+type Registry struct {
+	Rollups []Rollup `json:"rollups"`
+
+	byFrom map[common.Address]string
+	byTo   map[common.Address]string
+}
+
+// NewRegistry indexes the addresses of the given rollups.
+func NewRegistry(rollups []Rollup) (*Registry, error) {
+	r := &Registry{
+		Rollups: rollups,
+		byFrom:  make(map[common.Address]string),
+		byTo:    make(map[common.Address]string),
+	}
+	for i, rollup := range rollups {
+		if rollup.Name == "" {
+			return nil, fmt.Errorf("rollup %d has no name", i)
+		}
+		if err := indexAddresses(r.byFrom, rollup.Name, rollup.From); err != nil {
+			return nil, err
+		}
+		if err := indexAddresses(r.byTo, rollup.Name, rollup.To); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func indexAddresses(index map[common.Address]string, name string, addresses []string) error {
+	for _, address := range addresses {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid address of rollup %s: %s", name, address)
+		}
+		key := common.HexToAddress(address)
+		if existing, ok := index[key]; ok && existing != name {
+			return fmt.Errorf("address %s registered for %s and %s", address, existing, name)
+		}
+		index[key] = name
+	}
+	return nil
+}
+
+// ReadRegistryFile reads a registry from a JSON file. An empty path disables the attribution.
+func ReadRegistryFile(path string) (*Registry, error) {
+	if path == "" {
+		return nil, nil
+	}
+	log.Infof("reading rollups from: %s", path)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file Registry
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("could not parse rollups file %s: %w", path, err)
+	}
+	registry, err := NewRegistry(file.Rollups)
+	if err != nil {
+		return nil, fmt.Errorf("could not read rollups file %s: %w", path, err)
+	}
+	log.Infof("read %d rollups", len(registry.Rollups))
+	return registry, nil
+}
+
+// Identify returns the rollup a blob transaction belongs to, by its sender first as
+// batchers are dedicated accounts, then by its recipient. Unknown when none matches.
+func (r *Registry) Identify(from common.Address, to *common.Address) string {
+	if name, ok := r.byFrom[from]; ok {
+		return name
+	}
+	if to != nil {
+		if name, ok := r.byTo[*to]; ok {
+			return name
+		}
+	}
+	return Unknown
+}
@@ -0,0 +1,62 @@
+package rollups
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	baseBatcher = common.HexToAddress("0x5050F69a9786F081509234F1a7F4684b5E5b76C9")
+	baseInbox   = common.HexToAddress("0xFF00000000000000000000000000000000008453")
+	opInbox     = common.HexToAddress("0xFF00000000000000000000000000000000000010")
+)
+
+func TestIdentify(t *testing.T) {
+	registry, err := NewRegistry([]Rollup{
+		{Name: "base", From: []string{baseBatcher.String()}, To: []string{baseInbox.String()}},
+		{Name: "optimism", To: []string{"0xff00000000000000000000000000000000000010"}},
+	})
+	require.NoError(t, err)
+
+	other := common.HexToAddress("0x01")
+	assert.Equal(t, "base", registry.Identify(baseBatcher, &other))
+	assert.Equal(t, "base", registry.Identify(other, &baseInbox))
+	assert.Equal(t, "optimism", registry.Identify(other, &opInbox))
+	// the sender decides when both match
+	assert.Equal(t, "base", registry.Identify(baseBatcher, &opInbox))
+	assert.Equal(t, Unknown, registry.Identify(other, nil))
+}
+
+func TestNewRegistryErrors(t *testing.T) {
+	_, err := NewRegistry([]Rollup{{From: []string{baseBatcher.String()}}})
+	assert.Error(t, err)
+	_, err = NewRegistry([]Rollup{{Name: "base", From: []string{"0x1234"}}})
+	assert.Error(t, err)
+	_, err = NewRegistry([]Rollup{
+		{Name: "base", To: []string{baseInbox.String()}},
+		{Name: "other", To: []string{baseInbox.String()}},
+	})
+	assert.Error(t, err)
+}
+
+func TestReadRegistryFile(t *testing.T) {
+	registry, err := ReadRegistryFile("")
+	require.NoError(t, err)
+	assert.Nil(t, registry)
+
+	path := filepath.Join(t.TempDir(), "rollups.json")
+	content := `{"rollups": [{"name": "base", "from": ["0x5050F69a9786F081509234F1a7F4684b5E5b76C9"]}]}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	registry, err = ReadRegistryFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "base", registry.Identify(baseBatcher, nil))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"rollups": [`), 0o644))
+	_, err = ReadRegistryFile(path)
+	assert.Error(t, err)
+}
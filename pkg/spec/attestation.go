@@ -0,0 +1,64 @@
+package spec
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// An attestation as included in a block, for any fork
+type AgnosticAttestation struct {
+	Slot             phase0.Slot  // slot of the block including the attestation
+	BlockRoot        phase0.Root  // root of the block including the attestation
+	Index            uint64       // position of the attestation in the block
+	AttestationSlot  phase0.Slot  // slot the attestation votes for
+	CommitteeIndices []uint64     // committees of the aggregate, more than one since electra
+	BeaconBlockRoot  phase0.Root  // head vote
+	SourceEpoch      phase0.Epoch // source checkpoint vote
+	TargetEpoch      phase0.Epoch // target checkpoint vote
+	TargetRoot       phase0.Root  // target checkpoint vote
+	AggregationBits  []byte       // raw aggregation bitlist
+	AttestingBits    uint64       // number of aggregation bits set
+}
+
+func (f AgnosticAttestation) Type() ModelType {
+	return AttestationModel
+}
+
+func ParseAttestationsFromBlock(block AgnosticBlock) []AgnosticAttestation {
+	attestations := make([]AgnosticAttestation, 0, len(block.Attestations)+len(block.ElectraAttestations))
+
+	for i, item := range block.Attestations {
+		attestations = append(attestations, AgnosticAttestation{
+			Slot:             block.Slot,
+			BlockRoot:        block.Root,
+			Index:            uint64(i),
+			AttestationSlot:  item.Data.Slot,
+			CommitteeIndices: []uint64{uint64(item.Data.Index)},
+			BeaconBlockRoot:  item.Data.BeaconBlockRoot,
+			SourceEpoch:      item.Data.Source.Epoch,
+			TargetEpoch:      item.Data.Target.Epoch,
+			TargetRoot:       item.Data.Target.Root,
+			AggregationBits:  item.AggregationBits.Bytes(),
+			AttestingBits:    item.AggregationBits.Count(),
+		})
+	}
+	for i, item := range block.ElectraAttestations {
+		committees := make([]uint64, 0)
+		for _, committee := range item.CommitteeBits.BitIndices() {
+			committees = append(committees, uint64(committee))
+		}
+		attestations = append(attestations, AgnosticAttestation{
+			Slot:             block.Slot,
+			BlockRoot:        block.Root,
+			Index:            uint64(i),
+			AttestationSlot:  item.Data.Slot,
+			CommitteeIndices: committees,
+			BeaconBlockRoot:  item.Data.BeaconBlockRoot,
+			SourceEpoch:      item.Data.Source.Epoch,
+			TargetEpoch:      item.Data.Target.Epoch,
+			TargetRoot:       item.Data.Target.Root,
+			AggregationBits:  item.AggregationBits.Bytes(),
+			AttestingBits:    item.AggregationBits.Count(),
+		})
+	}
+	return attestations
+}
@@ -0,0 +1,70 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func testAttestationData(slot phase0.Slot, index phase0.CommitteeIndex) *phase0.AttestationData {
+	return &phase0.AttestationData{
+		Slot:            slot,
+		Index:           index,
+		BeaconBlockRoot: phase0.Root{1},
+		Source:          &phase0.Checkpoint{Epoch: 2, Root: phase0.Root{2}},
+		Target:          &phase0.Checkpoint{Epoch: 3, Root: phase0.Root{3}},
+	}
+}
+
+func TestParseAttestationsFromBlock(t *testing.T) {
+	bits := bitfield.NewBitlist(16)
+	bits.SetBitAt(1, true)
+	bits.SetBitAt(5, true)
+
+	block := spec.AgnosticBlock{
+		Slot: 100,
+		Root: phase0.Root{9},
+		Attestations: []*phase0.Attestation{
+			{AggregationBits: bitfield.NewBitlist(16), Data: testAttestationData(99, 4)},
+			{AggregationBits: bits, Data: testAttestationData(98, 7)},
+		},
+	}
+	attestations := spec.ParseAttestationsFromBlock(block)
+	if len(attestations) != 2 {
+		t.Fatalf("expected 2 attestations, got %d", len(attestations))
+	}
+	second := attestations[1]
+	if second.Slot != 100 || second.BlockRoot != block.Root || second.Index != 1 {
+		t.Errorf("unexpected block fields: %+v", second)
+	}
+	if second.AttestationSlot != 98 || len(second.CommitteeIndices) != 1 || second.CommitteeIndices[0] != 7 {
+		t.Errorf("unexpected vote fields: %+v", second)
+	}
+	if second.SourceEpoch != 2 || second.TargetEpoch != 3 || second.TargetRoot != (phase0.Root{3}) {
+		t.Errorf("unexpected checkpoints: %+v", second)
+	}
+	if second.AttestingBits != 2 {
+		t.Errorf("expected 2 attesting bits, got %d", second.AttestingBits)
+	}
+
+	committees := bitfield.NewBitvector64()
+	committees.SetBitAt(3, true)
+	committees.SetBitAt(12, true)
+	electraBlock := spec.AgnosticBlock{
+		Slot: 200,
+		ElectraAttestations: []*electra.Attestation{
+			{AggregationBits: bits, Data: testAttestationData(199, 0), CommitteeBits: committees},
+		},
+	}
+	attestations = spec.ParseAttestationsFromBlock(electraBlock)
+	if len(attestations) != 1 {
+		t.Fatalf("expected 1 attestation, got %d", len(attestations))
+	}
+	indices := attestations[0].CommitteeIndices
+	if len(indices) != 2 || indices[0] != 3 || indices[1] != 12 {
+		t.Errorf("expected committees [3 12], got %v", indices)
+	}
+}
@@ -0,0 +1,62 @@
+package spec
+
+import (
+	"math/big"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// An authorization tuple of a set code transaction (EIP-7702), delegating the code
+// of the authority account to another address.
+type AgnosticAuthorization struct {
+	Slot      phase0.Slot    // slot of the block including the transaction
+	BlockRoot phase0.Root    // root of the block including the transaction
+	TxHash    phase0.Hash32  // hash of the set code transaction
+	TxIdx     uint64         // index of the transaction in the block
+	Index     uint64         // position of the authorization in the list
+	ChainId   *big.Int       // chain the authorization is valid for, 0 for any
+	Address   common.Address // address whose code is delegated to, zero to clear the delegation
+	Nonce     uint64         // nonce of the authority account
+	Authority common.Address // signer of the authorization, zero if it cannot be recovered
+	Valid     bool           // whether the signature could be recovered
+}
+
+func (f AgnosticAuthorization) Type() ModelType {
+	return AuthorizationModel
+}
+
+// AuthorityString returns the hex authority, empty if it could not be recovered.
+func (f AgnosticAuthorization) AuthorityString() string {
+	if !f.Valid {
+		return ""
+	}
+	return f.Authority.String()
+}
+
+// ParseAuthorizations reads the authorization list of a set code transaction. The
+// authority is recovered from the signature; whether the execution layer applied
+// the authorization (chain ID, nonce) is not checked.
+func ParseAuthorizations(tx *types.Transaction, slot phase0.Slot, txIdx uint64) []AgnosticAuthorization {
+	list := tx.SetCodeAuthorizations()
+	authorizations := make([]AgnosticAuthorization, 0, len(list))
+	for i, item := range list {
+		authority, err := item.Authority()
+		if err != nil {
+			log.Debugf("could not recover authority %d of tx %s: %s", i, tx.Hash(), err)
+		}
+		authorizations = append(authorizations, AgnosticAuthorization{
+			Slot:      slot,
+			TxHash:    phase0.Hash32(tx.Hash()),
+			TxIdx:     txIdx,
+			Index:     uint64(i),
+			ChainId:   item.ChainID.ToBig(),
+			Address:   item.Address,
+			Nonce:     item.Nonce,
+			Authority: authority,
+			Valid:     err == nil,
+		})
+	}
+	return authorizations
+}
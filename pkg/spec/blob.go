@@ -17,6 +17,23 @@ var (
 	versionedHashVersionKZG = []byte("0x01")
 )
 
+const BytesPerBlob = len(deneb.Blob{})
+
+// results of the KZG verification of a blob sidecar, empty when not verified
+const (
+	KZGValid             = "valid"
+	KZGValidNoProof      = "valid_no_proof"     // matches its commitment, no proof to verify since Fulu
+	KZGInvalidBlob       = "invalid_blob"       // the blob does not deserialize into field elements
+	KZGInvalidCommitment = "invalid_commitment" // the blob does not match its commitment
+	KZGInvalidProof      = "invalid_proof"
+	KZGHashMismatch      = "hash_mismatch" // the versioned hash is not the one of the block transactions
+)
+
+// KZGVerified tells whether the blob passed the KZG verification, with or without proof.
+func KZGVerified(result string) bool {
+	return result == KZGValid || result == KZGValidNoProof
+}
+
 type AgnosticBlobSidecar struct {
 	Slot                        phase0.Slot // slot the blob belongs to
 	TxHash                      common.Hash // has of the transactions that references this blob in this slot
@@ -28,6 +45,9 @@ type AgnosticBlobSidecar struct {
 	KZGProof                    deneb.KZGProof
 	SignedBlockHeader           *phase0.SignedBeaconBlockHeader
 	KZGCommitmentInclusionProof deneb.KZGCommitmentInclusionProof
+	BlockRoot                   phase0.Root // root of the block the blob belongs to
+	KZGVerification             string      // result of the KZG verification, empty when disabled
+	StorageKey                  string      // key of the blob in the blob store, empty when not stored
 }
 
 func NewAgnosticBlobFromAPI(slot phase0.Slot, blob deneb.BlobSidecar) (*AgnosticBlobSidecar, error) {
@@ -45,6 +65,20 @@ func NewAgnosticBlobFromAPI(slot phase0.Slot, blob deneb.BlobSidecar) (*Agnostic
 	}, nil
 }
 
+// BlobFill is how much of a blob the transaction posting it used, kept on the block
+// so that the sidecars themselves can be released
+type BlobFill struct {
+	TxHash    common.Hash
+	UsedBytes uint64 // bytes before the trailing zeros
+}
+
+func (b *AgnosticBlobSidecar) Fill() BlobFill {
+	return BlobFill{
+		TxHash:    b.TxHash,
+		UsedBytes: uint64(BytesPerBlob - b.BlobEnding0s),
+	}
+}
+
 func (b *AgnosticBlobSidecar) GetTxHash(txs []AgnosticTransaction) {
 
 	for _, tx := range txs {
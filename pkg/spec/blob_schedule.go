@@ -0,0 +1,146 @@
+package spec
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+const (
+	GasPerBlob           = 1 << 17 // blob gas consumed by a blob
+	MinBaseFeePerBlobGas = 1       // Wei
+)
+
+var (
+	// Deneb blob limits, the base fee update fraction of the later forks is scaled from it
+	denebBlobParameters = BlobParameters{Target: 3, Max: 6, BaseFeeUpdateFraction: 3338477}
+
+	// base fee update fractions of the blob schedules of the execution clients by
+	// maximum blobs per block: Prague, BPO1 and BPO2
+	knownUpdateFractions = map[uint64]uint64{
+		9:  5007716,
+		15: 8346193,
+		21: 11684671,
+	}
+)
+
+// BlobParameters are the blob limits in force from an epoch on
+type BlobParameters struct {
+	Epoch                 phase0.Epoch
+	Target                uint64 // blobs per block
+	Max                   uint64 // blobs per block
+	BaseFeeUpdateFraction uint64
+}
+
+// BaseFee returns the blob base fee per blob gas for the excess blob gas of a block.
+// Zero before Deneb.
+func (p BlobParameters) BaseFee(excessBlobGas uint64) *big.Int {
+	if p.BaseFeeUpdateFraction == 0 {
+		return new(big.Int)
+	}
+	return fakeExponential(
+		big.NewInt(MinBaseFeePerBlobGas),
+		new(big.Int).SetUint64(excessBlobGas),
+		new(big.Int).SetUint64(p.BaseFeeUpdateFraction))
+}
+
+// newBlobParameters completes the maximum blobs set in the beacon spec with the target and update
+// fraction of the execution clients. Since Electra the target is two thirds of the maximum.
+func newBlobParameters(epoch phase0.Epoch, max uint64) BlobParameters {
+	params := BlobParameters{
+		Epoch:                 epoch,
+		Target:                max * 2 / 3,
+		Max:                   max,
+		BaseFeeUpdateFraction: knownUpdateFractions[max],
+	}
+	if params.BaseFeeUpdateFraction == 0 {
+		// unknown schedule, the fractions scale with the maximum
+		params.BaseFeeUpdateFraction = (denebBlobParameters.BaseFeeUpdateFraction*max + denebBlobParameters.Max - 1) / denebBlobParameters.Max
+		log.Warnf("unknown blob base fee update fraction for %d blobs at epoch %d, using %d",
+			max, epoch, params.BaseFeeUpdateFraction)
+	}
+	return params
+}
+
+// BlobSchedule holds the blob parameters of the network, sorted by epoch
+type BlobSchedule []BlobParameters
+
+// NewBlobSchedule reads the blob limits from the beacon spec (/eth/v1/config/spec): Deneb,
+// Electra and the BLOB_SCHEDULE of the blob parameter only forks since Fulu.
+func NewBlobSchedule(config map[string]any) BlobSchedule {
+	byEpoch := make(map[phase0.Epoch]BlobParameters)
+	if epoch, ok := specUint64(config["DENEB_FORK_EPOCH"]); ok {
+		params := denebBlobParameters
+		if max, ok := specUint64(config["MAX_BLOBS_PER_BLOCK"]); ok && max != params.Max {
+			params = newBlobParameters(phase0.Epoch(epoch), max)
+			params.Target = max / 2 // half of the maximum before Electra
+		}
+		params.Epoch = phase0.Epoch(epoch)
+		byEpoch[params.Epoch] = params
+	}
+	if epoch, ok := specUint64(config["ELECTRA_FORK_EPOCH"]); ok {
+		if max, ok := specUint64(config["MAX_BLOBS_PER_BLOCK_ELECTRA"]); ok {
+			byEpoch[phase0.Epoch(epoch)] = newBlobParameters(phase0.Epoch(epoch), max)
+		}
+	}
+	entries, _ := config["BLOB_SCHEDULE"].([]any)
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		epoch, okEpoch := specUint64(fields["EPOCH"])
+		max, okMax := specUint64(fields["MAX_BLOBS_PER_BLOCK"])
+		if okEpoch && okMax {
+			byEpoch[phase0.Epoch(epoch)] = newBlobParameters(phase0.Epoch(epoch), max)
+		}
+	}
+
+	schedule := make(BlobSchedule, 0, len(byEpoch))
+	for _, params := range byEpoch {
+		schedule = append(schedule, params)
+	}
+	sort.Slice(schedule, func(i, j int) bool {
+		return schedule[i].Epoch < schedule[j].Epoch
+	})
+	return schedule
+}
+
+// At returns the blob parameters in force at the epoch, empty before Deneb.
+func (s BlobSchedule) At(epoch phase0.Epoch) BlobParameters {
+	params := BlobParameters{}
+	for _, item := range s {
+		if item.Epoch > epoch {
+			break
+		}
+		params = item
+	}
+	return params
+}
+
+// specUint64 reads a number of the beacon spec, parsed as uint64 or as a typed epoch
+func specUint64(value any) (uint64, bool) {
+	switch v := value.(type) {
+	case uint64:
+		return v, true
+	case phase0.Epoch:
+		return uint64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// https://eips.ethereum.org/EIPS/eip-4844#helpers
+func fakeExponential(factor, numerator, denominator *big.Int) *big.Int {
+	output := new(big.Int)
+	accum := new(big.Int).Mul(factor, denominator)
+	for i := int64(1); accum.Sign() > 0; i++ {
+		output.Add(output, accum)
+
+		accum.Mul(accum, numerator)
+		accum.Div(accum, denominator)
+		accum.Div(accum, big.NewInt(i))
+	}
+	return output.Div(output, denominator)
+}
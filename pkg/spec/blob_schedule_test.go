@@ -0,0 +1,97 @@
+package spec_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blob limits of mainnet as returned by /eth/v1/config/spec
+var mainnetBlobConfig = map[string]any{
+	"DENEB_FORK_EPOCH":            uint64(269568),
+	"ELECTRA_FORK_EPOCH":          uint64(364032),
+	"FULU_FORK_EPOCH":             uint64(411392),
+	"MAX_BLOBS_PER_BLOCK":         uint64(6),
+	"MAX_BLOBS_PER_BLOCK_ELECTRA": uint64(9),
+	"BLOB_SCHEDULE": []any{
+		map[string]any{"EPOCH": uint64(412672), "MAX_BLOBS_PER_BLOCK": uint64(15)},
+		map[string]any{"EPOCH": uint64(419072), "MAX_BLOBS_PER_BLOCK": uint64(21)},
+	},
+}
+
+func TestBlobSchedule(t *testing.T) {
+	schedule := spec.NewBlobSchedule(mainnetBlobConfig)
+	require.Len(t, schedule, 4)
+
+	tests := []struct {
+		epoch    phase0.Epoch
+		target   uint64
+		max      uint64
+		fraction uint64
+	}{
+		{269567, 0, 0, 0}, // Capella
+		{269568, 3, 6, 3338477},
+		{364031, 3, 6, 3338477},
+		{364032, 6, 9, 5007716},
+		{411392, 6, 9, 5007716}, // Fulu keeps the Electra limits
+		{412672, 10, 15, 8346193},
+		{419072, 14, 21, 11684671},
+		{math.MaxUint64, 14, 21, 11684671},
+	}
+	for _, test := range tests {
+		params := schedule.At(test.epoch)
+		assert.Equal(t, test.target, params.Target, "epoch %d", test.epoch)
+		assert.Equal(t, test.max, params.Max, "epoch %d", test.epoch)
+		assert.Equal(t, test.fraction, params.BaseFeeUpdateFraction, "epoch %d", test.epoch)
+	}
+}
+
+func TestBlobScheduleUnknownForks(t *testing.T) {
+	// not scheduled forks are far in the future, unknown limits get an estimated fraction
+	schedule := spec.NewBlobSchedule(map[string]any{
+		"DENEB_FORK_EPOCH":            uint64(0),
+		"ELECTRA_FORK_EPOCH":          uint64(math.MaxUint64),
+		"MAX_BLOBS_PER_BLOCK":         uint64(6),
+		"MAX_BLOBS_PER_BLOCK_ELECTRA": uint64(9),
+		"BLOB_SCHEDULE": []any{
+			map[string]any{"EPOCH": uint64(10), "MAX_BLOBS_PER_BLOCK": uint64(12)},
+		},
+	})
+	params := schedule.At(10)
+	assert.Equal(t, uint64(8), params.Target)
+	assert.Equal(t, uint64(12), params.Max)
+	assert.Equal(t, uint64(6676954), params.BaseFeeUpdateFraction)
+	assert.Equal(t, uint64(6), schedule.At(9).Max)
+
+	assert.Empty(t, spec.NewBlobSchedule(map[string]any{}))
+}
+
+func TestBlobBaseFee(t *testing.T) {
+	deneb := spec.BlobParameters{Target: 3, Max: 6, BaseFeeUpdateFraction: 3338477}
+	tests := []struct {
+		params spec.BlobParameters
+		excess uint64
+		fee    string
+	}{
+		{spec.BlobParameters{}, 10 * 1024 * 1024, "0"},
+		{deneb, 0, "1"},
+		{deneb, 2314057, "1"},
+		{deneb, 2314058, "2"},
+		{deneb, 10 * 1024 * 1024, "23"},
+		{spec.BlobParameters{BaseFeeUpdateFraction: 8346193}, 10 * 1024 * 1024, "3"},
+		{spec.BlobParameters{BaseFeeUpdateFraction: 11684671}, 100_000_000, "5209"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.fee, test.params.BaseFee(test.excess).String(), "excess %d", test.excess)
+	}
+
+	block := spec.AgnosticBlock{BlobParameters: deneb}
+	block.ExecutionPayload.BlobGasUsed = 5 * spec.GasPerBlob
+	block.ExecutionPayload.ExcessBlobGas = 2314058
+	assert.Equal(t, uint64(5), block.Blobs())
+	assert.Equal(t, "2", block.BlobBaseFee().String())
+}
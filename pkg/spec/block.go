@@ -3,7 +3,7 @@ package spec
 import (
 	"encoding/binary"
 	"fmt"
-	"math"
+	"math/big"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec"
@@ -25,6 +25,7 @@ type AgnosticBlock struct {
 	ParentRoot            phase0.Root
 	ProposerIndex         phase0.ValidatorIndex
 	Graffiti              [32]byte
+	GraffitiClients       GraffitiClients // set when processed
 	Proposed              bool
 	Attestations          []*phase0.Attestation // For electra blocks, Attestations is nil
 	VotesIncluded         uint64
@@ -46,6 +47,9 @@ type AgnosticBlock struct {
 	ElectraAttestations      []*electra.Attestation
 	ElectraAttesterSlashings []*electra.AttesterSlashing
 	ExecutionRequests        *electra.ExecutionRequests
+	// Deneb
+	BlobParameters BlobParameters // blob limits of the epoch of the block, set when downloaded
+	BlobsFill      []BlobFill     // set once the blob sidecars are matched with the transactions
 }
 
 // This Wrapper is meant to include all common objects across Ethereum Hard Fork Specs
@@ -61,59 +65,60 @@ type AgnosticExecutionPayload struct {
 	BlockNumber          uint64
 	Withdrawals          []*capella.Withdrawal
 	PayloadSize          uint32
+	ExtraData            []byte
+	PrevRandao           [32]byte
+	ParentHash           phase0.Hash32
+	LogsBloom            [256]byte
+	BlobGasUsed          uint64
+	ExcessBlobGas        uint64
 }
 
 func (f AgnosticBlock) Type() ModelType {
 	return BlockModel
 }
 
-func (p AgnosticBlock) BlockGasFees() (uint64, uint64, error) {
-	reward := uint64(0)
-	burn := uint64(0)
+// Blobs returns the number of blobs included in the block
+func (p AgnosticBlock) Blobs() uint64 {
+	return p.ExecutionPayload.BlobGasUsed / GasPerBlob
+}
+
+// BlobBaseFee returns the price per blob gas of the block in Wei
+func (p AgnosticBlock) BlobBaseFee() *big.Int {
+	return p.BlobParameters.BaseFee(p.ExecutionPayload.ExcessBlobGas)
+}
+
+// BlockFees sums what the transactions of the block paid for gas, in Wei
+type BlockFees struct {
+	Tips          *big.Int // priority fees paid to the fee recipient
+	BurntFees     *big.Int // base fees burnt
+	BlobBurntFees *big.Int // blob fees burnt
+}
+
+// BlockFees computes the exact fees of the block from its parsed transactions.
+func (p AgnosticBlock) BlockFees() (BlockFees, error) {
+	fees := BlockFees{
+		Tips:          new(big.Int),
+		BurntFees:     new(big.Int),
+		BlobBurntFees: new(big.Int),
+	}
 	baseFeePerGas := p.ExecutionPayload.BaseFeePerGas
 
 	if len(p.ExecutionPayload.AgnosticTransactions) == 0 {
-		return reward, burn, fmt.Errorf("cannot calculate block reward: no transactions appended")
+		return fees, fmt.Errorf("cannot calculate block reward: no transactions appended")
 	}
 
-	// Max reasonable fee per transaction: 1000 ETH in Wei (sanity cap)
-	// Note: 1e21 exceeds uint64 max, using 1e19 (~184 ETH) as practical cap
-	const maxReasonableTxFee uint64 = 10000000000000000000
-
 	for _, tx := range p.ExecutionPayload.AgnosticTransactions {
-		// Check for underflow: GasPrice < baseFee should not happen but protect against it
-		if tx.GasPrice < baseFeePerGas {
-			logrus.Warnf("Slot %d: Transaction gas price (%d) < base fee (%d), skipping",
-				p.Slot, tx.GasPrice, baseFeePerGas)
-			continue
+		if tx.GasPrice == nil || tx.GasPrice.Cmp(new(big.Int).SetUint64(baseFeePerGas)) < 0 {
+			logrus.Warnf("Slot %d: Transaction %s gas price (%s) < base fee (%d), no tip counted",
+				p.Slot, tx.Hash.String(), tx.GasPrice, baseFeePerGas)
 		}
-
-		priorityFee := (tx.GasPrice - baseFeePerGas) * tx.Gas
-		baseFee := baseFeePerGas * uint64(tx.Gas)
-
-		// Sanity check: no single transaction should exceed 1000 ETH in fees
-		if priorityFee > maxReasonableTxFee {
-			logrus.Warnf("Slot %d: Priority fee %d exceeds max reasonable (%d), likely overflow or corrupted data, skipping",
-				p.Slot, priorityFee, maxReasonableTxFee)
-			continue
-		}
-
-		// Check for overflow before accumulation
-		if math.MaxUint64-reward < priorityFee {
-			logrus.Errorf("Slot %d: Overflow detected when accumulating priority fees", p.Slot)
-			return reward, burn, fmt.Errorf("fee accumulation overflow at slot %d", p.Slot)
-		}
-		if math.MaxUint64-burn < baseFee {
-			logrus.Errorf("Slot %d: Overflow detected when accumulating base fees", p.Slot)
-			return reward, burn, fmt.Errorf("burn accumulation overflow at slot %d", p.Slot)
-		}
-
-		reward += priorityFee
-		burn += baseFee
+		txFees := tx.ComputeFees(baseFeePerGas)
+		fees.Tips.Add(fees.Tips, txFees.Tip)
+		fees.BurntFees.Add(fees.BurntFees, txFees.BurntFee)
+		fees.BlobBurntFees.Add(fees.BlobBurntFees, txFees.BlobBurntFee)
 	}
 
-	return reward, burn, nil
-
+	return fees, nil
 }
 
 func GetCustomBlock(block spec.VersionedSignedBeaconBlock) (AgnosticBlock, error) {
@@ -254,10 +259,14 @@ func NewBellatrixBlock(block spec.VersionedSignedBeaconBlock) AgnosticBlock {
 			GasLimit:      block.Bellatrix.Message.Body.ExecutionPayload.GasLimit,
 			GasUsed:       block.Bellatrix.Message.Body.ExecutionPayload.GasUsed,
 			Timestamp:     block.Bellatrix.Message.Body.ExecutionPayload.Timestamp,
-			BaseFeePerGas: binary.BigEndian.Uint64(block.Bellatrix.Message.Body.ExecutionPayload.BaseFeePerGas[:]),
+			BaseFeePerGas: binary.LittleEndian.Uint64(block.Bellatrix.Message.Body.ExecutionPayload.BaseFeePerGas[:8]),
 			BlockHash:     block.Bellatrix.Message.Body.ExecutionPayload.BlockHash,
 			Transactions:  block.Bellatrix.Message.Body.ExecutionPayload.Transactions,
 			BlockNumber:   block.Bellatrix.Message.Body.ExecutionPayload.BlockNumber,
+			ExtraData:     block.Bellatrix.Message.Body.ExecutionPayload.ExtraData,
+			PrevRandao:    block.Bellatrix.Message.Body.ExecutionPayload.PrevRandao,
+			ParentHash:    block.Bellatrix.Message.Body.ExecutionPayload.ParentHash,
+			LogsBloom:     block.Bellatrix.Message.Body.ExecutionPayload.LogsBloom,
 			Withdrawals:   make([]*capella.Withdrawal, 0),
 			PayloadSize:   uint32(0),
 		}, // snappy
@@ -297,10 +306,14 @@ func NewCapellaBlock(block spec.VersionedSignedBeaconBlock) AgnosticBlock {
 			GasLimit:      block.Capella.Message.Body.ExecutionPayload.GasLimit,
 			GasUsed:       block.Capella.Message.Body.ExecutionPayload.GasUsed,
 			Timestamp:     block.Capella.Message.Body.ExecutionPayload.Timestamp,
-			BaseFeePerGas: binary.BigEndian.Uint64(block.Capella.Message.Body.ExecutionPayload.BaseFeePerGas[:]),
+			BaseFeePerGas: binary.LittleEndian.Uint64(block.Capella.Message.Body.ExecutionPayload.BaseFeePerGas[:8]),
 			BlockHash:     block.Capella.Message.Body.ExecutionPayload.BlockHash,
 			Transactions:  block.Capella.Message.Body.ExecutionPayload.Transactions,
 			BlockNumber:   block.Capella.Message.Body.ExecutionPayload.BlockNumber,
+			ExtraData:     block.Capella.Message.Body.ExecutionPayload.ExtraData,
+			PrevRandao:    block.Capella.Message.Body.ExecutionPayload.PrevRandao,
+			ParentHash:    block.Capella.Message.Body.ExecutionPayload.ParentHash,
+			LogsBloom:     block.Capella.Message.Body.ExecutionPayload.LogsBloom,
 			Withdrawals:   block.Capella.Message.Body.ExecutionPayload.Withdrawals,
 			PayloadSize:   uint32(0),
 		}, // snappy
@@ -345,7 +358,13 @@ func NewDenebBlock(block spec.VersionedSignedBeaconBlock) AgnosticBlock {
 			BlockHash:     block.Deneb.Message.Body.ExecutionPayload.BlockHash,
 			Transactions:  block.Deneb.Message.Body.ExecutionPayload.Transactions,
 			BlockNumber:   block.Deneb.Message.Body.ExecutionPayload.BlockNumber,
+			ExtraData:     block.Deneb.Message.Body.ExecutionPayload.ExtraData,
+			PrevRandao:    block.Deneb.Message.Body.ExecutionPayload.PrevRandao,
+			ParentHash:    block.Deneb.Message.Body.ExecutionPayload.ParentHash,
+			LogsBloom:     block.Deneb.Message.Body.ExecutionPayload.LogsBloom,
 			Withdrawals:   block.Deneb.Message.Body.ExecutionPayload.Withdrawals,
+			BlobGasUsed:   block.Deneb.Message.Body.ExecutionPayload.BlobGasUsed,
+			ExcessBlobGas: block.Deneb.Message.Body.ExecutionPayload.ExcessBlobGas,
 			PayloadSize:   uint32(0),
 		}, // snappy
 		BLSToExecutionChanges: block.Deneb.Message.Body.BLSToExecutionChanges,
@@ -391,7 +410,13 @@ func NewElectraBlock(block spec.VersionedSignedBeaconBlock) AgnosticBlock {
 			BlockHash:     block.Electra.Message.Body.ExecutionPayload.BlockHash,
 			Transactions:  block.Electra.Message.Body.ExecutionPayload.Transactions,
 			BlockNumber:   block.Electra.Message.Body.ExecutionPayload.BlockNumber,
+			ExtraData:     block.Electra.Message.Body.ExecutionPayload.ExtraData,
+			PrevRandao:    block.Electra.Message.Body.ExecutionPayload.PrevRandao,
+			ParentHash:    block.Electra.Message.Body.ExecutionPayload.ParentHash,
+			LogsBloom:     block.Electra.Message.Body.ExecutionPayload.LogsBloom,
 			Withdrawals:   block.Electra.Message.Body.ExecutionPayload.Withdrawals,
+			BlobGasUsed:   block.Electra.Message.Body.ExecutionPayload.BlobGasUsed,
+			ExcessBlobGas: block.Electra.Message.Body.ExecutionPayload.ExcessBlobGas,
 			PayloadSize:   uint32(0),
 		}, // snappy
 		BLSToExecutionChanges: block.Electra.Message.Body.BLSToExecutionChanges,
@@ -438,7 +463,13 @@ func NewFuluBlock(block spec.VersionedSignedBeaconBlock) AgnosticBlock {
 			BlockHash:     block.Fulu.Message.Body.ExecutionPayload.BlockHash,
 			Transactions:  block.Fulu.Message.Body.ExecutionPayload.Transactions,
 			BlockNumber:   block.Fulu.Message.Body.ExecutionPayload.BlockNumber,
+			ExtraData:     block.Fulu.Message.Body.ExecutionPayload.ExtraData,
+			PrevRandao:    block.Fulu.Message.Body.ExecutionPayload.PrevRandao,
+			ParentHash:    block.Fulu.Message.Body.ExecutionPayload.ParentHash,
+			LogsBloom:     block.Fulu.Message.Body.ExecutionPayload.LogsBloom,
 			Withdrawals:   block.Fulu.Message.Body.ExecutionPayload.Withdrawals,
+			BlobGasUsed:   block.Fulu.Message.Body.ExecutionPayload.BlobGasUsed,
+			ExcessBlobGas: block.Fulu.Message.Body.ExecutionPayload.ExcessBlobGas,
 			PayloadSize:   uint32(0),
 		}, // snappy
 		BLSToExecutionChanges: block.Fulu.Message.Body.BLSToExecutionChanges,
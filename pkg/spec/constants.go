@@ -118,6 +118,14 @@ const (
 	ConsolidationRequestModel
 	WithdrawalRequestModel
 	DepositRequestModel
+	AttestationModel
+	AuthorizationModel
+	LogModel
+	DecodedLogModel
+	TokenTransferModel
+	TokenApprovalModel
+	InternalCallModel
+	DataColumnSidecarModel
 )
 
 type ValidatorStatus int8
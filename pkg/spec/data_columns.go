@@ -0,0 +1,132 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// NumberOfColumns is the number of columns the extended blobs of a block are split into
+const NumberOfColumns = 128
+
+// AgnosticDataColumnSidecar is a PeerDAS column of the blobs of a block. Only what is needed
+// to follow data availability is kept, the cells and their proofs are skipped.
+type AgnosticDataColumnSidecar struct {
+	Slot                         phase0.Slot
+	BlockRoot                    phase0.Root
+	Index                        uint64
+	Cells                        uint64        // one per blob of the block
+	KZGCommitmentsInclusionProof []phase0.Root // branch of the commitments in the block body
+}
+
+func (d AgnosticDataColumnSidecar) Type() ModelType {
+	return DataColumnSidecarModel
+}
+
+// DataColumnSidecarEventWrapper is a data_column_sidecar event with the time it arrived at
+type DataColumnSidecarEventWrapper struct {
+	Timestamp              time.Time
+	DataColumnSidecarEvent apiv1.DataColumnSidecarEvent
+}
+
+// skippedJSON is a JSON value that is only counted
+type skippedJSON struct{}
+
+func (*skippedJSON) UnmarshalJSON([]byte) error {
+	return nil
+}
+
+type dataColumnSidecarJSON struct {
+	Index             string        `json:"index"`
+	Column            []skippedJSON `json:"column"`
+	SignedBlockHeader struct {
+		Message struct {
+			Slot string `json:"slot"`
+		} `json:"message"`
+	} `json:"signed_block_header"`
+	KZGCommitmentsInclusionProof []phase0.Root `json:"kzg_commitments_inclusion_proof"`
+}
+
+// ParseDataColumnSidecars reads the answer of /eth/v1/debug/beacon/data_column_sidecars/{block_id}
+// for the block with the given root, decoding one sidecar at a time and skipping its cells.
+func ParseDataColumnSidecars(blockRoot phase0.Root, r io.Reader) ([]AgnosticDataColumnSidecar, error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, fmt.Errorf("could not decode data column sidecars: %w", err)
+	}
+
+	columns := make([]AgnosticDataColumnSidecar, 0)
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("could not decode data column sidecars: %w", err)
+		}
+		if key != "data" {
+			// execution_optimistic, finalized...
+			var skipped skippedJSON
+			if err := dec.Decode(&skipped); err != nil {
+				return nil, fmt.Errorf("could not decode data column sidecars: %w", err)
+			}
+			continue
+		}
+		if err := expectDelim(dec, '['); err != nil {
+			return nil, fmt.Errorf("could not decode data column sidecars: %w", err)
+		}
+		for dec.More() {
+			var item dataColumnSidecarJSON
+			if err := dec.Decode(&item); err != nil {
+				return nil, fmt.Errorf("could not decode data column sidecar: %w", err)
+			}
+			column, err := item.toAgnostic(blockRoot)
+			if err != nil {
+				return nil, err
+			}
+			columns = append(columns, column)
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, fmt.Errorf("could not decode data column sidecars: %w", err)
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, fmt.Errorf("could not decode data column sidecars: %w", err)
+	}
+	return columns, nil
+}
+
+func (item dataColumnSidecarJSON) toAgnostic(blockRoot phase0.Root) (AgnosticDataColumnSidecar, error) {
+	index, err := strconv.ParseUint(item.Index, 10, 64)
+	if err != nil {
+		return AgnosticDataColumnSidecar{}, fmt.Errorf("invalid data column index %s: %w", item.Index, err)
+	}
+	if index >= NumberOfColumns {
+		return AgnosticDataColumnSidecar{}, fmt.Errorf("data column index %d out of range", index)
+	}
+	slot, err := strconv.ParseUint(item.SignedBlockHeader.Message.Slot, 10, 64)
+	if err != nil {
+		return AgnosticDataColumnSidecar{}, fmt.Errorf("invalid slot of data column %d: %w", index, err)
+	}
+	return AgnosticDataColumnSidecar{
+		Slot:                         phase0.Slot(slot),
+		BlockRoot:                    blockRoot,
+		Index:                        index,
+		Cells:                        uint64(len(item.Column)),
+		KZGCommitmentsInclusionProof: item.KZGCommitmentsInclusionProof,
+	}, nil
+}
+
+// expectDelim reads the next token, which has to be the given delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %s, got %v", delim, token)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package spec_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDataColumnSidecars(t *testing.T) {
+	proofNode := "0x" + strings.Repeat("ab", 32)
+	body := `{
+	"version": "fulu",
+	"execution_optimistic": false,
+	"finalized": true,
+	"data": [
+		{
+			"index": "3",
+			"column": ["0x00", "0x01"],
+			"kzg_commitments": ["0xc0", "0xc0"],
+			"kzg_proofs": ["0xc0", "0xc0"],
+			"signed_block_header": {"message": {"slot": "1000", "proposer_index": "1"}, "signature": "0x00"},
+			"kzg_commitments_inclusion_proof": ["` + proofNode + `", "` + proofNode + `"]
+		},
+		{
+			"index": "127",
+			"column": [],
+			"signed_block_header": {"message": {"slot": "1000"}},
+			"kzg_commitments_inclusion_proof": []
+		}
+	]
+}`
+	root := phase0.Root{1}
+	columns, err := spec.ParseDataColumnSidecars(root, strings.NewReader(body))
+	require.NoError(t, err)
+	require.Len(t, columns, 2)
+
+	assert.Equal(t, phase0.Slot(1000), columns[0].Slot)
+	assert.Equal(t, root, columns[0].BlockRoot)
+	assert.Equal(t, uint64(3), columns[0].Index)
+	assert.Equal(t, uint64(2), columns[0].Cells)
+	require.Len(t, columns[0].KZGCommitmentsInclusionProof, 2)
+	assert.Equal(t, proofNode, columns[0].KZGCommitmentsInclusionProof[0].String())
+
+	assert.Equal(t, uint64(127), columns[1].Index)
+	assert.Equal(t, uint64(0), columns[1].Cells)
+
+	_, err = spec.ParseDataColumnSidecars(root, strings.NewReader(`{"data": [{"index": "128", "signed_block_header": {"message": {"slot": "1"}}}]}`))
+	assert.Error(t, err)
+	_, err = spec.ParseDataColumnSidecars(root, strings.NewReader(`{"data": [{"index": "1", "signed_block_header": {"message": {"slot": "1"}}, "kzg_commitments_inclusion_proof": ["0x12"]}]}`))
+	assert.Error(t, err)
+	_, err = spec.ParseDataColumnSidecars(root, strings.NewReader(`{"data": {"index": "1"}}`))
+	assert.Error(t, err)
+}
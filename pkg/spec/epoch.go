@@ -37,6 +37,7 @@ type Epoch struct {
 	WithdrawalRequestsNum         int
 	ConsolidationsProcessedNum    uint64
 	ConsolidationsProcessedAmount phase0.Gwei
+	UnavailableMetrics            []string // metrics not computed for this epoch (non-archival states)
 }
 
 func (f Epoch) Type() ModelType {
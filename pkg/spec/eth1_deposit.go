@@ -3,6 +3,7 @@ package spec
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/core/types"
 )
@@ -15,7 +16,7 @@ type ETH1Deposit struct {
 	Sender                string
 	Recipient             string
 	GasUsed               uint64
-	GasPrice              uint64
+	GasPrice              *big.Int
 	DepositIndex          uint64
 	ValidatorPubkey       string
 	WithdrawalCredentials string
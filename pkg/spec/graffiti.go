@@ -0,0 +1,10 @@
+package spec
+
+// GraffitiClients are the consensus and execution clients a proposer advertised in the
+// graffiti of a block. Empty when not advertised.
+type GraffitiClients struct {
+	CLClient  string
+	CLVersion string // semantic version or commit prefix, as advertised
+	ELClient  string
+	ELVersion string
+}
@@ -0,0 +1,108 @@
+package spec
+
+import (
+	"math/big"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// A log emitted by a transaction, as found in its receipt
+type AgnosticLog struct {
+	Slot        phase0.Slot    // slot of the block including the transaction
+	BlockRoot   phase0.Root    // root of the block including the transaction
+	BlockNumber uint64         // execution block number
+	TxHash      phase0.Hash32  // hash of the transaction emitting the log
+	TxIdx       uint64         // index of the transaction in the block
+	LogIndex    uint64         // index of the log in the block
+	Address     common.Address // contract emitting the log
+	Topics      []common.Hash
+	Data        []byte
+}
+
+func (f AgnosticLog) Type() ModelType {
+	return LogModel
+}
+
+// ParseLogsFromTransactions flattens the receipt logs of the given transactions.
+// Transactions without receipt are skipped.
+func ParseLogsFromTransactions(txs []AgnosticTransaction) []AgnosticLog {
+	logs := make([]AgnosticLog, 0)
+	for _, tx := range txs {
+		if tx.Receipt == nil {
+			continue
+		}
+		for _, item := range tx.Receipt.Logs {
+			logs = append(logs, AgnosticLog{
+				Slot:        tx.Slot,
+				BlockRoot:   tx.BlockRoot,
+				BlockNumber: tx.BlockNumber,
+				TxHash:      tx.Hash,
+				TxIdx:       tx.TxIdx,
+				LogIndex:    uint64(item.Index),
+				Address:     item.Address,
+				Topics:      item.Topics,
+				Data:        item.Data,
+			})
+		}
+	}
+	return logs
+}
+
+// A log decoded with one of the configured ABIs
+type DecodedLog struct {
+	Slot      phase0.Slot
+	BlockRoot phase0.Root
+	TxHash    phase0.Hash32
+	LogIndex  uint64
+	Address   common.Address
+	ABI       string   // name of the ABI the event was found in
+	Event     string   // name of the event
+	Signature string   // canonical signature, e.g. Transfer(address,address,uint256)
+	ArgNames  []string // arguments in the order of the signature
+	ArgValues []string // addresses and hashes as hex, integers in base 10
+}
+
+func (f DecodedLog) Type() ModelType {
+	return DecodedLogModel
+}
+
+// ERC-20 and ERC-721 token standards
+const (
+	ERC20Standard  = "erc20"
+	ERC721Standard = "erc721"
+)
+
+// A Transfer event of an ERC-20 or ERC-721 token
+type TokenTransfer struct {
+	Slot      phase0.Slot
+	BlockRoot phase0.Root
+	TxHash    phase0.Hash32
+	LogIndex  uint64
+	Token     common.Address
+	Standard  string
+	From      common.Address
+	To        common.Address
+	Value     *big.Int // amount for ERC-20, token ID for ERC-721
+}
+
+func (f TokenTransfer) Type() ModelType {
+	return TokenTransferModel
+}
+
+// An Approval event of an ERC-20 or ERC-721 token
+type TokenApproval struct {
+	Slot      phase0.Slot
+	BlockRoot phase0.Root
+	TxHash    phase0.Hash32
+	LogIndex  uint64
+	Token     common.Address
+	Standard  string
+	Owner     common.Address
+	Spender   common.Address
+	Value     *big.Int // allowance for ERC-20, token ID for ERC-721
+}
+
+func (f TokenApproval) Type() ModelType {
+	return TokenApprovalModel
+}
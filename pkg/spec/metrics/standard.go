@@ -135,5 +135,6 @@ func (s StateMetricsBase) ExportToEpoch() local_spec.Epoch {
 		WithdrawalRequestsNum:         int(len(s.CurrentState.WithdrawalRequests)),
 		ConsolidationsProcessedNum:    uint64(len(s.CurrentState.ConsolidationsProcessed)),
 		ConsolidationsProcessedAmount: s.CurrentState.ConsolidationsProcessedAmount,
+		UnavailableMetrics:            s.NextState.UnavailableMetrics,
 	}
 }
@@ -46,7 +46,10 @@ func (p *ElectraMetrics) PreProcessBundle() {
 
 	if !p.baseMetrics.CurrentState.EmptyStateRoot() {
 		p.ProcessAttestations()
-		p.processPendingDeposits()
+		if !p.baseMetrics.NextState.NonArchival {
+			// the deposit churn and eth1 indices are not exposed by the per-resource endpoints
+			p.processPendingDeposits()
+		}
 		// FIX: Clear state maps before processing (state objects are reused between iterations)
 		p.baseMetrics.CurrentState.ConsolidatedAmounts = make(map[phase0.ValidatorIndex]phase0.Gwei)
 		p.baseMetrics.CurrentState.ConsolidatedOutAmounts = make(map[phase0.ValidatorIndex]phase0.Gwei)
@@ -54,7 +57,11 @@ func (p *ElectraMetrics) PreProcessBundle() {
 		// Process balance-affecting operations that happen during epoch transition
 		p.processExcessActiveBalanceRestructuring(p.baseMetrics.CurrentState, p.baseMetrics.NextState)
 		p.processConsolidationsForRewardCalculation(p.baseMetrics.CurrentState, p.baseMetrics.NextState)
-		p.processDepositsForRewardCalculation(p.baseMetrics.CurrentState, p.baseMetrics.NextState)
+		if p.baseMetrics.CurrentState.NonArchival || p.baseMetrics.NextState.NonArchival {
+			p.processDepositsFromQueues(p.baseMetrics.CurrentState, p.baseMetrics.NextState)
+		} else {
+			p.processDepositsForRewardCalculation(p.baseMetrics.CurrentState, p.baseMetrics.NextState)
+		}
 		p.processPendingConsolidations(p.baseMetrics.NextState)
 		if !p.baseMetrics.PrevState.EmptyStateRoot() {
 			// block rewards
@@ -780,6 +787,48 @@ func (p ElectraMetrics) processDepositsForRewardCalculation(currentState *spec.A
 	}
 }
 
+// processDepositsFromQueues is the non-archival counterpart of processDepositsForRewardCalculation.
+// Instead of replaying process_pending_deposits, which needs the deposit balance to consume and the
+// eth1 deposit indices, it compares the pending deposits queue of currentState and nextState to find
+// the deposits applied at the CurrentState→NextState boundary.
+// As they are known one epoch later than in archival mode, they are stored in currentState.
+func (p ElectraMetrics) processDepositsFromQueues(currentState *spec.AgnosticState, nextState *spec.AgnosticState) {
+	if currentState == nil || nextState == nil {
+		return
+	}
+
+	validatorPubkeys := make(map[[48]byte]phase0.ValidatorIndex)
+	for i, v := range currentState.Validators {
+		validatorPubkeys[v.PublicKey] = phase0.ValidatorIndex(i)
+	}
+
+	processedDeposits := make([]spec.Deposit, 0)
+	depositsNum := uint64(0)
+	totalDepositsAmount := phase0.Gwei(0)
+	for i, deposit := range spec.ProcessedPendingDeposits(currentState.PendingDeposits, nextState.PendingDeposits) {
+		if validatorIdx, exists := validatorPubkeys[deposit.Pubkey]; exists {
+			currentState.DepositedAmounts[validatorIdx] += deposit.Amount
+		}
+		processedDeposits = append(processedDeposits, spec.Deposit{
+			Slot:                  deposit.Slot,
+			EpochProcessed:        currentState.Epoch,
+			PublicKey:             deposit.Pubkey,
+			WithdrawalCredentials: deposit.WithdrawalCredentials,
+			Amount:                deposit.Amount,
+			Signature:             deposit.Signature,
+			Index:                 uint8(i),
+		})
+		depositsNum += 1
+		totalDepositsAmount += deposit.Amount
+	}
+	// the state object may be reused between bundles, do not count twice
+	if currentState.DepositsProcessed == nil {
+		currentState.DepositsProcessed = processedDeposits
+		currentState.DepositsNum += depositsNum
+		currentState.TotalDepositsAmount += totalDepositsAmount
+	}
+}
+
 // Equal to ProcessSlashings from phase0, but modified to use ElectraAttesterSlashings
 func (p *ElectraMetrics) ProcessSlashings() {
 	state := p.GetMetricsBase().NextState
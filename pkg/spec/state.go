@@ -66,6 +66,9 @@ type AgnosticState struct {
 	DepositBalanceToConsume       phase0.Gwei                           // balance to consume for deposits, used for Electra Fork
 	Eth1DepositIndex              uint64                                // index of the next deposit request to be processed, used for Electra Fork
 	DepositRequestsStartIndex     uint64                                // index of the next deposit request to be processed, used for Electra Fork
+	// Non-archival
+	NonArchival        bool     // state built from per-resource endpoints (see NewStateFromResources)
+	UnavailableMetrics []string // metrics that could not be computed for this state
 }
 
 func GetCustomState(bstate spec.VersionedBeaconState, duties EpochDuties) (AgnosticState, error) {
@@ -0,0 +1,214 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/altair"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// Metrics (named after their column) that cannot be computed from a state
+// built out of per-resource endpoints. They are listed in UnavailableMetrics.
+const (
+	UnavailableMissingSource             = "f_missing_source"
+	UnavailableMissingTarget             = "f_missing_target"
+	UnavailableMissingHead               = "f_missing_head"
+	UnavailableAttEffectiveBalance       = "f_att_effective_balance_eth"
+	UnavailableSourceAttEffectiveBalance = "f_source_att_effective_balance_eth"
+	UnavailableTargetAttEffectiveBalance = "f_target_att_effective_balance_eth"
+	UnavailableHeadAttEffectiveBalance   = "f_head_att_effective_balance_eth"
+
+	// MIN_EPOCHS_TO_INACTIVITY_PENALTY
+	MinEpochsToInactivityPenalty = 4
+)
+
+// StateResources gathers the per-resource beacon API responses for a given state,
+// used when the beacon node does not serve full states (non-archival nodes).
+type StateResources struct {
+	Version                   spec.DataVersion
+	Slot                      phase0.Slot
+	GenesisTimestamp          uint64
+	Validators                map[phase0.ValidatorIndex]*apiv1.Validator
+	BlockRoots                map[phase0.Slot]phase0.Root // only the slots needed to process the epoch
+	LatestBlockHeader         *phase0.BeaconBlockHeader
+	SyncCommittee             []phase0.ValidatorIndex
+	Finality                  *apiv1.Finality
+	AttestationRewards        []apiv1.ValidatorAttestationRewards // rewards of the previous epoch, used to rebuild participation
+	PendingDeposits           []*electra.PendingDeposit
+	PendingPartialWithdrawals []*electra.PendingPartialWithdrawal
+	PendingConsolidations     []*electra.PendingConsolidation
+}
+
+// NewStateFromResources builds an AgnosticState out of the per-resource endpoints.
+// Previous epoch participation is not exposed by the standard API, so it is rebuilt
+// from the attestation rewards; whatever cannot be rebuilt is listed in UnavailableMetrics.
+func NewStateFromResources(res StateResources, duties EpochDuties) (AgnosticState, error) {
+	if res.Version == spec.DataVersionPhase0 {
+		return AgnosticState{}, fmt.Errorf("phase0 states cannot be built from per-resource endpoints")
+	}
+
+	validators := make([]*phase0.Validator, len(res.Validators))
+	balances := make([]phase0.Gwei, len(res.Validators))
+	for idx, item := range res.Validators {
+		if int(idx) >= len(validators) || item.Validator == nil {
+			return AgnosticState{}, fmt.Errorf("validator list at slot %d is not contiguous (index %d)", res.Slot, idx)
+		}
+		validators[idx] = item.Validator
+		balances[idx] = item.Balance
+	}
+
+	blockRoots := make([]phase0.Root, SlotsPerHistoricalRoot)
+	for slot, root := range res.BlockRoots {
+		blockRoots[slot%SlotsPerHistoricalRoot] = root
+	}
+
+	syncCommittee := altair.SyncCommittee{
+		Pubkeys: make([]phase0.BLSPubKey, 0, len(res.SyncCommittee)),
+	}
+	for _, valIdx := range res.SyncCommittee {
+		if int(valIdx) >= len(validators) {
+			return AgnosticState{}, fmt.Errorf("sync committee member %d not in the validator list", valIdx)
+		}
+		syncCommittee.Pubkeys = append(syncCommittee.Pubkeys, validators[valIdx].PublicKey)
+	}
+
+	state := AgnosticState{
+		Version:                   res.Version,
+		NonArchival:               true,
+		Balances:                  balances,
+		Validators:                validators,
+		EpochStructs:              duties,
+		Epoch:                     phase0.Epoch(res.Slot / SlotsPerEpoch),
+		Slot:                      res.Slot,
+		BlockRoots:                blockRoots,
+		SyncCommittee:             syncCommittee,
+		GenesisTimestamp:          res.GenesisTimestamp,
+		LatestBlockHeader:         res.LatestBlockHeader,
+		PendingConsolidations:     res.PendingConsolidations,
+		PendingPartialWithdrawals: res.PendingPartialWithdrawals,
+		PendingDeposits:           res.PendingDeposits,
+		UnavailableMetrics:        make([]string, 0),
+	}
+	if res.Finality != nil {
+		state.CurrentJustifiedCheckpoint = *res.Finality.Justified
+		state.CurrentFinalizedCheckpoint = *res.Finality.Finalized
+	}
+
+	if err := state.Setup(); err != nil {
+		return AgnosticState{}, err
+	}
+
+	ProcessAttestationRewards(&state, res.AttestationRewards)
+
+	return state, nil
+}
+
+// ProcessAttestationRewards rebuilds the previous epoch participation flags from the attestation rewards.
+// A timely source/target vote is rewarded and a missed one penalized, except during an inactivity
+// leak, where correct votes get zero. A timely head vote is rewarded, but during a leak both outcomes
+// get zero, so head participation is marked as unavailable.
+// Nil rewards (the node could not compute them) mark all participation metrics as unavailable.
+func ProcessAttestationRewards(customState *AgnosticState, rewards []apiv1.ValidatorAttestationRewards) {
+	if rewards == nil {
+		customState.UnavailableMetrics = append(customState.UnavailableMetrics,
+			UnavailableMissingSource,
+			UnavailableMissingTarget,
+			UnavailableMissingHead,
+			UnavailableAttEffectiveBalance,
+			UnavailableSourceAttEffectiveBalance,
+			UnavailableTargetAttEffectiveBalance,
+			UnavailableHeadAttEffectiveBalance)
+		return
+	}
+	prevEpoch := phase0.Epoch(0)
+	if customState.Epoch > 0 {
+		prevEpoch = customState.Epoch - 1
+	}
+	inLeak := prevEpoch >= customState.CurrentFinalizedCheckpoint.Epoch+MinEpochsToInactivityPenalty
+	if inLeak {
+		customState.UnavailableMetrics = append(customState.UnavailableMetrics,
+			UnavailableMissingHead,
+			UnavailableHeadAttEffectiveBalance)
+	}
+
+	for _, reward := range rewards {
+		valIdx := reward.ValidatorIndex
+		if int(valIdx) >= len(customState.Validators) {
+			continue
+		}
+		validator := customState.Validators[valIdx]
+		eligible := IsActive(*validator, prevEpoch) && !validator.Slashed
+
+		correct := []bool{
+			reward.Source > 0 || (inLeak && eligible && reward.Source == 0),
+			reward.Target > 0 || (inLeak && eligible && reward.Target == 0),
+			!inLeak && reward.Head > 0,
+		}
+		for flagIndex, isCorrect := range correct {
+			if isCorrect {
+				customState.PrevEpochCorrectFlags[flagIndex][valIdx] = true
+				customState.AttestingBalance[flagIndex] += validator.EffectiveBalance
+			}
+		}
+	}
+}
+
+// ProcessedPendingDeposits returns the deposits of prevQueue that were applied in the epoch
+// transition that produced nextQueue. The transition drops the processed and postponed
+// deposits from the head of the queue and appends the postponed ones (and any new deposits)
+// at the end, so the remaining part of prevQueue must be a prefix of nextQueue.
+func ProcessedPendingDeposits(prevQueue []*electra.PendingDeposit, nextQueue []*electra.PendingDeposit) []*electra.PendingDeposit {
+	consumed := -1
+	for k := 0; k <= len(prevQueue) && uint64(k) <= MaxPendingDepositsPerEpoch; k++ {
+		remaining := prevQueue[k:]
+		if len(remaining) > len(nextQueue) {
+			continue
+		}
+		match := true
+		for i, deposit := range remaining {
+			if !samePendingDeposit(deposit, nextQueue[i]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			consumed = k
+			break
+		}
+	}
+	if consumed <= 0 {
+		return make([]*electra.PendingDeposit, 0)
+	}
+
+	// postponed deposits are re-appended right after the remaining ones
+	appended := nextQueue[len(prevQueue)-consumed:]
+	used := make([]bool, len(appended))
+
+	processed := make([]*electra.PendingDeposit, 0, consumed)
+	for _, deposit := range prevQueue[:consumed] {
+		postponed := false
+		for i, item := range appended {
+			if !used[i] && samePendingDeposit(deposit, item) {
+				used[i] = true
+				postponed = true
+				break
+			}
+		}
+		if !postponed {
+			processed = append(processed, deposit)
+		}
+	}
+	return processed
+}
+
+func samePendingDeposit(a *electra.PendingDeposit, b *electra.PendingDeposit) bool {
+	return a.Pubkey == b.Pubkey &&
+		a.Amount == b.Amount &&
+		a.Slot == b.Slot &&
+		a.Signature == b.Signature &&
+		bytes.Equal(a.WithdrawalCredentials, b.WithdrawalCredentials)
+}
@@ -0,0 +1,165 @@
+package spec_test
+
+import (
+	"testing"
+
+	apiv1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	local_spec "github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/require"
+)
+
+func pendingDeposit(id byte, amount phase0.Gwei) *electra.PendingDeposit {
+	return &electra.PendingDeposit{
+		Pubkey:                phase0.BLSPubKey{id},
+		WithdrawalCredentials: []byte{0x01, id},
+		Amount:                amount,
+		Slot:                  phase0.Slot(id),
+	}
+}
+
+func TestProcessedPendingDeposits(t *testing.T) {
+	a := pendingDeposit(1, 32)
+	b := pendingDeposit(2, 1)
+	c := pendingDeposit(3, 2)
+	d := pendingDeposit(4, 3)
+	a2 := pendingDeposit(1, 32) // same content as a, different pointer
+
+	tests := []struct {
+		name      string
+		prev      []*electra.PendingDeposit
+		next      []*electra.PendingDeposit
+		processed []*electra.PendingDeposit
+	}{
+		{
+			name:      "Empty queues",
+			prev:      nil,
+			next:      nil,
+			processed: []*electra.PendingDeposit{},
+		},
+		{
+			name:      "Nothing processed, new deposits appended",
+			prev:      []*electra.PendingDeposit{a, b},
+			next:      []*electra.PendingDeposit{a, b, c},
+			processed: []*electra.PendingDeposit{},
+		},
+		{
+			name:      "Head of the queue processed",
+			prev:      []*electra.PendingDeposit{a, b, c},
+			next:      []*electra.PendingDeposit{c, d},
+			processed: []*electra.PendingDeposit{a, b},
+		},
+		{
+			name:      "Whole queue processed",
+			prev:      []*electra.PendingDeposit{a, b},
+			next:      []*electra.PendingDeposit{},
+			processed: []*electra.PendingDeposit{a, b},
+		},
+		{
+			name:      "Exiting validator deposit postponed",
+			prev:      []*electra.PendingDeposit{a, b, c},
+			next:      []*electra.PendingDeposit{c, a2},
+			processed: []*electra.PendingDeposit{b},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.processed, local_spec.ProcessedPendingDeposits(test.prev, test.next))
+		})
+	}
+}
+
+func TestProcessAttestationRewards(t *testing.T) {
+	validators := make(map[phase0.ValidatorIndex]*apiv1.Validator)
+	for i := 0; i < 3; i++ {
+		validators[phase0.ValidatorIndex(i)] = &apiv1.Validator{
+			Index:   phase0.ValidatorIndex(i),
+			Balance: 32_000_000_000,
+			Validator: &phase0.Validator{
+				PublicKey:        phase0.BLSPubKey{byte(i)},
+				EffectiveBalance: 32_000_000_000,
+				ExitEpoch:        phase0.Epoch(local_spec.FarFutureEpoch),
+			},
+		}
+	}
+	rewards := []apiv1.ValidatorAttestationRewards{
+		{ValidatorIndex: 0, Source: 10, Target: 20, Head: 5},   // all correct
+		{ValidatorIndex: 1, Source: 10, Target: -20, Head: 0},  // wrong target and head
+		{ValidatorIndex: 2, Source: -10, Target: -20, Head: 0}, // missed
+	}
+	leakRewards := []apiv1.ValidatorAttestationRewards{
+		{ValidatorIndex: 0, Source: 0, Target: 0, Head: 0},
+		{ValidatorIndex: 1, Source: 0, Target: -20, Head: 0},
+		{ValidatorIndex: 2, Source: -10, Target: -20, Head: 0},
+	}
+
+	tests := []struct {
+		name        string
+		rewards     []apiv1.ValidatorAttestationRewards
+		finalized   phase0.Epoch
+		flags       [][]bool
+		unavailable []string
+	}{
+		{
+			name:      "Finalizing",
+			rewards:   rewards,
+			finalized: 9,
+			flags: [][]bool{
+				{true, true, false},
+				{true, false, false},
+				{true, false, false},
+			},
+			unavailable: []string{},
+		},
+		{
+			name:      "Inactivity leak",
+			rewards:   leakRewards,
+			finalized: 2,
+			flags: [][]bool{
+				{true, true, false},
+				{true, false, false},
+				{false, false, false},
+			},
+			unavailable: []string{local_spec.UnavailableMissingHead, local_spec.UnavailableHeadAttEffectiveBalance},
+		},
+		{
+			name:      "Rewards not available",
+			rewards:   nil,
+			finalized: 9,
+			flags: [][]bool{
+				{false, false, false},
+				{false, false, false},
+				{false, false, false},
+			},
+			unavailable: []string{
+				local_spec.UnavailableMissingSource,
+				local_spec.UnavailableMissingTarget,
+				local_spec.UnavailableMissingHead,
+				local_spec.UnavailableAttEffectiveBalance,
+				local_spec.UnavailableSourceAttEffectiveBalance,
+				local_spec.UnavailableTargetAttEffectiveBalance,
+				local_spec.UnavailableHeadAttEffectiveBalance,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state, err := local_spec.NewStateFromResources(local_spec.StateResources{
+				Version:            spec.DataVersionElectra,
+				Slot:               10*local_spec.SlotsPerEpoch + 31,
+				Validators:         validators,
+				LatestBlockHeader:  &phase0.BeaconBlockHeader{Slot: 10*local_spec.SlotsPerEpoch + 31},
+				Finality:           &apiv1.Finality{Justified: &phase0.Checkpoint{Epoch: test.finalized}, Finalized: &phase0.Checkpoint{Epoch: test.finalized}},
+				AttestationRewards: test.rewards,
+			}, local_spec.EpochDuties{})
+			require.NoError(t, err)
+			require.True(t, state.NonArchival)
+			require.Equal(t, test.flags, state.PrevEpochCorrectFlags)
+			require.Equal(t, test.unavailable, state.UnavailableMetrics)
+		})
+	}
+}
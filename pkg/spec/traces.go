@@ -0,0 +1,110 @@
+package spec
+
+import (
+	"math/big"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// CallFrame is a call of the callTracer of debug_traceBlockByHash, with its subcalls
+type CallFrame struct {
+	Type    string          `json:"type"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to,omitempty"`
+	Value   *hexutil.Big    `json:"value,omitempty"`
+	Gas     hexutil.Uint64  `json:"gas"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Error   string          `json:"error,omitempty"`
+	Calls   []CallFrame     `json:"calls,omitempty"`
+}
+
+// TxTrace is the trace of a transaction of a block
+type TxTrace struct {
+	TxHash common.Hash `json:"txHash"`
+	Result *CallFrame  `json:"result"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// An internal call of a transaction, moving ETH or failing
+type InternalCall struct {
+	Slot         phase0.Slot
+	BlockRoot    phase0.Root
+	TxHash       phase0.Hash32
+	TxIdx        uint64
+	TraceAddress []uint64 // position of the call in the call tree, [0 1] being the second subcall of the first subcall
+	CallType     string   // CALL, DELEGATECALL, CREATE, SELFDESTRUCT...
+	From         common.Address
+	To           common.Address
+	Value        *big.Int // Wei
+	Gas          uint64
+	GasUsed      uint64
+	Error        string // error of the call itself
+	Reverted     bool   // whether the call or any of its callers failed, so no value was moved
+}
+
+func (f InternalCall) Type() ModelType {
+	return InternalCallModel
+}
+
+// ParseInternalCalls flattens the call trees of the transactions of a block. The top
+// level calls are left out as they are the transactions themselves, only subcalls
+// moving value or failing are returned.
+func ParseInternalCalls(block AgnosticBlock, traces []TxTrace) []InternalCall {
+	calls := make([]InternalCall, 0)
+	for txIdx, trace := range traces {
+		if trace.Result == nil {
+			continue
+		}
+		txHash := phase0.Hash32(trace.TxHash)
+		for i, call := range trace.Result.Calls {
+			calls = flattenCall(calls, block, txHash, uint64(txIdx), call, []uint64{uint64(i)}, trace.Result.Error != "")
+		}
+	}
+	return calls
+}
+
+func flattenCall(
+	calls []InternalCall,
+	block AgnosticBlock,
+	txHash phase0.Hash32,
+	txIdx uint64,
+	frame CallFrame,
+	traceAddress []uint64,
+	parentReverted bool) []InternalCall {
+
+	reverted := parentReverted || frame.Error != ""
+	value := new(big.Int)
+	if frame.Value != nil {
+		value = frame.Value.ToInt()
+	}
+	if value.Sign() > 0 || frame.Error != "" {
+		to := common.Address{}
+		if frame.To != nil {
+			to = *frame.To
+		}
+		calls = append(calls, InternalCall{
+			Slot:         block.Slot,
+			BlockRoot:    block.Root,
+			TxHash:       txHash,
+			TxIdx:        txIdx,
+			TraceAddress: traceAddress,
+			CallType:     frame.Type,
+			From:         frame.From,
+			To:           to,
+			Value:        value,
+			Gas:          uint64(frame.Gas),
+			GasUsed:      uint64(frame.GasUsed),
+			Error:        frame.Error,
+			Reverted:     reverted,
+		})
+	}
+	for i, subcall := range frame.Calls {
+		address := make([]uint64, len(traceAddress)+1)
+		copy(address, traceAddress)
+		address[len(traceAddress)] = uint64(i)
+		calls = flattenCall(calls, block, txHash, txIdx, subcall, address, reverted)
+	}
+	return calls
+}
@@ -0,0 +1,61 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callTracer output of a transaction calling a contract that pays two addresses,
+// the second payment being reverted along with its caller
+const callTrace = `[{
+	"txHash": "0x0100000000000000000000000000000000000000000000000000000000000000",
+	"result": {
+		"type": "CALL", "from": "0x00000000000000000000000000000000000000aa", "to": "0x00000000000000000000000000000000000000bb",
+		"value": "0x0", "gas": "0x10000", "gasUsed": "0x5000",
+		"calls": [
+			{"type": "CALL", "from": "0x00000000000000000000000000000000000000bb", "to": "0x00000000000000000000000000000000000000cc", "value": "0xde0b6b3a7640000", "gas": "0x900", "gasUsed": "0x0"},
+			{"type": "STATICCALL", "from": "0x00000000000000000000000000000000000000bb", "to": "0x00000000000000000000000000000000000000dd", "gas": "0x900", "gasUsed": "0x100"},
+			{"type": "DELEGATECALL", "from": "0x00000000000000000000000000000000000000bb", "to": "0x00000000000000000000000000000000000000ee", "gas": "0x900", "gasUsed": "0x900", "error": "execution reverted",
+				"calls": [{"type": "CALL", "from": "0x00000000000000000000000000000000000000bb", "to": "0x00000000000000000000000000000000000000ff", "value": "0x1", "gas": "0x100", "gasUsed": "0x0"}]}
+		]
+	}
+}, {
+	"txHash": "0x0200000000000000000000000000000000000000000000000000000000000000",
+	"error": "tracing failed"
+}]`
+
+func TestParseInternalCalls(t *testing.T) {
+	var traces []spec.TxTrace
+	require.NoError(t, json.Unmarshal([]byte(callTrace), &traces))
+
+	block := spec.AgnosticBlock{Slot: 50, Root: phase0.Root{5}}
+	calls := spec.ParseInternalCalls(block, traces)
+	require.Len(t, calls, 3)
+
+	payment := calls[0]
+	assert.Equal(t, []uint64{0}, payment.TraceAddress)
+	assert.Equal(t, common.HexToAddress("0xcc"), payment.To)
+	assert.Equal(t, big.NewInt(1_000_000_000_000_000_000), payment.Value)
+	assert.False(t, payment.Reverted)
+	assert.Equal(t, phase0.Hash32{1}, payment.TxHash)
+	assert.Equal(t, block.Root, payment.BlockRoot)
+
+	failed := calls[1]
+	assert.Equal(t, []uint64{2}, failed.TraceAddress)
+	assert.Equal(t, "DELEGATECALL", failed.CallType)
+	assert.Equal(t, "execution reverted", failed.Error)
+	assert.True(t, failed.Reverted)
+
+	// moves value in the trace but its caller reverted
+	nested := calls[2]
+	assert.Equal(t, []uint64{2, 0}, nested.TraceAddress)
+	assert.Empty(t, nested.Error)
+	assert.True(t, nested.Reverted)
+}
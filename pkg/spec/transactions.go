@@ -11,19 +11,20 @@ import (
 )
 
 var (
-	blobTxType uint8 = 3
+	blobTxType    uint8 = 3
+	setCodeTxType uint8 = 4
 )
 
 // A wrapper for blockchain transaction with basic information retrieved from the Ethereum blockchain
 type AgnosticTransaction struct {
 	TxIdx           uint64          // transaction index in the block
 	TxType          uint8           // type of transaction: LegacyTxType, AccessListTxType, or DynamicFeeTxType
-	ChainId         uint8           // a unique identifier for the ethereum network
+	ChainId         uint64          // a unique identifier for the ethereum network
 	Data            string          // the input data of the transaction
 	Gas             uint64          // the gas limit of the transaction
-	GasPrice        uint64          // the gas price of the transaction
-	GasTipCap       uint64          // the tip cap per gas of the transaction
-	GasFeeCap       uint64          // the fee cap per gas of the transaction
+	GasPrice        *big.Int        // the gas price of the transaction, the effective one when the receipt is known
+	GasTipCap       *big.Int        // the tip cap per gas of the transaction
+	GasFeeCap       *big.Int        // the fee cap per gas of the transaction
 	Value           *big.Int        // the ether amount of the transaction in wei.
 	Nonce           uint64          // the sender account nonce of the transaction
 	To              *common.Address // transaction recipient's address
@@ -34,13 +35,20 @@ type AgnosticTransaction struct {
 	BlockNumber     uint64          // the number of the block where this transaction was added
 	Timestamp       uint64          // timestamp of the block to which this transaction belongs
 	ContractAddress common.Address  // address of the smart contract associated with this transaction
+	BlockRoot       phase0.Root     // root of the beacon block that included the transaction
 
 	// Blobs
 	BlobHashes    []common.Hash
-	BlobGasUsed   uint64 // amount of gas used
-	BlobGasPrice  uint64 // price per unit of gas used => Wei
-	BlobGasLimit  uint64 // maximum gas allowed
-	BlobGasFeeCap uint64
+	BlobGasUsed   uint64   // amount of gas used
+	BlobGasPrice  *big.Int // price per unit of gas used => Wei
+	BlobGasLimit  uint64   // maximum gas allowed
+	BlobGasFeeCap *big.Int
+
+	// Fees paid at the base fee of the block, set when parsed from a block
+	Fees TransactionFees
+
+	// Set code (EIP-7702)
+	Authorizations []AgnosticAuthorization
 
 	// Receipt
 	Receipt *types.Receipt
@@ -75,6 +83,11 @@ func ParseTransactionsFromBlock(block AgnosticBlock, receipts []*types.Receipt)
 				if err != nil {
 					return nil, err
 				}
+				agnosticTx.BlockRoot = block.Root
+				agnosticTx.Fees = agnosticTx.ComputeFees(block.ExecutionPayload.BaseFeePerGas)
+				for i := range agnosticTx.Authorizations {
+					agnosticTx.Authorizations[i].BlockRoot = block.Root
+				}
 				agnosticTxs = append(agnosticTxs, agnosticTx)
 				break
 			}
@@ -99,35 +112,44 @@ func ParseTransactionFromReceipt(
 	}
 
 	gasUsed := parsedTx.Gas()
-	gasPrice := parsedTx.GasPrice().Uint64()
+	gasPrice := copyBig(parsedTx.GasPrice())
 	contractAddress := common.Address{}
 	blobGasUsed := uint64(0)
-	blobGasPrice := uint64(0)
+	blobGasPrice := new(big.Int)
 	blobGasLimit := uint64(0)
-	blobGasFeeCap := uint64(0)
+	blobGasFeeCap := new(big.Int)
 
 	if receipt != nil {
 		gasUsed = receipt.GasUsed
-		gasPrice = receipt.EffectiveGasPrice.Uint64()
+		if receipt.EffectiveGasPrice != nil {
+			gasPrice = copyBig(receipt.EffectiveGasPrice)
+		}
 		contractAddress = receipt.ContractAddress
 	}
 
 	if parsedTx.Type() == blobTxType {
-		blobGasUsed = receipt.BlobGasUsed
-		blobGasPrice = receipt.BlobGasPrice.Uint64()
+		if receipt != nil {
+			blobGasUsed = receipt.BlobGasUsed
+			blobGasPrice = copyBig(receipt.BlobGasPrice)
+		}
 		blobGasLimit = parsedTx.BlobGas()
-		blobGasFeeCap = parsedTx.BlobGasFeeCap().Uint64()
+		blobGasFeeCap = copyBig(parsedTx.BlobGasFeeCap())
+	}
+
+	var authorizations []AgnosticAuthorization
+	if parsedTx.Type() == setCodeTxType {
+		authorizations = ParseAuthorizations(parsedTx, slot, txIdx)
 	}
 
 	return AgnosticTransaction{
 		TxIdx:           txIdx,
 		TxType:          parsedTx.Type(),
-		ChainId:         uint8(parsedTx.ChainId().Uint64()),
+		ChainId:         parsedTx.ChainId().Uint64(),
 		Data:            hex.EncodeToString(parsedTx.Data()),
 		Gas:             gasUsed,
 		GasPrice:        gasPrice,
-		GasTipCap:       parsedTx.GasTipCap().Uint64(),
-		GasFeeCap:       parsedTx.GasFeeCap().Uint64(),
+		GasTipCap:       copyBig(parsedTx.GasTipCap()),
+		GasFeeCap:       copyBig(parsedTx.GasFeeCap()),
 		Value:           new(big.Int).Set(parsedTx.Value()),
 		Nonce:           parsedTx.Nonce(),
 		To:              parsedTx.To(),
@@ -143,7 +165,43 @@ func ParseTransactionFromReceipt(
 		BlobGasLimit:    blobGasLimit,
 		BlobGasFeeCap:   blobGasFeeCap,
 		BlobHashes:      parsedTx.BlobHashes(),
+		Authorizations:  authorizations,
 		Receipt:         receipt,
 	}, nil
 
 }
+
+// TransactionFees splits what a transaction paid for gas, in Wei
+type TransactionFees struct {
+	Tip          *big.Int // (gas price - base fee) * gas used, paid to the fee recipient
+	BurntFee     *big.Int // base fee * gas used
+	BlobBurntFee *big.Int // blob gas price * blob gas used
+}
+
+// ComputeFees returns the fees of the transaction at the given base fee per gas.
+// A gas price below the base fee pays no tip, such a transaction is not valid.
+func (tx AgnosticTransaction) ComputeFees(baseFeePerGas uint64) TransactionFees {
+	baseFee := new(big.Int).SetUint64(baseFeePerGas)
+	gasUsed := new(big.Int).SetUint64(tx.Gas)
+	fees := TransactionFees{
+		Tip:          new(big.Int),
+		BurntFee:     new(big.Int).Mul(baseFee, gasUsed),
+		BlobBurntFee: new(big.Int),
+	}
+	if tx.GasPrice != nil && tx.GasPrice.Cmp(baseFee) > 0 {
+		fees.Tip.Sub(tx.GasPrice, baseFee)
+		fees.Tip.Mul(fees.Tip, gasUsed)
+	}
+	if tx.BlobGasPrice != nil {
+		fees.BlobBurntFee.Mul(tx.BlobGasPrice, new(big.Int).SetUint64(tx.BlobGasUsed))
+	}
+	return fees
+}
+
+// copyBig returns a copy of the value, zero when nil
+func copyBig(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(v)
+}
@@ -0,0 +1,160 @@
+package spec_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/migalabs/goteth/pkg/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSetCodeTransaction(t *testing.T) {
+	chainId := uint64(560048) // does not fit in a byte
+	sender, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	authority, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	delegate := common.HexToAddress("0x63c0c19a282a1B52b07dD5a65b58948A07DAE32B")
+
+	auth, err := types.SignSetCode(authority, types.SetCodeAuthorization{
+		ChainID: *uint256.NewInt(chainId),
+		Address: delegate,
+		Nonce:   7,
+	})
+	require.NoError(t, err)
+	invalid := types.SetCodeAuthorization{Address: delegate, V: 5}
+
+	signer := types.NewPragueSigner(new(big.Int).SetUint64(chainId))
+	tx, err := types.SignNewTx(sender, signer, &types.SetCodeTx{
+		ChainID:   uint256.NewInt(chainId),
+		Nonce:     1,
+		GasTipCap: uint256.NewInt(1),
+		GasFeeCap: uint256.NewInt(10),
+		Gas:       100000,
+		To:        crypto.PubkeyToAddress(sender.PublicKey),
+		Value:     uint256.NewInt(0),
+		AuthList:  []types.SetCodeAuthorization{auth, invalid},
+	})
+	require.NoError(t, err)
+
+	parsed, err := spec.ParseTransactionFromReceipt(tx, nil, 100, 10, 0, 3)
+	require.NoError(t, err)
+	assert.Equal(t, chainId, parsed.ChainId)
+	assert.Equal(t, uint8(types.SetCodeTxType), parsed.TxType)
+	assert.Equal(t, crypto.PubkeyToAddress(sender.PublicKey), parsed.From)
+	require.Len(t, parsed.Authorizations, 2)
+
+	first := parsed.Authorizations[0]
+	assert.True(t, first.Valid)
+	assert.Equal(t, crypto.PubkeyToAddress(authority.PublicKey), first.Authority)
+	assert.Equal(t, delegate, first.Address)
+	assert.Equal(t, uint64(7), first.Nonce)
+	assert.Equal(t, new(big.Int).SetUint64(chainId), first.ChainId)
+	assert.Equal(t, uint64(3), first.TxIdx)
+	assert.Equal(t, uint64(0), first.Index)
+
+	second := parsed.Authorizations[1]
+	assert.False(t, second.Valid)
+	assert.Equal(t, common.Address{}, second.Authority)
+	assert.Equal(t, uint64(1), second.Index)
+}
+
+func TestParseTransactionWithHugeFees(t *testing.T) {
+	chainId := big.NewInt(1)
+	sender, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	// caps far above 64 bits are valid in a transaction, only the balance check rejects them
+	feeCap, _ := uint256.FromDecimal("1000000000000000000000000000000")
+	blobFeeCap, _ := uint256.FromDecimal("50000000000000000000000")
+
+	tx, err := types.SignNewTx(sender, types.NewCancunSigner(chainId), &types.BlobTx{
+		ChainID:    uint256.MustFromBig(chainId),
+		Nonce:      1,
+		GasTipCap:  feeCap,
+		GasFeeCap:  feeCap,
+		Gas:        21000,
+		To:         common.Address{},
+		Value:      uint256.NewInt(0),
+		BlobFeeCap: blobFeeCap,
+		BlobHashes: []common.Hash{{0x01}},
+	})
+	require.NoError(t, err)
+
+	effectiveGasPrice, _ := new(big.Int).SetString("20000000000000000000", 10) // above max uint64
+	blobGasPrice, _ := new(big.Int).SetString("30000000000000000000", 10)
+	receipt := &types.Receipt{
+		TxHash:            tx.Hash(),
+		GasUsed:           21000,
+		EffectiveGasPrice: effectiveGasPrice,
+		BlobGasUsed:       131072,
+		BlobGasPrice:      blobGasPrice,
+	}
+
+	parsed, err := spec.ParseTransactionFromReceipt(tx, receipt, 100, 10, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, feeCap.ToBig(), parsed.GasTipCap)
+	assert.Equal(t, feeCap.ToBig(), parsed.GasFeeCap)
+	assert.Equal(t, effectiveGasPrice, parsed.GasPrice)
+	assert.Equal(t, blobGasPrice, parsed.BlobGasPrice)
+	assert.Equal(t, blobFeeCap.ToBig(), parsed.BlobGasFeeCap)
+
+	fees := parsed.ComputeFees(7)
+	assert.Equal(t, "419999999999999999853000", fees.Tip.String())
+	assert.Equal(t, "147000", fees.BurntFee.String())
+	assert.Equal(t, "3932160000000000000000000", fees.BlobBurntFee.String())
+}
+
+func TestComputeFees(t *testing.T) {
+	tx := spec.AgnosticTransaction{Gas: 21000, GasPrice: big.NewInt(5)}
+
+	// below the base fee no tip is paid, the burn only depends on the base fee
+	fees := tx.ComputeFees(10)
+	assert.Equal(t, "0", fees.Tip.String())
+	assert.Equal(t, "210000", fees.BurntFee.String())
+	assert.Equal(t, "0", fees.BlobBurntFee.String())
+
+	// base fee and gas used at their maximum do not wrap around
+	tx = spec.AgnosticTransaction{Gas: math.MaxUint64, GasPrice: new(big.Int).SetUint64(math.MaxUint64)}
+	fees = tx.ComputeFees(math.MaxUint64)
+	assert.Equal(t, "0", fees.Tip.String())
+	assert.Equal(t, "340282366920938463426481119284349108225", fees.BurntFee.String())
+
+	// missing prices count as zero
+	tx = spec.AgnosticTransaction{Gas: 21000}
+	fees = tx.ComputeFees(1)
+	assert.Equal(t, "0", fees.Tip.String())
+	assert.Equal(t, "21000", fees.BurntFee.String())
+}
+
+func TestBlockFees(t *testing.T) {
+	block := spec.AgnosticBlock{Slot: 1}
+	_, err := block.BlockFees()
+	assert.Error(t, err)
+
+	gasPrice, _ := new(big.Int).SetString("1000000000000000000000", 10) // 1000 ETH per gas
+	block.ExecutionPayload.BaseFeePerGas = 1_000_000_000
+	block.ExecutionPayload.AgnosticTransactions = []spec.AgnosticTransaction{
+		{Gas: 21000, GasPrice: gasPrice},
+		{Gas: 50000, GasPrice: big.NewInt(500_000_000)}, // below the base fee
+		{Gas: 21000, GasPrice: big.NewInt(3_000_000_000), BlobGasUsed: 131072, BlobGasPrice: big.NewInt(2)},
+	}
+
+	fees, err := block.BlockFees()
+	require.NoError(t, err)
+	assert.Equal(t, "21000000000021000000000000", fees.Tips.String())
+	assert.Equal(t, "92000000000000", fees.BurntFees.String())
+	assert.Equal(t, "262144", fees.BlobBurntFees.String())
+
+	block.ExecutionPayload.AgnosticTransactions = block.ExecutionPayload.AgnosticTransactions[1:]
+	fees, err = block.BlockFees()
+	require.NoError(t, err)
+	assert.Equal(t, "42000000000000", fees.Tips.String())
+	assert.Equal(t, "71000000000000", fees.BurntFees.String())
+	assert.Equal(t, "262144", fees.BlobBurntFees.String())
+}
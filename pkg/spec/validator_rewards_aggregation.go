@@ -4,8 +4,12 @@ import (
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
+// ValidatorRewardsAggregation sums the rewards of a validator, or of the validators of
+// an entity, over a window of epochs. Counters are wide enough for entities.
 type ValidatorRewardsAggregation struct {
 	ValidatorIndex                      phase0.ValidatorIndex
+	Entity                              string // set instead of the index when aggregating an entity
+	Validators                          uint64 // validators aggregated, 1 for a validator
 	StartEpoch                          phase0.Epoch
 	EndEpoch                            phase0.Epoch // Inclusive
 	Reward                              int64        // it can be negative
@@ -13,25 +17,34 @@ type ValidatorRewardsAggregation struct {
 	MaxAttestationReward                phase0.Gwei
 	MaxSyncCommitteeReward              phase0.Gwei
 	BaseReward                          phase0.Gwei
-	InSyncCommitteeCount                uint16
-	SyncCommitteeParticipationsIncluded uint16
-	AttestationsIncluded                uint16
-	MissingSourceCount                  uint16
-	MissingTargetCount                  uint16
-	MissingHeadCount                    uint16
+	InSyncCommitteeCount                uint64
+	SyncCommitteeParticipationsIncluded uint64
+	AttestationsIncluded                uint64
+	MissingSourceCount                  uint64
+	MissingTargetCount                  uint64
+	MissingHeadCount                    uint64
 	ProposerApiReward                   phase0.Gwei
 	ProposerManualReward                phase0.Gwei
-	InclusionDelaySum                   uint32
+	InclusionDelaySum                   uint64
 }
 
 func NewValidatorRewardsAggregation(validatorIndex phase0.ValidatorIndex, startEpoch phase0.Epoch, endEpoch phase0.Epoch) *ValidatorRewardsAggregation {
 	return &ValidatorRewardsAggregation{
 		ValidatorIndex: validatorIndex,
+		Validators:     1,
 		StartEpoch:     startEpoch,
 		EndEpoch:       endEpoch,
 	}
 }
 
+func NewEntityRewardsAggregation(entity string, startEpoch phase0.Epoch, endEpoch phase0.Epoch) *ValidatorRewardsAggregation {
+	return &ValidatorRewardsAggregation{
+		Entity:     entity,
+		StartEpoch: startEpoch,
+		EndEpoch:   endEpoch,
+	}
+}
+
 func (f ValidatorRewardsAggregation) Type() ModelType {
 	return ValidatorRewardsAggregationModel
 }
@@ -68,7 +81,7 @@ func (f *ValidatorRewardsAggregation) Aggregate(valRewards ValidatorRewards) {
 	if valRewards.InSyncCommittee {
 		f.InSyncCommitteeCount++
 	}
-	f.SyncCommitteeParticipationsIncluded += uint16(valRewards.SyncCommitteeParticipationsIncluded)
+	f.SyncCommitteeParticipationsIncluded += uint64(valRewards.SyncCommitteeParticipationsIncluded)
 	if valRewards.AttestationIncluded {
 		f.AttestationsIncluded++
 	}
@@ -83,5 +96,24 @@ func (f *ValidatorRewardsAggregation) Aggregate(valRewards ValidatorRewards) {
 	}
 	f.ProposerApiReward += valRewards.ProposerApiReward
 	f.ProposerManualReward += valRewards.ProposerManualReward
-	f.InclusionDelaySum += uint32(valRewards.InclusionDelay)
+	f.InclusionDelaySum += uint64(valRewards.InclusionDelay)
+}
+
+// Merge adds the aggregation of another validator over the same window.
+func (f *ValidatorRewardsAggregation) Merge(other ValidatorRewardsAggregation) {
+	f.Validators += other.Validators
+	f.Reward += other.Reward
+	f.MaxReward += other.MaxReward
+	f.MaxAttestationReward += other.MaxAttestationReward
+	f.MaxSyncCommitteeReward += other.MaxSyncCommitteeReward
+	f.BaseReward += other.BaseReward
+	f.InSyncCommitteeCount += other.InSyncCommitteeCount
+	f.SyncCommitteeParticipationsIncluded += other.SyncCommitteeParticipationsIncluded
+	f.AttestationsIncluded += other.AttestationsIncluded
+	f.MissingSourceCount += other.MissingSourceCount
+	f.MissingTargetCount += other.MissingTargetCount
+	f.MissingHeadCount += other.MissingHeadCount
+	f.ProposerApiReward += other.ProposerApiReward
+	f.ProposerManualReward += other.ProposerManualReward
+	f.InclusionDelaySum += other.InclusionDelaySum
 }
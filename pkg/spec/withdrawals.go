@@ -12,6 +12,7 @@ type Withdrawal struct {
 	ValidatorIndex phase0.ValidatorIndex
 	Address        bellatrix.ExecutionAddress
 	Amount         phase0.Gwei
+	BlockRoot      phase0.Root
 }
 
 func (f Withdrawal) Type() ModelType {
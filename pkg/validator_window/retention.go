@@ -0,0 +1,72 @@
+package validatorwindow
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/migalabs/goteth/pkg/db"
+	"github.com/migalabs/goteth/pkg/spec"
+)
+
+const (
+	secondsPerDay = 24 * 60 * 60
+
+	valRewardsTable = "t_validator_rewards_summary"
+)
+
+// EpochsPerDay returns the epochs of a day given the slot duration of the network,
+// e.g. 225 with 12 second slots.
+func EpochsPerDay(secondsPerSlot uint64) uint64 {
+	return secondsPerDay / (secondsPerSlot * spec.SlotsPerEpoch)
+}
+
+// RetentionPolicy keeps the last Epochs epochs of a table.
+type RetentionPolicy struct {
+	Table  string
+	Epochs phase0.Epoch
+}
+
+// ParseRetention parses a comma separated list of table=retention, the retention being
+// a number of epochs or of days with the d suffix, e.g. "t_transactions=30d,t_head_events=1000".
+// Days are converted with the epochs per day of the network.
+func ParseRetention(input string, epochsPerDay uint64) ([]RetentionPolicy, error) {
+	policies := make([]RetentionPolicy, 0)
+	if strings.TrimSpace(input) == "" {
+		return policies, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(input, ",") {
+		table, value, found := strings.Cut(strings.TrimSpace(item), "=")
+		if !found {
+			return nil, fmt.Errorf("could not parse retention: %s", item)
+		}
+		if !db.SupportsRetention(table) {
+			return nil, fmt.Errorf("table %s does not support retention, supported: %s",
+				table, strings.Join(db.RetentionTables(), ","))
+		}
+		if seen[table] {
+			return nil, fmt.Errorf("duplicated retention for table %s", table)
+		}
+		seen[table] = true
+
+		multiplier := uint64(1)
+		if strings.HasSuffix(value, "d") {
+			multiplier = epochsPerDay
+			value = strings.TrimSuffix(value, "d")
+		}
+		amount, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || amount == 0 {
+			return nil, fmt.Errorf("could not parse retention of table %s: %s", table, value)
+		}
+		policies = append(policies, RetentionPolicy{
+			Table:  table,
+			Epochs: phase0.Epoch(amount * multiplier),
+		})
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Table < policies[j].Table })
+	return policies, nil
+}
@@ -0,0 +1,43 @@
+package validatorwindow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetention(t *testing.T) {
+	policies, err := ParseRetention("t_transactions=30d, t_head_events=1000,t_entity_rewards_aggregation=90d", 225)
+	require.NoError(t, err)
+	assert.Equal(t, []RetentionPolicy{
+		{Table: "t_entity_rewards_aggregation", Epochs: 90 * 225},
+		{Table: "t_head_events", Epochs: 1000},
+		{Table: "t_transactions", Epochs: 30 * 225},
+	}, policies)
+
+	// 5 second slots
+	policies, err = ParseRetention("t_transactions=30d", EpochsPerDay(5))
+	require.NoError(t, err)
+	assert.Equal(t, []RetentionPolicy{{Table: "t_transactions", Epochs: 30 * 540}}, policies)
+
+	policies, err = ParseRetention("", 225)
+	require.NoError(t, err)
+	assert.Empty(t, policies)
+
+	for _, input := range []string{
+		"t_transactions",
+		"t_transactions=0",
+		"t_transactions=ten",
+		"t_genesis=10",
+		"t_transactions=1,t_transactions=2",
+	} {
+		_, err := ParseRetention(input, 225)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestEpochsPerDay(t *testing.T) {
+	assert.Equal(t, uint64(225), EpochsPerDay(12))
+	assert.Equal(t, uint64(540), EpochsPerDay(5))
+}
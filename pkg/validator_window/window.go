@@ -23,11 +23,13 @@ var (
 
 type ValidatorWindowRunner struct {
 	ctx              context.Context
-	dbClient         *db.DBService  // client to communicate with psql
-	eventsObj        events.Events  // object to receive signals from beacon node (needed to trigger the deletes)
-	stop             bool           // used to know if the tool should stop
-	windowEpochSize  int            // number of epochs of data to maintain in the database
-	routineSyncGroup sync.WaitGroup // to check if the routine is running
+	dbClient         *db.DBService     // client to communicate with psql
+	eventsObj        events.Events     // object to receive signals from beacon node (needed to trigger the deletes)
+	stop             bool              // used to know if the tool should stop
+	policies         []RetentionPolicy // epochs of data to maintain in the database per table
+	secondsPerSlot   uint64            // slot duration of the network, to convert epochs into timestamps
+	dryRun           bool              // only report what would be removed
+	routineSyncGroup sync.WaitGroup    // to check if the routine is running
 }
 
 func NewValidatorWindow(
@@ -59,17 +61,54 @@ func NewValidatorWindow(
 		}, errors.Wrap(err, "unable to generate API Client.")
 	}
 
+	secondsPerSlot, err := cli.RequestSecondsPerSlot()
+	if err != nil {
+		return &ValidatorWindowRunner{
+			ctx: pCtx,
+		}, errors.Wrap(err, "unable to read the seconds per slot.")
+	}
+
+	policies, err := ParseRetention(iConfig.Retention, EpochsPerDay(secondsPerSlot))
+	if err != nil {
+		return &ValidatorWindowRunner{
+			ctx: pCtx,
+		}, errors.Wrap(err, "unable to read retention.")
+	}
+	// the validator rewards window applies unless overridden by a retention
+	legacyWindow := true
+	for _, policy := range policies {
+		if policy.Table == valRewardsTable {
+			legacyWindow = false
+		}
+	}
+	if legacyWindow {
+		policies = append(policies, RetentionPolicy{
+			Table:  valRewardsTable,
+			Epochs: phase0.Epoch(iConfig.NumEpochs),
+		})
+	}
+
 	return &ValidatorWindowRunner{
 		ctx:              pCtx,
 		dbClient:         idbClient,
 		eventsObj:        events.NewEventsObj(pCtx, cli),
-		windowEpochSize:  iConfig.NumEpochs,
+		policies:         policies,
+		secondsPerSlot:   secondsPerSlot,
+		dryRun:           iConfig.DryRun,
 		routineSyncGroup: sync.WaitGroup{},
 	}, nil
 }
 
 func (s *ValidatorWindowRunner) Run() {
 
+	if s.dryRun {
+		if err := s.applyRetention(); err != nil {
+			log.Errorf("could not plan the retention: %s", err)
+		}
+		s.EndProcesses()
+		return
+	}
+
 	s.eventsObj.SubscribeToFinalizedCheckpointEvents() // every new finalized checkpoint, trigger deletes
 	s.eventsObj.SubscribeToHeadEvents()                // for monitorization
 	ticker := time.NewTicker(utils.RoutineFlushTimeout)
@@ -81,27 +120,12 @@ func (s *ValidatorWindowRunner) Run() {
 
 		case <-s.eventsObj.FinalizedChan:
 
-			dbHeadEpoch, err := s.dbClient.RetrieveLastEpoch()
+			err := s.applyRetention()
 			if err != nil {
 				log.Errorf("could not detect current head epoch in database: %s", err)
 				s.EndProcesses()
 				return
 			}
-
-			if dbHeadEpoch < phase0.Epoch(s.windowEpochSize) {
-				log.Infof("database head epoch: %d is less than window epoch size: %d", dbHeadEpoch, s.windowEpochSize)
-				continue
-			}
-			windowLowerEpochBoundary := dbHeadEpoch - phase0.Epoch(s.windowEpochSize)
-
-			log.Infof("database head epoch: %d", dbHeadEpoch)
-			log.Infof("deleting validator rewards from %d epoch backwards", windowLowerEpochBoundary)
-			err = s.dbClient.DeleteValidatorRewardsUntil(windowLowerEpochBoundary)
-			if err != nil {
-				log.Errorf("could not delete the validators: %s", err)
-				s.EndProcesses()
-				return
-			}
 		case <-ticker.C:
 			if s.stop {
 				return
@@ -110,6 +134,45 @@ func (s *ValidatorWindowRunner) Run() {
 	}
 }
 
+// applyRetention removes, for every policy, the rows older than the policy epochs from the
+// database head backwards. In dry-run mode it only logs what would be removed.
+// Failures on a single table are logged and do not stop the other ones.
+func (s *ValidatorWindowRunner) applyRetention() error {
+	dbHeadEpoch, err := s.dbClient.RetrieveLastEpoch()
+	if err != nil {
+		return err
+	}
+	log.Infof("database head epoch: %d", dbHeadEpoch)
+
+	totalBytes := uint64(0)
+	for _, policy := range s.policies {
+		if dbHeadEpoch < policy.Epochs {
+			log.Infof("%s: database head epoch %d is less than the retention of %d epochs", policy.Table, dbHeadEpoch, policy.Epochs)
+			continue
+		}
+		untilEpoch := dbHeadEpoch - policy.Epochs
+
+		plan, err := s.dbClient.PlanRetention(policy.Table, untilEpoch, s.secondsPerSlot)
+		if err != nil {
+			log.Errorf("could not plan the retention of %s: %s", policy.Table, err)
+			continue
+		}
+		totalBytes += plan.EstimatedBytes()
+		if s.dryRun {
+			log.Infof("[dry-run] %s", plan)
+			continue
+		}
+
+		log.Infof("removing rows of %s from %d epoch backwards: %s", policy.Table, untilEpoch, plan)
+		err = s.dbClient.ApplyRetention(plan)
+		if err != nil {
+			log.Errorf("could not apply the retention of %s: %s", policy.Table, err)
+		}
+	}
+	log.Infof("retention frees ~%.2f MB", float64(totalBytes)/(1<<20))
+	return nil
+}
+
 func (s *ValidatorWindowRunner) Close() {
 	s.stop = true
 	s.routineSyncGroup.Wait()